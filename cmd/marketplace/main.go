@@ -6,6 +6,7 @@ import (
 	"marketplace-api/internal/api/routes"
 	"marketplace-api/internal/config"
 	"marketplace-api/internal/database"
+	"marketplace-api/internal/database/migrations"
 	"marketplace-api/internal/logger"
 	"net/http"
 	"os"
@@ -38,6 +39,24 @@ func main() {
 	}
 
 	log := logger.New()
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			if err := runMigrate(cfg, os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Migration failed: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "users":
+			if err := runUsers(cfg, os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Command failed: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	log.Info("Starting marketplace API server")
 	log.Info("Configuration loaded successfully")
 
@@ -50,16 +69,30 @@ func main() {
 
 	log.Info("Database connection established")
 
-	if err := database.CreateTables(db); err != nil {
-		log.Error("Failed to create database tables", "error", err)
-		os.Exit(1)
-	}
+	if cfg.Database.AutoMigrate {
+		migrator, err := database.NewMigrator(db, migrations.FS)
+		if err != nil {
+			log.Error("Failed to load migrations", "error", err)
+			os.Exit(1)
+		}
 
-	log.Info("Database tables initialized")
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			log.Error("Failed to apply migrations", "error", err)
+			os.Exit(1)
+		}
+
+		log.Info("Database migrations applied", "count", len(applied))
+	}
 
 	gin.SetMode(gin.DebugMode)
 	router := gin.New()
 
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Error("Invalid trusted proxies", "error", err)
+		os.Exit(1)
+	}
+
 	router.Use(gin.LoggerWithConfig(gin.LoggerConfig{
 		Output: os.Stdout,
 		Formatter: func(param gin.LogFormatterParams) string {
@@ -76,7 +109,13 @@ func main() {
 
 	router.Use(gin.Recovery())
 
-	routes.SetupRoutes(router, db, cfg)
+	ctx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	if err := routes.SetupRoutes(ctx, router, db, cfg, log); err != nil {
+		log.Error("Failed to set up routes", "error", err)
+		os.Exit(1)
+	}
 
 	server := &http.Server{
 		Addr:         cfg.GetServerAddress(),
@@ -109,10 +148,12 @@ func main() {
 
 	log.Info("Server shutting down...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	stopBackground()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := server.Shutdown(ctx); err != nil {
+	if err := server.Shutdown(shutdownCtx); err != nil {
 		log.Error("Server forced to shutdown", "error", err)
 	}
 
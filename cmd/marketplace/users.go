@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"marketplace-api/internal/config"
+	"marketplace-api/internal/database"
+	"marketplace-api/internal/database/postgres"
+	"marketplace-api/pkg/rbac"
+)
+
+const usersUsage = `usage: marketplace users <command>
+
+commands:
+  set-role <login> <role>   assign role user, moderator or admin`
+
+// runUsers выполняет подкоманду users. Нужна, в частности, чтобы назначить первого администратора
+func runUsers(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n%s", usersUsage)
+	}
+
+	switch args[0] {
+	case "set-role":
+		if len(args) != 3 {
+			return fmt.Errorf("invalid arguments\n%s", usersUsage)
+		}
+
+		role := rbac.Role(args[2])
+		if !role.Valid() {
+			return fmt.Errorf("unknown role %q", args[2])
+		}
+
+		db, err := database.NewConnection(cfg.GetDatabaseURL())
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		userRepo := postgres.NewUserRepository(db)
+		tokenRevocationRepo := postgres.NewTokenRevocationRepository(db)
+
+		user, err := userRepo.GetUserByLogin(args[1])
+		if err != nil {
+			return err
+		}
+
+		if _, err := userRepo.UpdateUserRole(user.ID, role); err != nil {
+			return err
+		}
+
+		if err := tokenRevocationRepo.RevokeUserTokens(user.ID); err != nil {
+			return err
+		}
+
+		fmt.Printf("user %s now has role %s\n", user.Login, role)
+
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usersUsage)
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/internal/models"
+	"marketplace-api/internal/service"
+	"marketplace-api/pkg/middleware"
+	"marketplace-api/pkg/utils"
+)
+
+type AccountHandler struct {
+	accountService service.AccountServiceInterface
+}
+
+func NewAccountHandler(accountService service.AccountServiceInterface) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// ExportData выгружает персональные данные текущего пользователя
+// @Summary Выгрузка персональных данных
+// @Description Возвращает учетную запись, профиль, объявления, сеансы, API-ключи, привязанные учетные записи провайдеров, загруженные файлы (со ссылками на скачивание) и журнал безопасности в виде ZIP-архива с JSON-файлами или одного JSON-файла
+// @Tags users
+// @Security Bearer
+// @Produce application/zip
+// @Produce json
+// @Param format query string false "Формат выгрузки" Enums(zip, json) default(zip)
+// @Success 200 {file} file
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /users/me/export [get]
+func (h *AccountHandler) ExportData(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	format := c.DefaultQuery("format", "zip")
+	if format != "zip" && format != "json" {
+		utils.BadRequest(c, "Format must be zip or json")
+		return
+	}
+
+	export, err := h.accountService.ExportData(userID)
+	if err != nil {
+		utils.InternalError(c, "Failed to export data")
+		return
+	}
+
+	filename := fmt.Sprintf("marketplace-export-%d-%s", userID, export.ExportedAt.Format("20060102"))
+
+	var content []byte
+	contentType := "application/json"
+	if format == "zip" {
+		content, err = exportArchive(export)
+		contentType = "application/zip"
+	} else {
+		content, err = json.MarshalIndent(export, "", "  ")
+	}
+	if err != nil {
+		utils.InternalError(c, "Failed to export data")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+	c.Data(http.StatusOK, contentType, content)
+}
+
+// DeleteAccount назначает удаление аккаунта текущего пользователя
+// @Summary Удаление аккаунта
+// @Description Назначает удаление аккаунта по истечении ACCOUNT_DELETION_GRACE_PERIOD и завершает все сеансы. До удаления объявления скрыты, вход в аккаунт отменяет удаление. Пароль обязателен, если он задан
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body models.DeleteAccountRequest true "Текущий пароль"
+// @Success 202 {object} utils.SuccessResponse{data=models.AccountDeletion}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /users/me [delete]
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	var req models.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	deletion, err := h.accountService.DeleteAccount(userID, req, clientInfo(c))
+	if err != nil {
+		if err.Error() == "invalid password" {
+			utils.Unauthorized(c, "Invalid password")
+			return
+		}
+
+		utils.InternalError(c, "Failed to delete account")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusAccepted, deletion, "Account deletion scheduled")
+}
+
+// exportArchive упаковывает выгрузку в ZIP-архив: по JSON-файлу на каждый раздел
+func exportArchive(export *models.UserDataExport) ([]byte, error) {
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"public_profile.json", export.PublicProfile},
+		{"listings.json", export.Listings},
+		{"sessions.json", export.Sessions},
+		{"api_keys.json", export.APIKeys},
+		{"identities.json", export.Identities},
+		{"uploads.json", export.Uploads},
+		{"security_events.json", export.SecurityEvents},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	for _, file := range files {
+		w, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := json.MarshalIndent(file.data, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := w.Write(content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"marketplace-api/internal/models"
+	mockservice "marketplace-api/internal/service/mocks"
+)
+
+func testDataExport() *models.UserDataExport {
+	return &models.UserDataExport{
+		ExportedAt: time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+		Profile: models.User{
+			ID:        1,
+			Login:     "artificial00",
+			Email:     "user@example.com",
+			CreatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+			UpdatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+		},
+		Listings: []models.Listing{
+			{
+				ID:          7,
+				Title:       "Bike",
+				Description: "Road bike",
+				Price:       150,
+				CategoryID:  3,
+				UserID:      1,
+				UserLogin:   "artificial00",
+				IsOwner:     true,
+				CreatedAt:   time.Date(2025, 7, 21, 20, 0, 0, 0, time.UTC),
+				UpdatedAt:   time.Date(2025, 7, 21, 20, 0, 0, 0, time.UTC),
+			},
+		},
+		Sessions:   []models.Session{},
+		APIKeys:    []models.APIKey{},
+		Identities: []models.UserIdentity{},
+		Uploads: []models.Upload{
+			{
+				ID:          5,
+				UserID:      1,
+				ContentHash: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+				FileKey:     "9f/9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08.png",
+				ContentType: "image/png",
+				Size:        2048,
+				Width:       intPtr(640),
+				Height:      intPtr(480),
+				CreatedAt:   time.Date(2025, 7, 21, 20, 5, 0, 0, time.UTC),
+				URL:         "http://localhost:8080/api/uploads/files/9f/9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08.png?expires=1753182000&signature=abc",
+			},
+		},
+		SecurityEvents: []models.SecurityEvent{
+			{
+				ID:        4,
+				Type:      models.SecurityEventLoginSucceeded,
+				UserID:    1,
+				IPAddress: "203.0.113.7",
+				UserAgent: "Mozilla/5.0",
+				Details:   map[string]string{"method": "password"},
+				CreatedAt: time.Date(2025, 7, 22, 9, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func TestAccountHandler_ExportData(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAccountService)
+
+	testTable := []struct {
+		name                 string
+		userID               interface{}
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedContentType  string
+		expectedFilename     string
+		expectedResponseBody string
+	}{
+		{
+			name:   "JSON",
+			userID: 1,
+			query:  "?format=json",
+			mockBehavior: func(s *mockservice.MockAccountService) {
+				s.EXPECT().ExportData(1).Return(testDataExport(), nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "application/json",
+			expectedFilename:     `attachment; filename="marketplace-export-1-20250722.json"`,
+			expectedResponseBody: `{"exported_at":"2025-07-22T10:00:00Z","profile":{"id":1,"login":"artificial00","email":"user@example.com","created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-21T19:56:37Z"},"public_profile":{"display_name":"","bio":"","avatar_url":null,"location":"","phone":"","show_email":false,"show_phone":false,"preferred_contact":""},"listings":[{"id":7,"title":"Bike","description":"Road bike","image_url":null,"price":150,"category_id":3,"user_id":1,"user_login":"artificial00","is_owner":true,"created_at":"2025-07-21T20:00:00Z","updated_at":"2025-07-21T20:00:00Z"}],"sessions":[],"api_keys":[],"identities":[],"uploads":[{"id":5,"content_hash":"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08","file_key":"9f/9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08.png","content_type":"image/png","size":2048,"width":640,"height":480,"created_at":"2025-07-21T20:05:00Z","url":"http://localhost:8080/api/uploads/files/9f/9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08.png?expires=1753182000&signature=abc"}],"security_events":[{"id":4,"type":"login_succeeded","user_id":1,"ip_address":"203.0.113.7","user_agent":"Mozilla/5.0","details":{"method":"password"},"created_at":"2025-07-22T09:00:00Z"}]}`,
+		},
+		{
+			name:                 "Invalid format",
+			userID:               1,
+			query:                "?format=xml",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Format must be zip or json"}`,
+		},
+		{
+			name:                 "User not found in context",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:   "Internal server error",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockAccountService) {
+				s.EXPECT().ExportData(1).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to export data"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := serveAccountExport(t, testCase.userID, testCase.query, testCase.mockBehavior)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+			if testCase.expectedContentType != "" {
+				assert.Equal(t, testCase.expectedContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, testCase.expectedFilename, w.Header().Get("Content-Disposition"))
+				assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+			}
+		})
+	}
+}
+
+func TestAccountHandler_ExportData_Zip(t *testing.T) {
+	w := serveAccountExport(t, 1, "", func(s *mockservice.MockAccountService) {
+		s.EXPECT().ExportData(1).Return(testDataExport(), nil)
+	})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="marketplace-export-1-20250722.zip"`, w.Header().Get("Content-Disposition"))
+
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, file := range archive.File {
+		r, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+		files[file.Name] = string(content)
+	}
+
+	assert.Len(t, files, 8)
+	assert.JSONEq(t, `{"id":1,"login":"artificial00","email":"user@example.com","created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-21T19:56:37Z"}`, files["profile.json"])
+	assert.JSONEq(t, `{"display_name":"","bio":"","avatar_url":null,"location":"","phone":"","show_email":false,"show_phone":false,"preferred_contact":""}`, files["public_profile.json"])
+	assert.JSONEq(t, `[{"id":7,"title":"Bike","description":"Road bike","image_url":null,"price":150,"category_id":3,"user_id":1,"user_login":"artificial00","is_owner":true,"created_at":"2025-07-21T20:00:00Z","updated_at":"2025-07-21T20:00:00Z"}]`, files["listings.json"])
+	assert.JSONEq(t, `[]`, files["sessions.json"])
+	assert.JSONEq(t, `[]`, files["api_keys.json"])
+	assert.JSONEq(t, `[]`, files["identities.json"])
+	assert.JSONEq(t, `[{"id":5,"content_hash":"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08","file_key":"9f/9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08.png","content_type":"image/png","size":2048,"width":640,"height":480,"created_at":"2025-07-21T20:05:00Z","url":"http://localhost:8080/api/uploads/files/9f/9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08.png?expires=1753182000&signature=abc"}]`, files["uploads.json"])
+	assert.JSONEq(t, `[{"id":4,"type":"login_succeeded","user_id":1,"ip_address":"203.0.113.7","user_agent":"Mozilla/5.0","details":{"method":"password"},"created_at":"2025-07-22T09:00:00Z"}]`, files["security_events.json"])
+}
+
+func serveAccountExport(t *testing.T, userID interface{}, query string, behavior func(s *mockservice.MockAccountService)) *httptest.ResponseRecorder {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	accountService := mockservice.NewMockAccountService(c)
+
+	if behavior != nil {
+		behavior(accountService)
+	}
+
+	handler := NewAccountHandler(accountService)
+
+	w := httptest.NewRecorder()
+
+	gin.SetMode(gin.TestMode)
+	ctx, r := gin.CreateTestContext(w)
+
+	r.Use(func(ctx *gin.Context) {
+		if userID != nil {
+			ctx.Set("user_id", userID)
+		}
+	})
+
+	r.GET("/users/me/export", handler.ExportData)
+
+	ctx.Request, _ = http.NewRequest("GET", "/users/me/export"+query, nil)
+
+	r.ServeHTTP(w, ctx.Request)
+
+	return w
+}
+
+func TestAccountHandler_DeleteAccount(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAccountService, req models.DeleteAccountRequest)
+
+	testTable := []struct {
+		name                 string
+		userID               interface{}
+		requestBody          string
+		request              models.DeleteAccountRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			userID:      1,
+			requestBody: `{"password":"password123"}`,
+			request:     models.DeleteAccountRequest{Password: "password123"},
+			mockBehavior: func(s *mockservice.MockAccountService, req models.DeleteAccountRequest) {
+				s.EXPECT().DeleteAccount(1, req, testClient).Return(&models.AccountDeletion{
+					DeletionScheduledAt: time.Date(2025, 8, 21, 10, 0, 0, 0, time.UTC),
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusAccepted,
+			expectedResponseBody: `{"message":"Account deletion scheduled","data":{"deletion_scheduled_at":"2025-08-21T10:00:00Z"}}`,
+		},
+		{
+			name:        "Account without password",
+			userID:      1,
+			requestBody: `{}`,
+			mockBehavior: func(s *mockservice.MockAccountService, req models.DeleteAccountRequest) {
+				s.EXPECT().DeleteAccount(1, req, testClient).Return(&models.AccountDeletion{
+					DeletionScheduledAt: time.Date(2025, 8, 21, 10, 0, 0, 0, time.UTC),
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusAccepted,
+			expectedResponseBody: `{"message":"Account deletion scheduled","data":{"deletion_scheduled_at":"2025-08-21T10:00:00Z"}}`,
+		},
+		{
+			name:                 "Invalid request format",
+			userID:               1,
+			requestBody:          `{"password":}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
+		},
+		{
+			name:        "Invalid password",
+			userID:      1,
+			requestBody: `{"password":"wrong"}`,
+			request:     models.DeleteAccountRequest{Password: "wrong"},
+			mockBehavior: func(s *mockservice.MockAccountService, req models.DeleteAccountRequest) {
+				s.EXPECT().DeleteAccount(1, req, testClient).Return(nil, errors.New("invalid password"))
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"Invalid password"}`,
+		},
+		{
+			name:                 "User not found in context",
+			requestBody:          `{"password":"password123"}`,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:        "Internal server error",
+			userID:      1,
+			requestBody: `{"password":"password123"}`,
+			request:     models.DeleteAccountRequest{Password: "password123"},
+			mockBehavior: func(s *mockservice.MockAccountService, req models.DeleteAccountRequest) {
+				s.EXPECT().DeleteAccount(1, req, testClient).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to delete account"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			accountService := mockservice.NewMockAccountService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(accountService, testCase.request)
+			}
+
+			handler := NewAccountHandler(accountService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+			})
+
+			r.DELETE("/users/me", handler.DeleteAccount)
+
+			ctx.Request, _ = http.NewRequest("DELETE", "/users/me", bytes.NewBufferString(testCase.requestBody))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+			setTestClient(ctx.Request)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
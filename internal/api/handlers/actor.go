@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"marketplace-api/internal/models"
+	"marketplace-api/pkg/middleware"
+)
+
+// currentActor собирает из контекста пользователя, от имени которого выполняется запрос
+func currentActor(c *gin.Context) (models.Actor, bool) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		return models.Actor{}, false
+	}
+
+	return models.Actor{
+		UserID: userID,
+		Role:   middleware.GetUserRole(c),
+	}, true
+}
+
+// clientInfo собирает сведения о клиенте для записи сеанса входа и журнала безопасности
+func clientInfo(c *gin.Context) models.ClientInfo {
+	return models.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		RequestID: middleware.GetRequestID(c),
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/internal/models"
+	"marketplace-api/internal/service"
+	"marketplace-api/pkg/utils"
+)
+
+type AdminHandler struct {
+	adminService service.AdminServiceInterface
+}
+
+func NewAdminHandler(adminService service.AdminServiceInterface) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// ListUsers получает список пользователей
+// @Summary Список пользователей
+// @Description Возвращает постраничный список пользователей. Доступно администраторам
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param role query string false "Фильтр по роли" Enums(user, moderator, admin)
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(20)
+// @Success 200 {object} utils.SuccessResponse{data=models.PaginatedUsers}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	var filter models.UsersFilter
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequest(c, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	users, err := h.adminService.ListUsers(filter)
+	if err != nil {
+		utils.InternalError(c, "Failed to get users")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, users, "")
+}
+
+// UpdateUserRole изменяет роль пользователя
+// @Summary Изменить роль пользователя
+// @Description Назначает пользователю роль user, moderator или admin. Доступно администраторам
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param request body models.UpdateUserRoleRequest true "Новая роль"
+// @Success 200 {object} utils.SuccessResponse{data=models.User}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/users/{id}/role [put]
+func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
+	actor, exists := currentActor(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	var req models.UpdateUserRoleRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format: "+err.Error())
+		return
+	}
+
+	user, err := h.adminService.UpdateUserRole(actor, id, req, clientInfo(c))
+	if err != nil {
+		if err.Error() == "user not found" {
+			utils.NotFound(c, "User not found")
+			return
+		}
+		if err.Error() == "invalid user ID" ||
+			err.Error() == "invalid role" ||
+			err.Error() == "cannot change own role" {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		utils.InternalError(c, "Failed to update user role")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, user, "User role updated successfully")
+}
+
+// UnlockUser снимает блокировку входа пользователя
+// @Summary Разблокировать вход пользователя
+// @Description Сбрасывает счетчик неудачных попыток входа и снимает временную блокировку. Доступно администраторам
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/users/{id}/unlock [post]
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	actor, exists := currentActor(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	if err := h.adminService.UnlockUser(actor, id, clientInfo(c)); err != nil {
+		if err.Error() == "user not found" {
+			utils.NotFound(c, "User not found")
+			return
+		}
+		if err.Error() == "invalid user ID" {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		utils.InternalError(c, "Failed to unlock user")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, nil, "User unlocked successfully")
+}
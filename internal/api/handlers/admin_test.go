@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"marketplace-api/internal/models"
+	mockservice "marketplace-api/internal/service/mocks"
+	"marketplace-api/pkg/rbac"
+)
+
+func TestAdminHandler_ListUsers(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAdminService, filter models.UsersFilter)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		filter               models.UsersFilter
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			query:  "?role=moderator&page=1&limit=10",
+			filter: models.UsersFilter{Role: rbac.RoleModerator, Page: 1, Limit: 10},
+			mockBehavior: func(s *mockservice.MockAdminService, filter models.UsersFilter) {
+				users := &models.PaginatedUsers{
+					Data: []models.User{
+						{
+							ID:        2,
+							Login:     "moderator",
+							Role:      rbac.RoleModerator,
+							CreatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+							UpdatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+						},
+					},
+					Total:      1,
+					Page:       1,
+					Limit:      10,
+					TotalPages: 1,
+				}
+				s.EXPECT().ListUsers(filter).Return(users, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":{"data":[{"id":2,"login":"moderator","role":"moderator","created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-21T19:56:37Z"}],"total":1,"page":1,"limit":10,"total_pages":1}}`,
+		},
+		{
+			name:                 "Invalid role filter",
+			query:                "?role=root",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid query parameters: Key: 'UsersFilter.Role' Error:Field validation for 'Role' failed on the 'oneof' tag"}`,
+		},
+		{
+			name:   "Internal server error",
+			filter: models.UsersFilter{},
+			mockBehavior: func(s *mockservice.MockAdminService, filter models.UsersFilter) {
+				s.EXPECT().ListUsers(filter).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to get users"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			adminService := mockservice.NewMockAdminService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(adminService, testCase.filter)
+			}
+
+			handler := NewAdminHandler(adminService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.GET("/admin/users", handler.ListUsers)
+
+			ctx.Request, _ = http.NewRequest("GET", "/admin/users"+testCase.query, nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestAdminHandler_UpdateUserRole(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAdminService, actor models.Actor, userID int, req models.UpdateUserRoleRequest)
+
+	admin := models.Actor{UserID: 1, Role: rbac.RoleAdmin}
+
+	testTable := []struct {
+		name                 string
+		userID               string
+		requestBody          string
+		request              models.UpdateUserRoleRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			userID:      "2",
+			requestBody: `{"role":"moderator"}`,
+			request:     models.UpdateUserRoleRequest{Role: rbac.RoleModerator},
+			mockBehavior: func(s *mockservice.MockAdminService, actor models.Actor, userID int, req models.UpdateUserRoleRequest) {
+				user := &models.User{
+					ID:        2,
+					Login:     "seller",
+					Role:      rbac.RoleModerator,
+					CreatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+					UpdatedAt: time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+				}
+				s.EXPECT().UpdateUserRole(actor, userID, req, testClient).Return(user, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"User role updated successfully","data":{"id":2,"login":"seller","role":"moderator","created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-22T10:00:00Z"}}`,
+		},
+		{
+			name:                 "Invalid user ID",
+			userID:               "abc",
+			requestBody:          `{"role":"moderator"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid user ID"}`,
+		},
+		{
+			name:                 "Unknown role",
+			userID:               "2",
+			requestBody:          `{"role":"root"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format: Key: 'UpdateUserRoleRequest.Role' Error:Field validation for 'Role' failed on the 'oneof' tag"}`,
+		},
+		{
+			name:        "Own role",
+			userID:      "1",
+			requestBody: `{"role":"user"}`,
+			request:     models.UpdateUserRoleRequest{Role: rbac.RoleUser},
+			mockBehavior: func(s *mockservice.MockAdminService, actor models.Actor, userID int, req models.UpdateUserRoleRequest) {
+				s.EXPECT().UpdateUserRole(actor, userID, req, testClient).Return(nil, errors.New("cannot change own role"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"cannot change own role"}`,
+		},
+		{
+			name:        "User not found",
+			userID:      "999",
+			requestBody: `{"role":"admin"}`,
+			request:     models.UpdateUserRoleRequest{Role: rbac.RoleAdmin},
+			mockBehavior: func(s *mockservice.MockAdminService, actor models.Actor, userID int, req models.UpdateUserRoleRequest) {
+				s.EXPECT().UpdateUserRole(actor, userID, req, testClient).Return(nil, errors.New("user not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"not_found", "message":"User not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			adminService := mockservice.NewMockAdminService(c)
+
+			if testCase.mockBehavior != nil {
+				if userID, err := strconv.Atoi(testCase.userID); err == nil {
+					testCase.mockBehavior(adminService, admin, userID, testCase.request)
+				}
+			}
+
+			handler := NewAdminHandler(adminService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				ctx.Set("user_id", admin.UserID)
+				ctx.Set("user_role", admin.Role)
+			})
+
+			r.PUT("/admin/users/:id/role", handler.UpdateUserRole)
+
+			ctx.Request, _ = http.NewRequest("PUT", "/admin/users/"+testCase.userID+"/role", bytes.NewBufferString(testCase.requestBody))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+			setTestClient(ctx.Request)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestAdminHandler_UnlockUser(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAdminService, actor models.Actor, userID int)
+
+	admin := models.Actor{UserID: 1, Role: rbac.RoleAdmin}
+
+	testTable := []struct {
+		name                 string
+		userID               string
+		authenticated        bool
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:          "OK",
+			userID:        "2",
+			authenticated: true,
+			mockBehavior: func(s *mockservice.MockAdminService, actor models.Actor, userID int) {
+				s.EXPECT().UnlockUser(actor, userID, testClient).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"User unlocked successfully"}`,
+		},
+		{
+			name:                 "User not found in context",
+			userID:               "2",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:                 "Invalid user ID",
+			userID:               "abc",
+			authenticated:        true,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid user ID"}`,
+		},
+		{
+			name:          "User not found",
+			userID:        "999",
+			authenticated: true,
+			mockBehavior: func(s *mockservice.MockAdminService, actor models.Actor, userID int) {
+				s.EXPECT().UnlockUser(actor, userID, testClient).Return(errors.New("user not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"not_found", "message":"User not found"}`,
+		},
+		{
+			name:          "Internal server error",
+			userID:        "2",
+			authenticated: true,
+			mockBehavior: func(s *mockservice.MockAdminService, actor models.Actor, userID int) {
+				s.EXPECT().UnlockUser(actor, userID, testClient).Return(errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to unlock user"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			adminService := mockservice.NewMockAdminService(c)
+
+			if testCase.mockBehavior != nil {
+				if userID, err := strconv.Atoi(testCase.userID); err == nil {
+					testCase.mockBehavior(adminService, admin, userID)
+				}
+			}
+
+			handler := NewAdminHandler(adminService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.authenticated {
+					ctx.Set("user_id", admin.UserID)
+					ctx.Set("user_role", admin.Role)
+				}
+			})
+
+			r.POST("/admin/users/:id/unlock", handler.UnlockUser)
+
+			ctx.Request, _ = http.NewRequest("POST", "/admin/users/"+testCase.userID+"/unlock", nil)
+			setTestClient(ctx.Request)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
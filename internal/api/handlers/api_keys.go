@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/internal/models"
+	"marketplace-api/internal/service"
+	"marketplace-api/pkg/middleware"
+	"marketplace-api/pkg/utils"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyServiceInterface
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyServiceInterface) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKey создает персональный API-ключ
+// @Summary Создать API-ключ
+// @Description Создает именованный API-ключ с указанными scopes (listings:read, listings:write) и необязательным сроком действия. Ключ показывается только в этом ответе
+// @Tags api-keys
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body models.CreateAPIKeyRequest true "Название, scopes и срок действия ключа"
+// @Success 201 {object} utils.SuccessResponse{data=models.CreatedAPIKey}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	var req models.CreateAPIKeyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	key, err := h.apiKeyService.CreateAPIKey(userID, req, clientInfo(c))
+	if err != nil {
+		if err.Error() == "api key name is required" ||
+			err.Error() == "invalid api key scope" ||
+			err.Error() == "expiration must be in the future" {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+
+		utils.InternalError(c, "Failed to create API key")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusCreated, key, "API key created, store it now: it will not be shown again")
+}
+
+// ListAPIKeys возвращает API-ключи текущего пользователя
+// @Summary Список API-ключей
+// @Description Возвращает API-ключи пользователя с начальными символами ключа, scopes, сроком действия и временем последнего использования
+// @Tags api-keys
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.SuccessResponse{data=[]models.APIKey}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	keys, err := h.apiKeyService.ListAPIKeys(userID)
+	if err != nil {
+		utils.InternalError(c, "Failed to get API keys")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, keys, "API keys retrieved successfully")
+}
+
+// RevokeAPIKey отзывает API-ключ
+// @Summary Отозвать API-ключ
+// @Description Удаляет API-ключ текущего пользователя, после чего он сразу перестает приниматься
+// @Tags api-keys
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID ключа"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid API key ID")
+		return
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(userID, id, clientInfo(c)); err != nil {
+		if err.Error() == "api key not found" {
+			utils.NotFound(c, "API key not found")
+			return
+		}
+
+		utils.InternalError(c, "Failed to revoke API key")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, nil, "API key revoked")
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"marketplace-api/internal/models"
+	mockservice "marketplace-api/internal/service/mocks"
+)
+
+func TestAPIKeyHandler_CreateAPIKey(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAPIKeyService, userID int, req models.CreateAPIKeyRequest)
+
+	expiresAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                 string
+		userID               interface{}
+		requestBody          string
+		request              models.CreateAPIKeyRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			userID:      1,
+			requestBody: `{"name":"bulk import","scopes":["listings:read","listings:write"],"expires_at":"2026-01-01T00:00:00Z"}`,
+			request: models.CreateAPIKeyRequest{
+				Name:      "bulk import",
+				Scopes:    []string{"listings:read", "listings:write"},
+				ExpiresAt: &expiresAt,
+			},
+			mockBehavior: func(s *mockservice.MockAPIKeyService, userID int, req models.CreateAPIKeyRequest) {
+				key := &models.CreatedAPIKey{
+					APIKey: models.APIKey{
+						ID:        3,
+						UserID:    1,
+						Name:      "bulk import",
+						Prefix:    "mk_AbCdEfGh",
+						Scopes:    []string{"listings:read", "listings:write"},
+						ExpiresAt: &expiresAt,
+						CreatedAt: time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+					},
+					Key: "mk_AbCdEfGhsecret",
+				}
+				s.EXPECT().CreateAPIKey(userID, req, testClient).Return(key, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"message":"API key created, store it now: it will not be shown again","data":{"id":3,"name":"bulk import","prefix":"mk_AbCdEfGh","scopes":["listings:read","listings:write"],"expires_at":"2026-01-01T00:00:00Z","created_at":"2025-07-22T10:00:00Z","key":"mk_AbCdEfGhsecret"}}`,
+		},
+		{
+			name:                 "User not found in context",
+			requestBody:          `{"name":"bulk import","scopes":["listings:read"]}`,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:                 "Missing scopes",
+			userID:               1,
+			requestBody:          `{"name":"bulk import"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
+		},
+		{
+			name:        "Unknown scope",
+			userID:      1,
+			requestBody: `{"name":"bulk import","scopes":["users:manage"]}`,
+			request:     models.CreateAPIKeyRequest{Name: "bulk import", Scopes: []string{"users:manage"}},
+			mockBehavior: func(s *mockservice.MockAPIKeyService, userID int, req models.CreateAPIKeyRequest) {
+				s.EXPECT().CreateAPIKey(userID, req, testClient).Return(nil, errors.New("invalid api key scope"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"invalid api key scope"}`,
+		},
+		{
+			name:        "Internal server error",
+			userID:      1,
+			requestBody: `{"name":"bulk import","scopes":["listings:read"]}`,
+			request:     models.CreateAPIKeyRequest{Name: "bulk import", Scopes: []string{"listings:read"}},
+			mockBehavior: func(s *mockservice.MockAPIKeyService, userID int, req models.CreateAPIKeyRequest) {
+				s.EXPECT().CreateAPIKey(userID, req, testClient).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to create API key"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			apiKeyService := mockservice.NewMockAPIKeyService(c)
+
+			if testCase.mockBehavior != nil {
+				if userID, ok := testCase.userID.(int); ok {
+					testCase.mockBehavior(apiKeyService, userID, testCase.request)
+				}
+			}
+
+			handler := NewAPIKeyHandler(apiKeyService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+			})
+
+			r.POST("/auth/api-keys", handler.CreateAPIKey)
+
+			ctx.Request, _ = http.NewRequest("POST", "/auth/api-keys", bytes.NewBufferString(testCase.requestBody))
+			setTestClient(ctx.Request)
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestAPIKeyHandler_ListAPIKeys(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	lastUsedAt := time.Date(2025, 7, 23, 8, 30, 0, 0, time.UTC)
+
+	apiKeyService := mockservice.NewMockAPIKeyService(c)
+	apiKeyService.EXPECT().ListAPIKeys(1).Return([]models.APIKey{
+		{
+			ID:         3,
+			UserID:     1,
+			Name:       "bulk import",
+			Prefix:     "mk_AbCdEfGh",
+			Scopes:     []string{"listings:write"},
+			LastUsedAt: &lastUsedAt,
+			CreatedAt:  time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+		},
+	}, nil)
+
+	handler := NewAPIKeyHandler(apiKeyService)
+
+	w := httptest.NewRecorder()
+
+	gin.SetMode(gin.TestMode)
+	ctx, r := gin.CreateTestContext(w)
+
+	r.Use(func(ctx *gin.Context) {
+		ctx.Set("user_id", 1)
+	})
+
+	r.GET("/auth/api-keys", handler.ListAPIKeys)
+
+	ctx.Request, _ = http.NewRequest("GET", "/auth/api-keys", nil)
+
+	r.ServeHTTP(w, ctx.Request)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"message":"API keys retrieved successfully","data":[{"id":3,"name":"bulk import","prefix":"mk_AbCdEfGh","scopes":["listings:write"],"last_used_at":"2025-07-23T08:30:00Z","created_at":"2025-07-22T10:00:00Z"}]}`, w.Body.String())
+}
+
+func TestAPIKeyHandler_RevokeAPIKey(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAPIKeyService, userID, keyID int)
+
+	testTable := []struct {
+		name                 string
+		keyParam             string
+		keyID                int
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "OK",
+			keyParam: "3",
+			keyID:    3,
+			mockBehavior: func(s *mockservice.MockAPIKeyService, userID, keyID int) {
+				s.EXPECT().RevokeAPIKey(userID, keyID, testClient).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"API key revoked"}`,
+		},
+		{
+			name:                 "Invalid ID",
+			keyParam:             "abc",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid API key ID"}`,
+		},
+		{
+			name:     "Not found",
+			keyParam: "99",
+			keyID:    99,
+			mockBehavior: func(s *mockservice.MockAPIKeyService, userID, keyID int) {
+				s.EXPECT().RevokeAPIKey(userID, keyID, testClient).Return(errors.New("api key not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"not_found", "message":"API key not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			apiKeyService := mockservice.NewMockAPIKeyService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(apiKeyService, 1, testCase.keyID)
+			}
+
+			handler := NewAPIKeyHandler(apiKeyService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				ctx.Set("user_id", 1)
+			})
+
+			r.DELETE("/auth/api-keys/:id", handler.RevokeAPIKey)
+
+			ctx.Request, _ = http.NewRequest("DELETE", "/auth/api-keys/"+testCase.keyParam, nil)
+			setTestClient(ctx.Request)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"marketplace-api/internal/models"
 	"marketplace-api/internal/service"
+	"marketplace-api/pkg/middleware"
 	"marketplace-api/pkg/utils"
 )
 
@@ -21,7 +24,7 @@ func NewAuthHandler(authService service.AuthServiceInterface) *AuthHandler {
 
 // Register регистрирует нового пользователя
 // @Summary Регистрация пользователя
-// @Description Регистрирует нового пользователя в системе
+// @Description Регистрирует нового пользователя в системе. Если логин или пароль не соответствуют политике, в details перечисляются все нарушенные правила
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -38,18 +41,25 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.Register(req)
+	response, err := h.authService.Register(req, clientInfo(c))
 	if err != nil {
 		if err.Error() == "user already exists" {
 			utils.Conflict(c, "User with this login already exists")
 			return
 		}
-		if err.Error() == "invalid login format" {
-			utils.BadRequest(c, "Login must be 3-50 characters long and contain only letters, numbers, and underscores")
+		if respondPolicyViolation(c, err, "Login or password does not meet the requirements") {
 			return
 		}
-		if err.Error() == "password must be at least 6 characters long and contain letters and digits" {
-			utils.BadRequest(c, err.Error())
+		if err.Error() == "email is required" {
+			utils.BadRequest(c, "Email is required")
+			return
+		}
+		if err.Error() == "invalid email format" {
+			utils.BadRequest(c, "Invalid email format")
+			return
+		}
+		if err.Error() == "email already in use" {
+			utils.Conflict(c, "Email is already in use")
 			return
 		}
 
@@ -62,14 +72,17 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login авторизует пользователя
 // @Summary Авторизация пользователя
-// @Description Авторизует пользователя в системе
+// @Description Авторизует пользователя в системе. Если включен второй фактор, возвращает two_factor с токеном для /auth/login/2fa
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param credentials body models.LoginRequest true "Учетные данные"
-// @Success 200 {object} models.AuthResponse
+// @Success 200 {object} models.LoginResponse
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
+// @Failure 423 {object} utils.ErrorResponse
+// @Failure 429 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
@@ -79,15 +92,275 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.Login(req)
+	response, err := h.authService.Login(req, clientInfo(c))
 	if err != nil {
+		if respondLoginThrottled(c, err) {
+			return
+		}
 		utils.Unauthorized(c, "Invalid login or password")
 		return
 	}
 
+	if response.TwoFactor != nil {
+		utils.SendSuccess(c, http.StatusOK, response, "Two-factor authentication required")
+		return
+	}
+
 	utils.SendSuccess(c, http.StatusOK, response, "Login successful")
 }
 
+// LoginTwoFactor завершает вход вторым фактором
+// @Summary Подтверждение входа вторым фактором
+// @Description Завершает вход по токену из ответа /auth/login кодом TOTP или одноразовым кодом восстановления
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.LoginTwoFactorRequest true "Токен незавершенного входа и код"
+// @Success 200 {object} models.AuthResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 423 {object} utils.ErrorResponse
+// @Failure 429 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/login/2fa [post]
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req models.LoginTwoFactorRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	response, err := h.authService.LoginTwoFactor(req, clientInfo(c))
+	if err != nil {
+		if respondLoginThrottled(c, err) {
+			return
+		}
+		if err.Error() == "invalid challenge" {
+			utils.Unauthorized(c, "Invalid or expired challenge token")
+			return
+		}
+		if err.Error() == "invalid two-factor code" {
+			utils.Unauthorized(c, "Invalid two-factor code")
+			return
+		}
+
+		utils.InternalError(c, "Login failed")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, response, "Login successful")
+}
+
+// Refresh обновляет пару токенов
+// @Summary Обновление токенов
+// @Description Обменивает refresh-токен на новую пару access/refresh токенов. Refresh-токен одноразовый
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.RefreshRequest true "Refresh-токен"
+// @Success 200 {object} models.AuthResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req models.RefreshRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	response, err := h.authService.Refresh(req, clientInfo(c))
+	if err != nil {
+		if err.Error() == "invalid refresh token" {
+			utils.Unauthorized(c, "Invalid or expired refresh token")
+			return
+		}
+		if err.Error() == "refresh token reuse detected" {
+			utils.Unauthorized(c, "Refresh token has already been used, please log in again")
+			return
+		}
+
+		utils.InternalError(c, "Failed to refresh token")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, response, "Token refreshed successfully")
+}
+
+// Logout завершает текущий сеанс
+// @Summary Выход из системы
+// @Description Отзывает текущий access-токен и, если передан, refresh-токен
+// @Tags auth
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body models.LogoutRequest false "Refresh-токен текущего сеанса"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims, exists := middleware.GetTokenClaims(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	var req models.LogoutRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	if err := h.authService.Logout(claims, req, clientInfo(c)); err != nil {
+		utils.InternalError(c, "Failed to log out")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, nil, "Logged out successfully")
+}
+
+// LogoutAll завершает все сеансы пользователя
+// @Summary Выход со всех устройств
+// @Description Отзывает все access- и refresh-токены текущего пользователя
+// @Tags auth
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	if err := h.authService.LogoutAll(userID, clientInfo(c)); err != nil {
+		utils.InternalError(c, "Failed to log out from all devices")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, nil, "Logged out from all devices successfully")
+}
+
+// ChangePassword меняет пароль текущего пользователя
+// @Summary Смена пароля
+// @Description Меняет пароль после проверки текущего. Новый пароль проверяется по политике и не должен совпадать с последними паролями. Все сеансы пользователя завершаются, в ответе новая пара токенов
+// @Tags auth
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body models.ChangePasswordRequest true "Текущий и новый пароль"
+// @Success 200 {object} models.AuthResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/password [put]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	var req models.ChangePasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	response, err := h.authService.ChangePassword(userID, req, clientInfo(c))
+	if err != nil {
+		if err.Error() == "invalid current password" {
+			utils.BadRequest(c, "Current password is incorrect")
+			return
+		}
+		if respondPolicyViolation(c, err, "Password does not meet the requirements") {
+			return
+		}
+
+		utils.InternalError(c, "Failed to change password")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, response, "Password changed successfully")
+}
+
+// ForgotPassword запрашивает сброс пароля
+// @Summary Запрос сброса пароля
+// @Description Отправляет письмо со ссылкой для сброса пароля. Ответ не зависит от того, существует ли пользователь
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.ForgotPasswordRequest true "Логин пользователя"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 429 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	if err := h.authService.ForgotPassword(req, clientInfo(c)); err != nil {
+		var rateErr *service.RateLimitError
+		if errors.As(err, &rateErr) {
+			utils.TooManyRequests(c, rateErr.RetryAfter, "Too many password reset requests, try again later")
+			return
+		}
+		utils.InternalError(c, "Failed to request password reset")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, nil, "If the account exists, a password reset link has been sent")
+}
+
+// ResetPassword устанавливает новый пароль по токену сброса
+// @Summary Сброс пароля
+// @Description Устанавливает новый пароль по одноразовому токену из письма. Все сеансы пользователя завершаются
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.ResetPasswordRequest true "Токен сброса и новый пароль"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	if err := h.authService.ResetPassword(req, clientInfo(c)); err != nil {
+		if err.Error() == "invalid or expired reset token" {
+			utils.BadRequest(c, "Invalid or expired reset token")
+			return
+		}
+		if respondPolicyViolation(c, err, "Password does not meet the requirements") {
+			return
+		}
+
+		utils.InternalError(c, "Failed to reset password")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, nil, "Password has been reset successfully")
+}
+
 // Me возвращает информацию о текущем пользователе
 // @Summary Получить информацию о текущем пользователе
 // @Description Возвращает информацию о авторизованном пользователе
@@ -118,3 +391,29 @@ func (h *AuthHandler) Me(c *gin.Context) {
 
 	utils.SendSuccess(c, http.StatusOK, user, "")
 }
+
+// respondLoginThrottled отвечает 423 или 429, если вход ограничен защитой от перебора
+func respondLoginThrottled(c *gin.Context, err error) bool {
+	var rateErr *service.RateLimitError
+	if !errors.As(err, &rateErr) {
+		return false
+	}
+
+	if rateErr.Message == "account locked" {
+		utils.Locked(c, rateErr.RetryAfter, "Account is temporarily locked due to too many failed login attempts")
+	} else {
+		utils.TooManyRequests(c, rateErr.RetryAfter, "Too many login attempts, try again later")
+	}
+	return true
+}
+
+// respondPolicyViolation отвечает 400 со списком нарушенных правил, если логин или пароль не соответствуют политике
+func respondPolicyViolation(c *gin.Context, err error, message string) bool {
+	var policyErr *service.PolicyError
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+
+	utils.BadRequestWithDetails(c, message, policyErr.Violations)
+	return true
+}
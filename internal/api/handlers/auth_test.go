@@ -13,9 +13,20 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"marketplace-api/internal/models"
+	"marketplace-api/internal/policy"
+	"marketplace-api/internal/service"
 	mockservice "marketplace-api/internal/service/mocks"
+	"marketplace-api/pkg/utils"
 )
 
+// testClient клиент, от имени которого тесты выполняют вход
+var testClient = models.ClientInfo{IP: "203.0.113.7", UserAgent: "marketplace-tests/1.0"}
+
+func setTestClient(req *http.Request) {
+	req.RemoteAddr = testClient.IP + ":52100"
+	req.Header.Set("User-Agent", testClient.UserAgent)
+}
+
 func TestAuthHandler_Register(t *testing.T) {
 	type mockBehavior func(s *mockservice.MockAuthService, req models.RegisterRequest)
 
@@ -42,12 +53,15 @@ func TestAuthHandler_Register(t *testing.T) {
 						CreatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
 						UpdatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
 					},
-					Token: "jwt.token.here",
+					Token:                 "jwt.token.here",
+					TokenExpiresAt:        time.Date(2025, 7, 21, 20, 11, 37, 0, time.UTC),
+					RefreshToken:          "refresh.token.here",
+					RefreshTokenExpiresAt: time.Date(2025, 8, 20, 19, 56, 37, 0, time.UTC),
 				}
-				s.EXPECT().Register(req).Return(response, nil)
+				s.EXPECT().Register(req, testClient).Return(response, nil)
 			},
 			expectedStatusCode:   http.StatusCreated,
-			expectedResponseBody: `{"message":"User registered successfully","data":{"user":{"id":1,"login":"artificial00","created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-21T19:56:37Z"},"token":"jwt.token.here"}}`,
+			expectedResponseBody: `{"message":"User registered successfully","data":{"user":{"id":1,"login":"artificial00","created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-21T19:56:37Z"},"token":"jwt.token.here","token_expires_at":"2025-07-21T20:11:37Z","refresh_token":"refresh.token.here","refresh_token_expires_at":"2025-08-20T19:56:37Z"}}`,
 		},
 		{
 			name:                 "Invalid request format",
@@ -67,6 +81,22 @@ func TestAuthHandler_Register(t *testing.T) {
 			expectedStatusCode:   http.StatusBadRequest,
 			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
 		},
+		{
+			name:        "Policy violation",
+			requestBody: `{"login":"admin","password":"qwerty123"}`,
+			request: models.RegisterRequest{
+				Login:    "admin",
+				Password: "qwerty123",
+			},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.RegisterRequest) {
+				s.EXPECT().Register(req, testClient).Return(nil, &service.PolicyError{Violations: []policy.Violation{
+					{Field: policy.FieldLogin, Rule: policy.RuleReserved, Message: "login is reserved"},
+					{Field: policy.FieldPassword, Rule: policy.RuleBreached, Message: "password has appeared in a data breach"},
+				}})
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Login or password does not meet the requirements", "details":[{"field":"login","rule":"reserved","message":"login is reserved"},{"field":"password","rule":"breached","message":"password has appeared in a data breach"}]}`,
+		},
 		{
 			name:        "User already exists",
 			requestBody: `{"login":"existing_user","password":"password123"}`,
@@ -75,11 +105,38 @@ func TestAuthHandler_Register(t *testing.T) {
 				Password: "password123",
 			},
 			mockBehavior: func(s *mockservice.MockAuthService, req models.RegisterRequest) {
-				s.EXPECT().Register(req).Return(nil, errors.New("user already exists"))
+				s.EXPECT().Register(req, testClient).Return(nil, errors.New("user already exists"))
 			},
 			expectedStatusCode:   http.StatusConflict,
 			expectedResponseBody: `{"error":"conflict", "message":"User with this login already exists"}`,
 		},
+		{
+			name:        "Email already in use",
+			requestBody: `{"login":"artificial00","email":"taken@example.com","password":"password123"}`,
+			request: models.RegisterRequest{
+				Login:    "artificial00",
+				Email:    "taken@example.com",
+				Password: "password123",
+			},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.RegisterRequest) {
+				s.EXPECT().Register(req, testClient).Return(nil, errors.New("email already in use"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"conflict", "message":"Email is already in use"}`,
+		},
+		{
+			name:        "Email required by policy",
+			requestBody: `{"login":"artificial00","password":"password123"}`,
+			request: models.RegisterRequest{
+				Login:    "artificial00",
+				Password: "password123",
+			},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.RegisterRequest) {
+				s.EXPECT().Register(req, testClient).Return(nil, errors.New("email is required"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Email is required"}`,
+		},
 		{
 			name:        "Internal server error",
 			requestBody: `{"login":"testuser","password":"password123"}`,
@@ -88,7 +145,7 @@ func TestAuthHandler_Register(t *testing.T) {
 				Password: "password123",
 			},
 			mockBehavior: func(s *mockservice.MockAuthService, req models.RegisterRequest) {
-				s.EXPECT().Register(req).Return(nil, errors.New("database connection failed"))
+				s.EXPECT().Register(req, testClient).Return(nil, errors.New("database connection failed"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
 			expectedResponseBody: `{"error":"internal_error", "message":"Registration failed"}`,
@@ -116,6 +173,7 @@ func TestAuthHandler_Register(t *testing.T) {
 			r.POST("/auth/register", handler.Register)
 
 			ctx.Request, _ = http.NewRequest("POST", "/auth/register", bytes.NewBufferString(testCase.requestBody))
+			setTestClient(ctx.Request)
 			ctx.Request.Header.Set("Content-Type", "application/json")
 
 			r.ServeHTTP(w, ctx.Request)
@@ -136,6 +194,7 @@ func TestAuthHandler_Login(t *testing.T) {
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
+		expectedRetryAfter   string
 	}{
 		{
 			name:        "OK",
@@ -152,12 +211,34 @@ func TestAuthHandler_Login(t *testing.T) {
 						CreatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
 						UpdatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
 					},
-					Token: "jwt.token.here",
+					Token:                 "jwt.token.here",
+					TokenExpiresAt:        time.Date(2025, 7, 21, 20, 11, 37, 0, time.UTC),
+					RefreshToken:          "refresh.token.here",
+					RefreshTokenExpiresAt: time.Date(2025, 8, 20, 19, 56, 37, 0, time.UTC),
+				}
+				s.EXPECT().Login(req, testClient).Return(&models.LoginResponse{AuthResponse: response}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Login successful","data":{"user":{"id":1,"login":"artificial00","created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-21T19:56:37Z"},"token":"jwt.token.here","token_expires_at":"2025-07-21T20:11:37Z","refresh_token":"refresh.token.here","refresh_token_expires_at":"2025-08-20T19:56:37Z"}}`,
+		},
+		{
+			name:        "Two-factor required",
+			requestBody: `{"login":"artificial00","password":"password123"}`,
+			request: models.LoginRequest{
+				Login:    "artificial00",
+				Password: "password123",
+			},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.LoginRequest) {
+				response := &models.LoginResponse{
+					TwoFactor: &models.TwoFactorChallenge{
+						ChallengeToken: "challenge.token.here",
+						ExpiresAt:      time.Date(2025, 7, 21, 20, 1, 37, 0, time.UTC),
+					},
 				}
-				s.EXPECT().Login(req).Return(response, nil)
+				s.EXPECT().Login(req, testClient).Return(response, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"message":"Login successful","data":{"user":{"id":1,"login":"artificial00","created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-21T19:56:37Z"},"token":"jwt.token.here"}}`,
+			expectedResponseBody: `{"message":"Two-factor authentication required","data":{"two_factor":{"challenge_token":"challenge.token.here","expires_at":"2025-07-21T20:01:37Z"}}}`,
 		},
 		{
 			name:                 "Invalid request format",
@@ -185,7 +266,7 @@ func TestAuthHandler_Login(t *testing.T) {
 				Password: "wrongpassword",
 			},
 			mockBehavior: func(s *mockservice.MockAuthService, req models.LoginRequest) {
-				s.EXPECT().Login(req).Return(nil, errors.New("invalid credentials"))
+				s.EXPECT().Login(req, testClient).Return(nil, errors.New("invalid credentials"))
 			},
 			expectedStatusCode:   http.StatusUnauthorized,
 			expectedResponseBody: `{"error":"unauthorized", "message":"Invalid login or password"}`,
@@ -198,11 +279,45 @@ func TestAuthHandler_Login(t *testing.T) {
 				Password: "password123",
 			},
 			mockBehavior: func(s *mockservice.MockAuthService, req models.LoginRequest) {
-				s.EXPECT().Login(req).Return(nil, errors.New("user not found"))
+				s.EXPECT().Login(req, testClient).Return(nil, errors.New("user not found"))
 			},
 			expectedStatusCode:   http.StatusUnauthorized,
 			expectedResponseBody: `{"error":"unauthorized", "message":"Invalid login or password"}`,
 		},
+		{
+			name:        "Account locked",
+			requestBody: `{"login":"artificial00","password":"password123"}`,
+			request: models.LoginRequest{
+				Login:    "artificial00",
+				Password: "password123",
+			},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.LoginRequest) {
+				s.EXPECT().Login(req, testClient).Return(nil, &service.RateLimitError{
+					Message:    "account locked",
+					RetryAfter: 14*time.Minute + 30*time.Second,
+				})
+			},
+			expectedStatusCode:   http.StatusLocked,
+			expectedResponseBody: `{"error":"locked", "message":"Account is temporarily locked due to too many failed login attempts"}`,
+			expectedRetryAfter:   "870",
+		},
+		{
+			name:        "Too many attempts",
+			requestBody: `{"login":"artificial00","password":"password123"}`,
+			request: models.LoginRequest{
+				Login:    "artificial00",
+				Password: "password123",
+			},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.LoginRequest) {
+				s.EXPECT().Login(req, testClient).Return(nil, &service.RateLimitError{
+					Message:    "too many login attempts",
+					RetryAfter: 3500 * time.Millisecond,
+				})
+			},
+			expectedStatusCode:   http.StatusTooManyRequests,
+			expectedResponseBody: `{"error":"too_many_requests", "message":"Too many login attempts, try again later"}`,
+			expectedRetryAfter:   "4",
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -226,6 +341,738 @@ func TestAuthHandler_Login(t *testing.T) {
 			r.POST("/auth/login", handler.Login)
 
 			ctx.Request, _ = http.NewRequest("POST", "/auth/login", bytes.NewBufferString(testCase.requestBody))
+			setTestClient(ctx.Request)
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+			assert.Equal(t, testCase.expectedRetryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestAuthHandler_LoginTwoFactor(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAuthService, req models.LoginTwoFactorRequest)
+
+	testTable := []struct {
+		name                 string
+		requestBody          string
+		request              models.LoginTwoFactorRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedRetryAfter   string
+	}{
+		{
+			name:        "OK",
+			requestBody: `{"challenge_token":"challenge.token.here","code":"123456"}`,
+			request: models.LoginTwoFactorRequest{
+				ChallengeToken: "challenge.token.here",
+				Code:           "123456",
+			},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.LoginTwoFactorRequest) {
+				response := &models.AuthResponse{
+					User: models.User{
+						ID:               1,
+						Login:            "artificial00",
+						TwoFactorEnabled: true,
+						CreatedAt:        time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+						UpdatedAt:        time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+					},
+					Token:                 "jwt.token.here",
+					TokenExpiresAt:        time.Date(2025, 7, 21, 20, 11, 37, 0, time.UTC),
+					RefreshToken:          "refresh.token.here",
+					RefreshTokenExpiresAt: time.Date(2025, 8, 20, 19, 56, 37, 0, time.UTC),
+				}
+				s.EXPECT().LoginTwoFactor(req, testClient).Return(response, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Login successful","data":{"user":{"id":1,"login":"artificial00","two_factor_enabled":true,"created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-21T19:56:37Z"},"token":"jwt.token.here","token_expires_at":"2025-07-21T20:11:37Z","refresh_token":"refresh.token.here","refresh_token_expires_at":"2025-08-20T19:56:37Z"}}`,
+		},
+		{
+			name:                 "Missing code",
+			requestBody:          `{"challenge_token":"challenge.token.here"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
+		},
+		{
+			name:        "Invalid challenge",
+			requestBody: `{"challenge_token":"expired","code":"123456"}`,
+			request: models.LoginTwoFactorRequest{
+				ChallengeToken: "expired",
+				Code:           "123456",
+			},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.LoginTwoFactorRequest) {
+				s.EXPECT().LoginTwoFactor(req, testClient).Return(nil, errors.New("invalid challenge"))
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"Invalid or expired challenge token"}`,
+		},
+		{
+			name:        "Invalid code",
+			requestBody: `{"challenge_token":"challenge.token.here","code":"000000"}`,
+			request: models.LoginTwoFactorRequest{
+				ChallengeToken: "challenge.token.here",
+				Code:           "000000",
+			},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.LoginTwoFactorRequest) {
+				s.EXPECT().LoginTwoFactor(req, testClient).Return(nil, errors.New("invalid two-factor code"))
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"Invalid two-factor code"}`,
+		},
+		{
+			name:        "Account locked",
+			requestBody: `{"challenge_token":"challenge.token.here","code":"000000"}`,
+			request: models.LoginTwoFactorRequest{
+				ChallengeToken: "challenge.token.here",
+				Code:           "000000",
+			},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.LoginTwoFactorRequest) {
+				s.EXPECT().LoginTwoFactor(req, testClient).Return(nil, &service.RateLimitError{
+					Message:    "account locked",
+					RetryAfter: 15 * time.Minute,
+				})
+			},
+			expectedStatusCode:   http.StatusLocked,
+			expectedResponseBody: `{"error":"locked", "message":"Account is temporarily locked due to too many failed login attempts"}`,
+			expectedRetryAfter:   "900",
+		},
+		{
+			name:        "Service error",
+			requestBody: `{"challenge_token":"challenge.token.here","code":"123456"}`,
+			request: models.LoginTwoFactorRequest{
+				ChallengeToken: "challenge.token.here",
+				Code:           "123456",
+			},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.LoginTwoFactorRequest) {
+				s.EXPECT().LoginTwoFactor(req, testClient).Return(nil, errors.New("database error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Login failed"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			authService := mockservice.NewMockAuthService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(authService, testCase.request)
+			}
+
+			handler := NewAuthHandler(authService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.POST("/auth/login/2fa", handler.LoginTwoFactor)
+
+			ctx.Request, _ = http.NewRequest("POST", "/auth/login/2fa", bytes.NewBufferString(testCase.requestBody))
+			setTestClient(ctx.Request)
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+			assert.Equal(t, testCase.expectedRetryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestAuthHandler_Refresh(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAuthService, req models.RefreshRequest)
+
+	testTable := []struct {
+		name                 string
+		requestBody          string
+		request              models.RefreshRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			requestBody: `{"refresh_token":"old.refresh.token"}`,
+			request:     models.RefreshRequest{RefreshToken: "old.refresh.token"},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.RefreshRequest) {
+				response := &models.AuthResponse{
+					User: models.User{
+						ID:        1,
+						Login:     "artificial00",
+						CreatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+						UpdatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+					},
+					Token:                 "new.jwt.token",
+					TokenExpiresAt:        time.Date(2025, 7, 22, 10, 15, 0, 0, time.UTC),
+					RefreshToken:          "new.refresh.token",
+					RefreshTokenExpiresAt: time.Date(2025, 8, 21, 10, 0, 0, 0, time.UTC),
+				}
+				s.EXPECT().Refresh(req, testClient).Return(response, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Token refreshed successfully","data":{"user":{"id":1,"login":"artificial00","created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-21T19:56:37Z"},"token":"new.jwt.token","token_expires_at":"2025-07-22T10:15:00Z","refresh_token":"new.refresh.token","refresh_token_expires_at":"2025-08-21T10:00:00Z"}}`,
+		},
+		{
+			name:                 "Missing refresh token",
+			requestBody:          `{}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
+		},
+		{
+			name:        "Invalid refresh token",
+			requestBody: `{"refresh_token":"unknown"}`,
+			request:     models.RefreshRequest{RefreshToken: "unknown"},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.RefreshRequest) {
+				s.EXPECT().Refresh(req, testClient).Return(nil, errors.New("invalid refresh token"))
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"Invalid or expired refresh token"}`,
+		},
+		{
+			name:        "Reused refresh token",
+			requestBody: `{"refresh_token":"used.refresh.token"}`,
+			request:     models.RefreshRequest{RefreshToken: "used.refresh.token"},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.RefreshRequest) {
+				s.EXPECT().Refresh(req, testClient).Return(nil, errors.New("refresh token reuse detected"))
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"Refresh token has already been used, please log in again"}`,
+		},
+		{
+			name:        "Internal server error",
+			requestBody: `{"refresh_token":"old.refresh.token"}`,
+			request:     models.RefreshRequest{RefreshToken: "old.refresh.token"},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.RefreshRequest) {
+				s.EXPECT().Refresh(req, testClient).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to refresh token"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			authService := mockservice.NewMockAuthService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(authService, testCase.request)
+			}
+
+			handler := NewAuthHandler(authService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.POST("/auth/refresh", handler.Refresh)
+
+			ctx.Request, _ = http.NewRequest("POST", "/auth/refresh", bytes.NewBufferString(testCase.requestBody))
+			setTestClient(ctx.Request)
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAuthService, claims *utils.Claims, req models.LogoutRequest)
+
+	claims := &utils.Claims{UserID: 1, Login: "artificial00"}
+	claims.ID = "token-id"
+
+	testTable := []struct {
+		name                 string
+		requestBody          string
+		claims               *utils.Claims
+		request              models.LogoutRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK with refresh token",
+			requestBody: `{"refresh_token":"refresh.token.here"}`,
+			claims:      claims,
+			request:     models.LogoutRequest{RefreshToken: "refresh.token.here"},
+			mockBehavior: func(s *mockservice.MockAuthService, claims *utils.Claims, req models.LogoutRequest) {
+				s.EXPECT().Logout(claims, req, testClient).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Logged out successfully"}`,
+		},
+		{
+			name:        "OK without body",
+			requestBody: ``,
+			claims:      claims,
+			mockBehavior: func(s *mockservice.MockAuthService, claims *utils.Claims, req models.LogoutRequest) {
+				s.EXPECT().Logout(claims, req, testClient).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Logged out successfully"}`,
+		},
+		{
+			name:                 "Invalid request format",
+			requestBody:          `{"refresh_token":}`,
+			claims:               claims,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
+		},
+		{
+			name:                 "Claims not found in context",
+			requestBody:          ``,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:        "Internal server error",
+			requestBody: ``,
+			claims:      claims,
+			mockBehavior: func(s *mockservice.MockAuthService, claims *utils.Claims, req models.LogoutRequest) {
+				s.EXPECT().Logout(claims, req, testClient).Return(errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to log out"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			authService := mockservice.NewMockAuthService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(authService, testCase.claims, testCase.request)
+			}
+
+			handler := NewAuthHandler(authService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.claims != nil {
+					ctx.Set("user_id", testCase.claims.UserID)
+					ctx.Set("token_claims", testCase.claims)
+				}
+			})
+
+			r.POST("/auth/logout", handler.Logout)
+
+			ctx.Request, _ = http.NewRequest("POST", "/auth/logout", bytes.NewBufferString(testCase.requestBody))
+			setTestClient(ctx.Request)
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestAuthHandler_LogoutAll(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAuthService, userID int)
+
+	testTable := []struct {
+		name                 string
+		userID               interface{}
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockAuthService, userID int) {
+				s.EXPECT().LogoutAll(userID, testClient).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Logged out from all devices successfully"}`,
+		},
+		{
+			name:                 "User not found in context",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:   "Internal server error",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockAuthService, userID int) {
+				s.EXPECT().LogoutAll(userID, testClient).Return(errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to log out from all devices"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			authService := mockservice.NewMockAuthService(c)
+
+			if testCase.mockBehavior != nil {
+				if userID, ok := testCase.userID.(int); ok {
+					testCase.mockBehavior(authService, userID)
+				}
+			}
+
+			handler := NewAuthHandler(authService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+			})
+
+			r.POST("/auth/logout-all", handler.LogoutAll)
+
+			ctx.Request, _ = http.NewRequest("POST", "/auth/logout-all", nil)
+			setTestClient(ctx.Request)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestAuthHandler_ChangePassword(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAuthService, userID int, req models.ChangePasswordRequest)
+
+	testTable := []struct {
+		name                 string
+		userID               interface{}
+		requestBody          string
+		request              models.ChangePasswordRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			userID:      1,
+			requestBody: `{"current_password":"oldpass123","new_password":"newpass123"}`,
+			request:     models.ChangePasswordRequest{CurrentPassword: "oldpass123", NewPassword: "newpass123"},
+			mockBehavior: func(s *mockservice.MockAuthService, userID int, req models.ChangePasswordRequest) {
+				response := &models.AuthResponse{
+					User: models.User{
+						ID:        1,
+						Login:     "artificial00",
+						CreatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+						UpdatedAt: time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+					},
+					Token:                 "new.jwt.token",
+					TokenExpiresAt:        time.Date(2025, 7, 22, 10, 15, 0, 0, time.UTC),
+					RefreshToken:          "new.refresh.token",
+					RefreshTokenExpiresAt: time.Date(2025, 8, 21, 10, 0, 0, 0, time.UTC),
+				}
+				s.EXPECT().ChangePassword(userID, req, testClient).Return(response, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Password changed successfully","data":{"user":{"id":1,"login":"artificial00","created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-22T10:00:00Z"},"token":"new.jwt.token","token_expires_at":"2025-07-22T10:15:00Z","refresh_token":"new.refresh.token","refresh_token_expires_at":"2025-08-21T10:00:00Z"}}`,
+		},
+		{
+			name:                 "User not found in context",
+			requestBody:          `{"current_password":"oldpass123","new_password":"newpass123"}`,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:                 "Missing current password",
+			userID:               1,
+			requestBody:          `{"new_password":"newpass123"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
+		},
+		{
+			name:        "Wrong current password",
+			userID:      1,
+			requestBody: `{"current_password":"wrongpass1","new_password":"newpass123"}`,
+			request:     models.ChangePasswordRequest{CurrentPassword: "wrongpass1", NewPassword: "newpass123"},
+			mockBehavior: func(s *mockservice.MockAuthService, userID int, req models.ChangePasswordRequest) {
+				s.EXPECT().ChangePassword(userID, req, testClient).Return(nil, errors.New("invalid current password"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Current password is incorrect"}`,
+		},
+		{
+			name:        "Weak new password",
+			userID:      1,
+			requestBody: `{"current_password":"oldpass123","new_password":"onlyletters"}`,
+			request:     models.ChangePasswordRequest{CurrentPassword: "oldpass123", NewPassword: "onlyletters"},
+			mockBehavior: func(s *mockservice.MockAuthService, userID int, req models.ChangePasswordRequest) {
+				s.EXPECT().ChangePassword(userID, req, testClient).Return(nil, &service.PolicyError{Violations: []policy.Violation{
+					{Field: policy.FieldPassword, Rule: policy.RuleDigit, Message: "password must contain a digit"},
+				}})
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Password does not meet the requirements", "details":[{"field":"password","rule":"digit","message":"password must contain a digit"}]}`,
+		},
+		{
+			name:        "Recently used password",
+			userID:      1,
+			requestBody: `{"current_password":"oldpass123","new_password":"oldpass123"}`,
+			request:     models.ChangePasswordRequest{CurrentPassword: "oldpass123", NewPassword: "oldpass123"},
+			mockBehavior: func(s *mockservice.MockAuthService, userID int, req models.ChangePasswordRequest) {
+				s.EXPECT().ChangePassword(userID, req, testClient).Return(nil, &service.PolicyError{Violations: []policy.Violation{
+					policy.ReusedViolation(5),
+				}})
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Password does not meet the requirements", "details":[{"field":"password","rule":"reused","message":"password must differ from the last 5 passwords"}]}`,
+		},
+		{
+			name:        "Internal server error",
+			userID:      1,
+			requestBody: `{"current_password":"oldpass123","new_password":"newpass123"}`,
+			request:     models.ChangePasswordRequest{CurrentPassword: "oldpass123", NewPassword: "newpass123"},
+			mockBehavior: func(s *mockservice.MockAuthService, userID int, req models.ChangePasswordRequest) {
+				s.EXPECT().ChangePassword(userID, req, testClient).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to change password"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			authService := mockservice.NewMockAuthService(c)
+
+			if testCase.mockBehavior != nil {
+				if userID, ok := testCase.userID.(int); ok {
+					testCase.mockBehavior(authService, userID, testCase.request)
+				}
+			}
+
+			handler := NewAuthHandler(authService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+			})
+
+			r.PUT("/auth/password", handler.ChangePassword)
+
+			ctx.Request, _ = http.NewRequest("PUT", "/auth/password", bytes.NewBufferString(testCase.requestBody))
+			setTestClient(ctx.Request)
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestAuthHandler_ForgotPassword(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAuthService, req models.ForgotPasswordRequest)
+
+	testTable := []struct {
+		name                 string
+		requestBody          string
+		request              models.ForgotPasswordRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedRetryAfter   string
+	}{
+		{
+			name:        "OK",
+			requestBody: `{"login":"artificial00"}`,
+			request:     models.ForgotPasswordRequest{Login: "artificial00"},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.ForgotPasswordRequest) {
+				s.EXPECT().ForgotPassword(req, testClient).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"If the account exists, a password reset link has been sent"}`,
+		},
+		{
+			name:                 "Missing login",
+			requestBody:          `{}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
+		},
+		{
+			name:        "Too many requests",
+			requestBody: `{"login":"artificial00"}`,
+			request:     models.ForgotPasswordRequest{Login: "artificial00"},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.ForgotPasswordRequest) {
+				s.EXPECT().ForgotPassword(req, testClient).Return(&service.RateLimitError{
+					Message:    "too many password reset requests",
+					RetryAfter: 10 * time.Minute,
+				})
+			},
+			expectedStatusCode:   http.StatusTooManyRequests,
+			expectedResponseBody: `{"error":"too_many_requests", "message":"Too many password reset requests, try again later"}`,
+			expectedRetryAfter:   "600",
+		},
+		{
+			name:        "Internal server error",
+			requestBody: `{"login":"artificial00"}`,
+			request:     models.ForgotPasswordRequest{Login: "artificial00"},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.ForgotPasswordRequest) {
+				s.EXPECT().ForgotPassword(req, testClient).Return(errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to request password reset"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			authService := mockservice.NewMockAuthService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(authService, testCase.request)
+			}
+
+			handler := NewAuthHandler(authService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.POST("/auth/password/forgot", handler.ForgotPassword)
+
+			ctx.Request, _ = http.NewRequest("POST", "/auth/password/forgot", bytes.NewBufferString(testCase.requestBody))
+			setTestClient(ctx.Request)
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+			assert.Equal(t, testCase.expectedRetryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestAuthHandler_ResetPassword(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAuthService, req models.ResetPasswordRequest)
+
+	testTable := []struct {
+		name                 string
+		requestBody          string
+		request              models.ResetPasswordRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			requestBody: `{"token":"reset.token","new_password":"newpass123"}`,
+			request:     models.ResetPasswordRequest{Token: "reset.token", NewPassword: "newpass123"},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.ResetPasswordRequest) {
+				s.EXPECT().ResetPassword(req, testClient).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Password has been reset successfully"}`,
+		},
+		{
+			name:                 "Missing token",
+			requestBody:          `{"new_password":"newpass123"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
+		},
+		{
+			name:        "Invalid or expired token",
+			requestBody: `{"token":"used.token","new_password":"newpass123"}`,
+			request:     models.ResetPasswordRequest{Token: "used.token", NewPassword: "newpass123"},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.ResetPasswordRequest) {
+				s.EXPECT().ResetPassword(req, testClient).Return(errors.New("invalid or expired reset token"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid or expired reset token"}`,
+		},
+		{
+			name:        "Weak new password",
+			requestBody: `{"token":"reset.token","new_password":"onlyletters"}`,
+			request:     models.ResetPasswordRequest{Token: "reset.token", NewPassword: "onlyletters"},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.ResetPasswordRequest) {
+				s.EXPECT().ResetPassword(req, testClient).Return(&service.PolicyError{Violations: []policy.Violation{
+					{Field: policy.FieldPassword, Rule: policy.RuleDigit, Message: "password must contain a digit"},
+				}})
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Password does not meet the requirements", "details":[{"field":"password","rule":"digit","message":"password must contain a digit"}]}`,
+		},
+		{
+			name:        "Internal server error",
+			requestBody: `{"token":"reset.token","new_password":"newpass123"}`,
+			request:     models.ResetPasswordRequest{Token: "reset.token", NewPassword: "newpass123"},
+			mockBehavior: func(s *mockservice.MockAuthService, req models.ResetPasswordRequest) {
+				s.EXPECT().ResetPassword(req, testClient).Return(errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to reset password"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			authService := mockservice.NewMockAuthService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(authService, testCase.request)
+			}
+
+			handler := NewAuthHandler(authService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.POST("/auth/password/reset", handler.ResetPassword)
+
+			ctx.Request, _ = http.NewRequest("POST", "/auth/password/reset", bytes.NewBufferString(testCase.requestBody))
+			setTestClient(ctx.Request)
 			ctx.Request.Header.Set("Content-Type", "application/json")
 
 			r.ServeHTTP(w, ctx.Request)
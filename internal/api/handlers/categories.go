@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/internal/models"
+	"marketplace-api/internal/service"
+	"marketplace-api/pkg/utils"
+)
+
+type CategoryHandler struct {
+	categoryService service.CategoryServiceInterface
+}
+
+func NewCategoryHandler(categoryService service.CategoryServiceInterface) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+	}
+}
+
+// GetCategories получает дерево категорий
+// @Summary Дерево категорий
+// @Description Возвращает все категории объявлений деревом: у каждой категории в children перечислены подкатегории. Категории одного уровня упорядочены по sort_order и названию
+// @Tags categories
+// @Produce json
+// @Success 200 {object} utils.SuccessResponse{data=[]models.Category}
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /categories [get]
+func (h *CategoryHandler) GetCategories(c *gin.Context) {
+	categories, err := h.categoryService.GetCategoryTree()
+	if err != nil {
+		utils.InternalError(c, "Failed to get categories")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, categories, "")
+}
+
+// CreateCategory создает категорию
+// @Summary Создать категорию
+// @Description Создает категорию верхнего уровня или подкатегорию, если указан parent_id. Slug состоит из строчных латинских букв, цифр и дефисов и уникален среди всех категорий. Доступно администраторам
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param category body models.CreateCategoryRequest true "Данные категории"
+// @Success 201 {object} utils.SuccessResponse{data=models.Category}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/categories [post]
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	actor, exists := currentActor(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	var req models.CreateCategoryRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format: "+err.Error())
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(actor, req, clientInfo(c))
+	if err != nil {
+		switch err.Error() {
+		case "invalid category slug", "parent category not found":
+			utils.BadRequest(c, err.Error())
+		case "category slug already exists":
+			utils.Conflict(c, "Category with this slug already exists")
+		default:
+			utils.InternalError(c, "Failed to create category")
+		}
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusCreated, category, "Category created successfully")
+}
+
+// UpdateCategory обновляет категорию
+// @Summary Обновить категорию
+// @Description Меняет slug, название, порядок или родителя категории; parent_id 0 переносит категорию на верхний уровень. Категорию нельзя перенести в нее саму или в ее подкатегорию. Доступно администраторам
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID категории"
+// @Param category body models.UpdateCategoryRequest true "Данные для обновления"
+// @Success 200 {object} utils.SuccessResponse{data=models.Category}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	actor, exists := currentActor(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid category ID")
+		return
+	}
+
+	var req models.UpdateCategoryRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format: "+err.Error())
+		return
+	}
+
+	category, err := h.categoryService.UpdateCategory(actor, id, req, clientInfo(c))
+	if err != nil {
+		switch err.Error() {
+		case "category not found":
+			utils.NotFound(c, "Category not found")
+		case "invalid category ID", "invalid category slug", "parent category not found",
+			"category cannot be moved into itself or its subcategory", "no fields to update":
+			utils.BadRequest(c, err.Error())
+		case "category slug already exists":
+			utils.Conflict(c, "Category with this slug already exists")
+		default:
+			utils.InternalError(c, "Failed to update category")
+		}
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, category, "Category updated successfully")
+}
+
+// DeleteCategory удаляет категорию
+// @Summary Удалить категорию
+// @Description Удаляет категорию. Категорию с подкатегориями или объявлениями удалить нельзя: их сначала нужно перенести. Доступно администраторам
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID категории"
+// @Success 200 {object} utils.SuccessResponse{data=nil}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	actor, exists := currentActor(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid category ID")
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(actor, id, clientInfo(c)); err != nil {
+		switch err.Error() {
+		case "category not found":
+			utils.NotFound(c, "Category not found")
+		case "invalid category ID":
+			utils.BadRequest(c, err.Error())
+		case "category has subcategories", "category has listings":
+			utils.Conflict(c, err.Error())
+		default:
+			utils.InternalError(c, "Failed to delete category")
+		}
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, nil, "Category deleted successfully")
+}
@@ -0,0 +1,397 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"marketplace-api/internal/models"
+	mockservice "marketplace-api/internal/service/mocks"
+	"marketplace-api/pkg/rbac"
+)
+
+func TestCategoryHandler_GetCategories(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockCategoryService)
+
+	createdAt := time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mockservice.MockCategoryService) {
+				categories := []models.Category{
+					{
+						ID:        1,
+						Slug:      "electronics",
+						Name:      "Электроника",
+						CreatedAt: createdAt,
+						UpdatedAt: createdAt,
+						Children: []models.Category{
+							{
+								ID:        2,
+								ParentID:  intPtr(1),
+								Slug:      "phones",
+								Name:      "Телефоны",
+								CreatedAt: createdAt,
+								UpdatedAt: createdAt,
+							},
+						},
+					},
+				}
+				s.EXPECT().GetCategoryTree().Return(categories, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":[{"id":1,"parent_id":null,"slug":"electronics","name":"Электроника","sort_order":0,"created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-21T19:56:37Z","children":[{"id":2,"parent_id":1,"slug":"phones","name":"Телефоны","sort_order":0,"created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-21T19:56:37Z"}]}]}`,
+		},
+		{
+			name: "Internal server error",
+			mockBehavior: func(s *mockservice.MockCategoryService) {
+				s.EXPECT().GetCategoryTree().Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to get categories"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			categoryService := mockservice.NewMockCategoryService(c)
+			testCase.mockBehavior(categoryService)
+
+			handler := NewCategoryHandler(categoryService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.GET("/categories", handler.GetCategories)
+
+			ctx.Request, _ = http.NewRequest("GET", "/categories", nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestCategoryHandler_CreateCategory(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockCategoryService, actor models.Actor, req models.CreateCategoryRequest)
+
+	admin := models.Actor{UserID: 1, Role: rbac.RoleAdmin}
+
+	testTable := []struct {
+		name                 string
+		requestBody          string
+		request              models.CreateCategoryRequest
+		unauthenticated      bool
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			requestBody: `{"parent_id":1,"slug":"phones","name":"Телефоны","sort_order":10}`,
+			request:     models.CreateCategoryRequest{ParentID: intPtr(1), Slug: "phones", Name: "Телефоны", SortOrder: 10},
+			mockBehavior: func(s *mockservice.MockCategoryService, actor models.Actor, req models.CreateCategoryRequest) {
+				category := &models.Category{
+					ID:        2,
+					ParentID:  intPtr(1),
+					Slug:      "phones",
+					Name:      "Телефоны",
+					SortOrder: 10,
+					CreatedAt: time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+					UpdatedAt: time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+				}
+				s.EXPECT().CreateCategory(actor, req, testClient).Return(category, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"message":"Category created successfully","data":{"id":2,"parent_id":1,"slug":"phones","name":"Телефоны","sort_order":10,"created_at":"2025-07-22T10:00:00Z","updated_at":"2025-07-22T10:00:00Z"}}`,
+		},
+		{
+			name:                 "Missing name",
+			requestBody:          `{"slug":"phones"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format: Key: 'CreateCategoryRequest.Name' Error:Field validation for 'Name' failed on the 'required' tag"}`,
+		},
+		{
+			name:        "Invalid slug",
+			requestBody: `{"slug":"Phones & Tablets","name":"Телефоны"}`,
+			request:     models.CreateCategoryRequest{Slug: "Phones & Tablets", Name: "Телефоны"},
+			mockBehavior: func(s *mockservice.MockCategoryService, actor models.Actor, req models.CreateCategoryRequest) {
+				s.EXPECT().CreateCategory(actor, req, testClient).Return(nil, errors.New("invalid category slug"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"invalid category slug"}`,
+		},
+		{
+			name:        "Slug taken",
+			requestBody: `{"slug":"phones","name":"Телефоны"}`,
+			request:     models.CreateCategoryRequest{Slug: "phones", Name: "Телефоны"},
+			mockBehavior: func(s *mockservice.MockCategoryService, actor models.Actor, req models.CreateCategoryRequest) {
+				s.EXPECT().CreateCategory(actor, req, testClient).Return(nil, errors.New("category slug already exists"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"conflict", "message":"Category with this slug already exists"}`,
+		},
+		{
+			name:        "Internal server error",
+			requestBody: `{"slug":"phones","name":"Телефоны"}`,
+			request:     models.CreateCategoryRequest{Slug: "phones", Name: "Телефоны"},
+			mockBehavior: func(s *mockservice.MockCategoryService, actor models.Actor, req models.CreateCategoryRequest) {
+				s.EXPECT().CreateCategory(actor, req, testClient).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to create category"}`,
+		},
+		{
+			name:                 "No user ID in context",
+			requestBody:          `{"slug":"phones","name":"Телефоны"}`,
+			unauthenticated:      true,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			categoryService := mockservice.NewMockCategoryService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(categoryService, admin, testCase.request)
+			}
+
+			handler := NewCategoryHandler(categoryService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if !testCase.unauthenticated {
+					ctx.Set("user_id", admin.UserID)
+					ctx.Set("user_role", admin.Role)
+				}
+			})
+
+			r.POST("/admin/categories", handler.CreateCategory)
+
+			ctx.Request, _ = http.NewRequest("POST", "/admin/categories", bytes.NewBufferString(testCase.requestBody))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+			setTestClient(ctx.Request)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestCategoryHandler_UpdateCategory(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockCategoryService, actor models.Actor, id int, req models.UpdateCategoryRequest)
+
+	admin := models.Actor{UserID: 1, Role: rbac.RoleAdmin}
+
+	testTable := []struct {
+		name                 string
+		categoryID           string
+		requestBody          string
+		request              models.UpdateCategoryRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Move to top level",
+			categoryID:  "2",
+			requestBody: `{"parent_id":0}`,
+			request:     models.UpdateCategoryRequest{ParentID: intPtr(0)},
+			mockBehavior: func(s *mockservice.MockCategoryService, actor models.Actor, id int, req models.UpdateCategoryRequest) {
+				category := &models.Category{
+					ID:        2,
+					Slug:      "phones",
+					Name:      "Телефоны",
+					CreatedAt: time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+					UpdatedAt: time.Date(2025, 7, 22, 11, 0, 0, 0, time.UTC),
+				}
+				s.EXPECT().UpdateCategory(actor, id, req, testClient).Return(category, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Category updated successfully","data":{"id":2,"parent_id":null,"slug":"phones","name":"Телефоны","sort_order":0,"created_at":"2025-07-22T10:00:00Z","updated_at":"2025-07-22T11:00:00Z"}}`,
+		},
+		{
+			name:                 "Invalid category ID",
+			categoryID:           "abc",
+			requestBody:          `{"name":"Телефоны"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid category ID"}`,
+		},
+		{
+			name:        "Move into subcategory",
+			categoryID:  "1",
+			requestBody: `{"parent_id":2}`,
+			request:     models.UpdateCategoryRequest{ParentID: intPtr(2)},
+			mockBehavior: func(s *mockservice.MockCategoryService, actor models.Actor, id int, req models.UpdateCategoryRequest) {
+				s.EXPECT().UpdateCategory(actor, id, req, testClient).Return(nil, errors.New("category cannot be moved into itself or its subcategory"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"category cannot be moved into itself or its subcategory"}`,
+		},
+		{
+			name:        "Category not found",
+			categoryID:  "999",
+			requestBody: `{"name":"Телефоны"}`,
+			request:     models.UpdateCategoryRequest{Name: stringPtr("Телефоны")},
+			mockBehavior: func(s *mockservice.MockCategoryService, actor models.Actor, id int, req models.UpdateCategoryRequest) {
+				s.EXPECT().UpdateCategory(actor, id, req, testClient).Return(nil, errors.New("category not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"not_found", "message":"Category not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			categoryService := mockservice.NewMockCategoryService(c)
+
+			if testCase.mockBehavior != nil {
+				if id, err := strconv.Atoi(testCase.categoryID); err == nil {
+					testCase.mockBehavior(categoryService, admin, id, testCase.request)
+				}
+			}
+
+			handler := NewCategoryHandler(categoryService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				ctx.Set("user_id", admin.UserID)
+				ctx.Set("user_role", admin.Role)
+			})
+
+			r.PUT("/admin/categories/:id", handler.UpdateCategory)
+
+			ctx.Request, _ = http.NewRequest("PUT", "/admin/categories/"+testCase.categoryID, bytes.NewBufferString(testCase.requestBody))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+			setTestClient(ctx.Request)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestCategoryHandler_DeleteCategory(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockCategoryService, actor models.Actor, id int)
+
+	admin := models.Actor{UserID: 1, Role: rbac.RoleAdmin}
+
+	testTable := []struct {
+		name                 string
+		categoryID           string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:       "OK",
+			categoryID: "2",
+			mockBehavior: func(s *mockservice.MockCategoryService, actor models.Actor, id int) {
+				s.EXPECT().DeleteCategory(actor, id, testClient).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Category deleted successfully"}`,
+		},
+		{
+			name:       "Has listings",
+			categoryID: "2",
+			mockBehavior: func(s *mockservice.MockCategoryService, actor models.Actor, id int) {
+				s.EXPECT().DeleteCategory(actor, id, testClient).Return(errors.New("category has listings"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"conflict", "message":"category has listings"}`,
+		},
+		{
+			name:       "Category not found",
+			categoryID: "999",
+			mockBehavior: func(s *mockservice.MockCategoryService, actor models.Actor, id int) {
+				s.EXPECT().DeleteCategory(actor, id, testClient).Return(errors.New("category not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"not_found", "message":"Category not found"}`,
+		},
+		{
+			name:       "Internal server error",
+			categoryID: "2",
+			mockBehavior: func(s *mockservice.MockCategoryService, actor models.Actor, id int) {
+				s.EXPECT().DeleteCategory(actor, id, testClient).Return(errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to delete category"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			categoryService := mockservice.NewMockCategoryService(c)
+
+			if testCase.mockBehavior != nil {
+				if id, err := strconv.Atoi(testCase.categoryID); err == nil {
+					testCase.mockBehavior(categoryService, admin, id)
+				}
+			}
+
+			handler := NewCategoryHandler(categoryService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				ctx.Set("user_id", admin.UserID)
+				ctx.Set("user_role", admin.Role)
+			})
+
+			r.DELETE("/admin/categories/:id", handler.DeleteCategory)
+
+			ctx.Request, _ = http.NewRequest("DELETE", "/admin/categories/"+testCase.categoryID, nil)
+			setTestClient(ctx.Request)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
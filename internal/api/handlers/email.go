@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/internal/models"
+	"marketplace-api/internal/service"
+	"marketplace-api/pkg/middleware"
+	"marketplace-api/pkg/utils"
+)
+
+type EmailHandler struct {
+	emailService service.EmailServiceInterface
+}
+
+func NewEmailHandler(emailService service.EmailServiceInterface) *EmailHandler {
+	return &EmailHandler{
+		emailService: emailService,
+	}
+}
+
+// ChangeEmail устанавливает или меняет email текущего пользователя
+// @Summary Установка email
+// @Description Устанавливает или меняет email после проверки пароля и отправляет письмо для подтверждения
+// @Tags auth
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body models.ChangeEmailRequest true "Новый email и текущий пароль"
+// @Success 200 {object} utils.SuccessResponse{data=models.User}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 429 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/email [put]
+func (h *EmailHandler) ChangeEmail(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	var req models.ChangeEmailRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	user, err := h.emailService.ChangeEmail(userID, req, clientInfo(c))
+	if err != nil {
+		var rateErr *service.RateLimitError
+		if errors.As(err, &rateErr) {
+			utils.TooManyRequests(c, rateErr.RetryAfter, "Verification email was sent recently, try again later")
+			return
+		}
+		if err.Error() == "invalid email format" {
+			utils.BadRequest(c, "Invalid email format")
+			return
+		}
+		if err.Error() == "invalid current password" {
+			utils.BadRequest(c, "Current password is incorrect")
+			return
+		}
+		if err.Error() == "email already in use" {
+			utils.Conflict(c, "Email is already in use")
+			return
+		}
+
+		utils.InternalError(c, "Failed to change email")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, user, "Verification email sent")
+}
+
+// VerifyEmail подтверждает email по токену из письма
+// @Summary Подтверждение email
+// @Description Подтверждает email по одноразовому токену из письма
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.VerifyEmailRequest true "Токен подтверждения"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/email/verify [post]
+func (h *EmailHandler) VerifyEmail(c *gin.Context) {
+	var req models.VerifyEmailRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	if err := h.emailService.VerifyEmail(req); err != nil {
+		if err.Error() == "invalid or expired verification token" {
+			utils.BadRequest(c, "Invalid or expired verification token")
+			return
+		}
+
+		utils.InternalError(c, "Failed to verify email")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, nil, "Email verified successfully")
+}
+
+// ResendVerification повторно отправляет письмо подтверждения
+// @Summary Повторная отправка письма подтверждения
+// @Description Отправляет новое письмо подтверждения на текущий email. Частота отправки ограничена
+// @Tags auth
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 429 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/email/resend [post]
+func (h *EmailHandler) ResendVerification(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	if err := h.emailService.ResendVerification(userID); err != nil {
+		var rateErr *service.RateLimitError
+		if errors.As(err, &rateErr) {
+			utils.TooManyRequests(c, rateErr.RetryAfter, "Verification email was sent recently, try again later")
+			return
+		}
+		if err.Error() == "email is not set" {
+			utils.BadRequest(c, "Email is not set")
+			return
+		}
+		if err.Error() == "email already verified" {
+			utils.Conflict(c, "Email is already verified")
+			return
+		}
+
+		utils.InternalError(c, "Failed to send verification email")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, nil, "Verification email sent")
+}
@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"marketplace-api/internal/models"
+	"marketplace-api/internal/service"
+	mockservice "marketplace-api/internal/service/mocks"
+)
+
+func TestEmailHandler_ChangeEmail(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockEmailService, userID int, req models.ChangeEmailRequest)
+
+	testTable := []struct {
+		name                 string
+		userID               interface{}
+		requestBody          string
+		request              models.ChangeEmailRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedRetryAfter   string
+	}{
+		{
+			name:        "OK",
+			userID:      1,
+			requestBody: `{"email":"seller@example.com","password":"password123"}`,
+			request:     models.ChangeEmailRequest{Email: "seller@example.com", Password: "password123"},
+			mockBehavior: func(s *mockservice.MockEmailService, userID int, req models.ChangeEmailRequest) {
+				user := &models.User{
+					ID:        1,
+					Login:     "artificial00",
+					Email:     "seller@example.com",
+					CreatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+					UpdatedAt: time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+				}
+				s.EXPECT().ChangeEmail(userID, req, testClient).Return(user, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Verification email sent","data":{"id":1,"login":"artificial00","email":"seller@example.com","created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-22T10:00:00Z"}}`,
+		},
+		{
+			name:                 "User not found in context",
+			requestBody:          `{"email":"seller@example.com","password":"password123"}`,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:                 "Missing password",
+			userID:               1,
+			requestBody:          `{"email":"seller@example.com"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
+		},
+		{
+			name:        "Invalid email format",
+			userID:      1,
+			requestBody: `{"email":"not-an-email","password":"password123"}`,
+			request:     models.ChangeEmailRequest{Email: "not-an-email", Password: "password123"},
+			mockBehavior: func(s *mockservice.MockEmailService, userID int, req models.ChangeEmailRequest) {
+				s.EXPECT().ChangeEmail(userID, req, testClient).Return(nil, errors.New("invalid email format"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid email format"}`,
+		},
+		{
+			name:        "Wrong password",
+			userID:      1,
+			requestBody: `{"email":"seller@example.com","password":"wrongpass1"}`,
+			request:     models.ChangeEmailRequest{Email: "seller@example.com", Password: "wrongpass1"},
+			mockBehavior: func(s *mockservice.MockEmailService, userID int, req models.ChangeEmailRequest) {
+				s.EXPECT().ChangeEmail(userID, req, testClient).Return(nil, errors.New("invalid current password"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Current password is incorrect"}`,
+		},
+		{
+			name:        "Email already in use",
+			userID:      1,
+			requestBody: `{"email":"taken@example.com","password":"password123"}`,
+			request:     models.ChangeEmailRequest{Email: "taken@example.com", Password: "password123"},
+			mockBehavior: func(s *mockservice.MockEmailService, userID int, req models.ChangeEmailRequest) {
+				s.EXPECT().ChangeEmail(userID, req, testClient).Return(nil, errors.New("email already in use"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"conflict", "message":"Email is already in use"}`,
+		},
+		{
+			name:        "Throttled",
+			userID:      1,
+			requestBody: `{"email":"seller@example.com","password":"password123"}`,
+			request:     models.ChangeEmailRequest{Email: "seller@example.com", Password: "password123"},
+			mockBehavior: func(s *mockservice.MockEmailService, userID int, req models.ChangeEmailRequest) {
+				s.EXPECT().ChangeEmail(userID, req, testClient).Return(nil, &service.RateLimitError{
+					Message:    "verification email recently sent",
+					RetryAfter: 42500 * time.Millisecond,
+				})
+			},
+			expectedStatusCode:   http.StatusTooManyRequests,
+			expectedResponseBody: `{"error":"too_many_requests", "message":"Verification email was sent recently, try again later"}`,
+			expectedRetryAfter:   "43",
+		},
+		{
+			name:        "Internal server error",
+			userID:      1,
+			requestBody: `{"email":"seller@example.com","password":"password123"}`,
+			request:     models.ChangeEmailRequest{Email: "seller@example.com", Password: "password123"},
+			mockBehavior: func(s *mockservice.MockEmailService, userID int, req models.ChangeEmailRequest) {
+				s.EXPECT().ChangeEmail(userID, req, testClient).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to change email"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			emailService := mockservice.NewMockEmailService(c)
+
+			if testCase.mockBehavior != nil {
+				if userID, ok := testCase.userID.(int); ok {
+					testCase.mockBehavior(emailService, userID, testCase.request)
+				}
+			}
+
+			handler := NewEmailHandler(emailService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+			})
+
+			r.PUT("/auth/email", handler.ChangeEmail)
+
+			ctx.Request, _ = http.NewRequest("PUT", "/auth/email", bytes.NewBufferString(testCase.requestBody))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+			setTestClient(ctx.Request)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+			assert.Equal(t, testCase.expectedRetryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestEmailHandler_VerifyEmail(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockEmailService, req models.VerifyEmailRequest)
+
+	testTable := []struct {
+		name                 string
+		requestBody          string
+		request              models.VerifyEmailRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			requestBody: `{"token":"verification.token"}`,
+			request:     models.VerifyEmailRequest{Token: "verification.token"},
+			mockBehavior: func(s *mockservice.MockEmailService, req models.VerifyEmailRequest) {
+				s.EXPECT().VerifyEmail(req).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Email verified successfully"}`,
+		},
+		{
+			name:                 "Missing token",
+			requestBody:          `{}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
+		},
+		{
+			name:        "Invalid or expired token",
+			requestBody: `{"token":"used.token"}`,
+			request:     models.VerifyEmailRequest{Token: "used.token"},
+			mockBehavior: func(s *mockservice.MockEmailService, req models.VerifyEmailRequest) {
+				s.EXPECT().VerifyEmail(req).Return(errors.New("invalid or expired verification token"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid or expired verification token"}`,
+		},
+		{
+			name:        "Internal server error",
+			requestBody: `{"token":"verification.token"}`,
+			request:     models.VerifyEmailRequest{Token: "verification.token"},
+			mockBehavior: func(s *mockservice.MockEmailService, req models.VerifyEmailRequest) {
+				s.EXPECT().VerifyEmail(req).Return(errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to verify email"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			emailService := mockservice.NewMockEmailService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(emailService, testCase.request)
+			}
+
+			handler := NewEmailHandler(emailService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.POST("/auth/email/verify", handler.VerifyEmail)
+
+			ctx.Request, _ = http.NewRequest("POST", "/auth/email/verify", bytes.NewBufferString(testCase.requestBody))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestEmailHandler_ResendVerification(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockEmailService, userID int)
+
+	testTable := []struct {
+		name                 string
+		userID               interface{}
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedRetryAfter   string
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockEmailService, userID int) {
+				s.EXPECT().ResendVerification(userID).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Verification email sent"}`,
+		},
+		{
+			name:                 "User not found in context",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:   "Email not set",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockEmailService, userID int) {
+				s.EXPECT().ResendVerification(userID).Return(errors.New("email is not set"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Email is not set"}`,
+		},
+		{
+			name:   "Already verified",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockEmailService, userID int) {
+				s.EXPECT().ResendVerification(userID).Return(errors.New("email already verified"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"conflict", "message":"Email is already verified"}`,
+		},
+		{
+			name:   "Throttled",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockEmailService, userID int) {
+				s.EXPECT().ResendVerification(userID).Return(&service.RateLimitError{
+					Message:    "verification email recently sent",
+					RetryAfter: 30 * time.Second,
+				})
+			},
+			expectedStatusCode:   http.StatusTooManyRequests,
+			expectedResponseBody: `{"error":"too_many_requests", "message":"Verification email was sent recently, try again later"}`,
+			expectedRetryAfter:   "30",
+		},
+		{
+			name:   "Internal server error",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockEmailService, userID int) {
+				s.EXPECT().ResendVerification(userID).Return(errors.New("smtp unavailable"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to send verification email"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			emailService := mockservice.NewMockEmailService(c)
+
+			if testCase.mockBehavior != nil {
+				if userID, ok := testCase.userID.(int); ok {
+					testCase.mockBehavior(emailService, userID)
+				}
+			}
+
+			handler := NewEmailHandler(emailService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+			})
+
+			r.POST("/auth/email/resend", handler.ResendVerification)
+
+			ctx.Request, _ = http.NewRequest("POST", "/auth/email/resend", nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+			assert.Equal(t, testCase.expectedRetryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/internal/models"
+	"marketplace-api/internal/service"
+	"marketplace-api/pkg/middleware"
+	"marketplace-api/pkg/utils"
+)
+
+type ImpersonationHandler struct {
+	impersonationService service.ImpersonationServiceInterface
+}
+
+func NewImpersonationHandler(impersonationService service.ImpersonationServiceInterface) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationService: impersonationService,
+	}
+}
+
+// Impersonate выдает администратору токен для работы от имени пользователя
+// @Summary Войти от имени пользователя
+// @Description Выдает короткоживущий access-токен пользователя, в котором записан и администратор. Все запросы с токеном пишутся в журнал. Без allow_write разрешены только запросы на чтение; смена пароля, email, 2FA, API-ключей, удаление аккаунта и выгрузка данных недоступны всегда. Войти от имени администратора нельзя. Доступно администраторам
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param request body models.ImpersonateRequest true "Причина и разрешение на запись"
+// @Success 201 {object} utils.SuccessResponse{data=models.ImpersonationResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/users/{id}/impersonate [post]
+func (h *ImpersonationHandler) Impersonate(c *gin.Context) {
+	claims, exists := middleware.GetTokenClaims(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	var req models.ImpersonateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format: "+err.Error())
+		return
+	}
+
+	response, err := h.impersonationService.Impersonate(claims, id, req, clientInfo(c))
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			utils.NotFound(c, "User not found")
+		case "invalid user ID", "reason is required", "cannot impersonate yourself":
+			utils.BadRequest(c, err.Error())
+		case "cannot impersonate an administrator", "nested impersonation is not allowed":
+			utils.Forbidden(c, err.Error())
+		default:
+			utils.InternalError(c, "Failed to impersonate user")
+		}
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusCreated, response, "Impersonation started")
+}
+
+// ListImpersonations получает журнал входов от имени пользователей
+// @Summary Журнал входов от имени пользователей
+// @Description Возвращает постраничный журнал входов администраторов от имени пользователей, начиная с последних. Доступно администраторам
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param admin_id query int false "Фильтр по администратору"
+// @Param user_id query int false "Фильтр по пользователю"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(20)
+// @Success 200 {object} utils.SuccessResponse{data=models.PaginatedImpersonations}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/impersonations [get]
+func (h *ImpersonationHandler) ListImpersonations(c *gin.Context) {
+	var filter models.ImpersonationsFilter
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequest(c, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	impersonations, err := h.impersonationService.ListImpersonations(filter)
+	if err != nil {
+		utils.InternalError(c, "Failed to get impersonations")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, impersonations, "")
+}
+
+// GetImpersonation получает запись о входе от имени пользователя
+// @Summary Вход от имени пользователя
+// @Description Возвращает запись о входе вместе со всеми запросами, выполненными с выданным токеном. Доступно администраторам
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID записи"
+// @Success 200 {object} utils.SuccessResponse{data=models.Impersonation}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/impersonations/{id} [get]
+func (h *ImpersonationHandler) GetImpersonation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid impersonation ID")
+		return
+	}
+
+	impersonation, err := h.impersonationService.GetImpersonation(id)
+	if err != nil {
+		switch err.Error() {
+		case "impersonation not found":
+			utils.NotFound(c, "Impersonation not found")
+		case "invalid impersonation ID":
+			utils.BadRequest(c, err.Error())
+		default:
+			utils.InternalError(c, "Failed to get impersonation")
+		}
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, impersonation, "")
+}
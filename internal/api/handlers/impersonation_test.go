@@ -0,0 +1,351 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"marketplace-api/internal/models"
+	mockservice "marketplace-api/internal/service/mocks"
+	"marketplace-api/pkg/rbac"
+	"marketplace-api/pkg/utils"
+)
+
+func TestImpersonationHandler_Impersonate(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockImpersonationService, admin *utils.Claims, userID int, req models.ImpersonateRequest)
+
+	admin := &utils.Claims{UserID: 1, Login: "admin", Role: "admin", SessionID: 9}
+
+	testTable := []struct {
+		name                 string
+		userID               string
+		requestBody          string
+		claims               *utils.Claims
+		request              models.ImpersonateRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			userID:      "2",
+			requestBody: `{"reason":"ticket #4521"}`,
+			claims:      admin,
+			request:     models.ImpersonateRequest{Reason: "ticket #4521"},
+			mockBehavior: func(s *mockservice.MockImpersonationService, admin *utils.Claims, userID int, req models.ImpersonateRequest) {
+				response := &models.ImpersonationResponse{
+					ImpersonationID: 3,
+					User: models.User{
+						ID:        2,
+						Login:     "seller",
+						Role:      rbac.RoleUser,
+						CreatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+						UpdatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+					},
+					Token:          "impersonation-token",
+					TokenExpiresAt: time.Date(2025, 7, 22, 10, 15, 0, 0, time.UTC),
+				}
+				s.EXPECT().Impersonate(admin, userID, req, testClient).Return(response, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"message":"Impersonation started","data":{"impersonation_id":3,"user":{"id":2,"login":"seller","role":"user","created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-21T19:56:37Z"},"token":"impersonation-token","token_expires_at":"2025-07-22T10:15:00Z","allow_write":false}}`,
+		},
+		{
+			name:                 "Reason is missing",
+			userID:               "2",
+			requestBody:          `{"allow_write":true}`,
+			claims:               admin,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format: Key: 'ImpersonateRequest.Reason' Error:Field validation for 'Reason' failed on the 'required' tag"}`,
+		},
+		{
+			name:                 "Invalid user ID",
+			userID:               "abc",
+			requestBody:          `{"reason":"ticket #4521"}`,
+			claims:               admin,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid user ID"}`,
+		},
+		{
+			name:        "Impersonate yourself",
+			userID:      "1",
+			requestBody: `{"reason":"ticket #4521"}`,
+			claims:      admin,
+			request:     models.ImpersonateRequest{Reason: "ticket #4521"},
+			mockBehavior: func(s *mockservice.MockImpersonationService, admin *utils.Claims, userID int, req models.ImpersonateRequest) {
+				s.EXPECT().Impersonate(admin, userID, req, testClient).Return(nil, errors.New("cannot impersonate yourself"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"cannot impersonate yourself"}`,
+		},
+		{
+			name:        "Administrator",
+			userID:      "2",
+			requestBody: `{"reason":"ticket #4521","allow_write":true}`,
+			claims:      admin,
+			request:     models.ImpersonateRequest{Reason: "ticket #4521", AllowWrite: true},
+			mockBehavior: func(s *mockservice.MockImpersonationService, admin *utils.Claims, userID int, req models.ImpersonateRequest) {
+				s.EXPECT().Impersonate(admin, userID, req, testClient).Return(nil, errors.New("cannot impersonate an administrator"))
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: `{"error":"forbidden", "message":"cannot impersonate an administrator"}`,
+		},
+		{
+			name:        "User not found",
+			userID:      "404",
+			requestBody: `{"reason":"ticket #4521"}`,
+			claims:      admin,
+			request:     models.ImpersonateRequest{Reason: "ticket #4521"},
+			mockBehavior: func(s *mockservice.MockImpersonationService, admin *utils.Claims, userID int, req models.ImpersonateRequest) {
+				s.EXPECT().Impersonate(admin, userID, req, testClient).Return(nil, errors.New("user not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"not_found", "message":"User not found"}`,
+		},
+		{
+			name:                 "User not found in context",
+			userID:               "2",
+			requestBody:          `{"reason":"ticket #4521"}`,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:        "Internal server error",
+			userID:      "2",
+			requestBody: `{"reason":"ticket #4521"}`,
+			claims:      admin,
+			request:     models.ImpersonateRequest{Reason: "ticket #4521"},
+			mockBehavior: func(s *mockservice.MockImpersonationService, admin *utils.Claims, userID int, req models.ImpersonateRequest) {
+				s.EXPECT().Impersonate(admin, userID, req, testClient).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to impersonate user"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			impersonationService := mockservice.NewMockImpersonationService(c)
+
+			if testCase.mockBehavior != nil {
+				id, _ := strconv.Atoi(testCase.userID)
+				testCase.mockBehavior(impersonationService, testCase.claims, id, testCase.request)
+			}
+
+			handler := NewImpersonationHandler(impersonationService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.claims != nil {
+					ctx.Set("token_claims", testCase.claims)
+				}
+			})
+
+			r.POST("/admin/users/:id/impersonate", handler.Impersonate)
+
+			ctx.Request, _ = http.NewRequest("POST", "/admin/users/"+testCase.userID+"/impersonate", bytes.NewBufferString(testCase.requestBody))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+			setTestClient(ctx.Request)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestImpersonationHandler_ListImpersonations(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockImpersonationService, filter models.ImpersonationsFilter)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		filter               models.ImpersonationsFilter
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			query:  "?user_id=2&limit=10",
+			filter: models.ImpersonationsFilter{UserID: 2, Limit: 10},
+			mockBehavior: func(s *mockservice.MockImpersonationService, filter models.ImpersonationsFilter) {
+				impersonations := &models.PaginatedImpersonations{
+					Data: []models.Impersonation{
+						{
+							ID:            3,
+							AdminID:       1,
+							AdminLogin:    "admin",
+							UserID:        2,
+							UserLogin:     "seller",
+							Reason:        "ticket #4521",
+							IPAddress:     "203.0.113.7",
+							UserAgent:     "marketplace-tests/1.0",
+							ExpiresAt:     time.Date(2025, 7, 22, 10, 15, 0, 0, time.UTC),
+							CreatedAt:     time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+							RequestsCount: 4,
+						},
+					},
+					Total:      1,
+					Page:       1,
+					Limit:      10,
+					TotalPages: 1,
+				}
+				s.EXPECT().ListImpersonations(filter).Return(impersonations, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":{"data":[{"id":3,"admin_id":1,"admin_login":"admin","user_id":2,"user_login":"seller","reason":"ticket #4521","allow_write":false,"ip_address":"203.0.113.7","user_agent":"marketplace-tests/1.0","expires_at":"2025-07-22T10:15:00Z","created_at":"2025-07-22T10:00:00Z","requests_count":4}],"total":1,"page":1,"limit":10,"total_pages":1}}`,
+		},
+		{
+			name:                 "Invalid user filter",
+			query:                "?user_id=-1",
+			filter:               models.ImpersonationsFilter{},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid query parameters: Key: 'ImpersonationsFilter.UserID' Error:Field validation for 'UserID' failed on the 'min' tag"}`,
+		},
+		{
+			name:   "Internal server error",
+			filter: models.ImpersonationsFilter{},
+			mockBehavior: func(s *mockservice.MockImpersonationService, filter models.ImpersonationsFilter) {
+				s.EXPECT().ListImpersonations(filter).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to get impersonations"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			impersonationService := mockservice.NewMockImpersonationService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(impersonationService, testCase.filter)
+			}
+
+			handler := NewImpersonationHandler(impersonationService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.GET("/admin/impersonations", handler.ListImpersonations)
+
+			ctx.Request, _ = http.NewRequest("GET", "/admin/impersonations"+testCase.query, nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestImpersonationHandler_GetImpersonation(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockImpersonationService)
+
+	status := http.StatusOK
+
+	testTable := []struct {
+		name                 string
+		id                   string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			id:   "3",
+			mockBehavior: func(s *mockservice.MockImpersonationService) {
+				impersonation := &models.Impersonation{
+					ID:            3,
+					AdminID:       1,
+					AdminLogin:    "admin",
+					UserID:        2,
+					UserLogin:     "seller",
+					Reason:        "ticket #4521",
+					ExpiresAt:     time.Date(2025, 7, 22, 10, 15, 0, 0, time.UTC),
+					CreatedAt:     time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+					RequestsCount: 2,
+					Requests: []models.ImpersonatedRequest{
+						{ID: 10, Method: "GET", Path: "/api/listings/my", IPAddress: "203.0.113.7", Status: &status, CreatedAt: time.Date(2025, 7, 22, 10, 1, 0, 0, time.UTC)},
+						{ID: 11, Method: "DELETE", Path: "/api/listings/5", IPAddress: "203.0.113.7", Blocked: true, CreatedAt: time.Date(2025, 7, 22, 10, 2, 0, 0, time.UTC)},
+					},
+				}
+				s.EXPECT().GetImpersonation(3).Return(impersonation, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":{"id":3,"admin_id":1,"admin_login":"admin","user_id":2,"user_login":"seller","reason":"ticket #4521","allow_write":false,"ip_address":"","user_agent":"","expires_at":"2025-07-22T10:15:00Z","created_at":"2025-07-22T10:00:00Z","requests_count":2,"requests":[{"id":10,"method":"GET","path":"/api/listings/my","ip_address":"203.0.113.7","blocked":false,"status":200,"created_at":"2025-07-22T10:01:00Z"},{"id":11,"method":"DELETE","path":"/api/listings/5","ip_address":"203.0.113.7","blocked":true,"status":null,"created_at":"2025-07-22T10:02:00Z"}]}}`,
+		},
+		{
+			name:                 "Invalid ID",
+			id:                   "abc",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid impersonation ID"}`,
+		},
+		{
+			name: "Not found",
+			id:   "404",
+			mockBehavior: func(s *mockservice.MockImpersonationService) {
+				s.EXPECT().GetImpersonation(404).Return(nil, errors.New("impersonation not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"not_found", "message":"Impersonation not found"}`,
+		},
+		{
+			name: "Internal server error",
+			id:   "3",
+			mockBehavior: func(s *mockservice.MockImpersonationService) {
+				s.EXPECT().GetImpersonation(3).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to get impersonation"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			impersonationService := mockservice.NewMockImpersonationService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(impersonationService)
+			}
+
+			handler := NewImpersonationHandler(impersonationService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.GET("/admin/impersonations/:id", handler.GetImpersonation)
+
+			ctx.Request, _ = http.NewRequest("GET", "/admin/impersonations/"+testCase.id, nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/pkg/utils"
+)
+
+type JWKSHandler struct {
+	keyRing *utils.KeyRing
+}
+
+func NewJWKSHandler(keyRing *utils.KeyRing) *JWKSHandler {
+	return &JWKSHandler{
+		keyRing: keyRing,
+	}
+}
+
+// GetJWKS возвращает публичные ключи для проверки токенов
+// @Summary Публичные ключи подписи (JWKS)
+// @Description Возвращает набор публичных ключей в формате RFC 7517 для проверки токенов сторонними сервисами. В режиме HS256 набор пуст
+// @Tags auth
+// @Produce json
+// @Success 200 {object} utils.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, h.keyRing.JWKS(time.Now()))
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"marketplace-api/pkg/utils"
+)
+
+func TestJWKSHandler_GetJWKS(t *testing.T) {
+	now := time.Now()
+	expired := now.Add(-time.Minute)
+
+	rsaKey, err := utils.GenerateSigningKey(utils.AlgorithmRS256, now.Add(-time.Hour))
+	require.NoError(t, err)
+	edKey, err := utils.GenerateSigningKey(utils.AlgorithmEdDSA, now.Add(-time.Hour))
+	require.NoError(t, err)
+	expiredKey, err := utils.GenerateSigningKey(utils.AlgorithmRS256, now.Add(-48*time.Hour))
+	require.NoError(t, err)
+	expiredKey.RetiresAt = &expired
+	expiredKey.ExpiresAt = &expired
+
+	testTable := []struct {
+		name             string
+		keyRing          *utils.KeyRing
+		expectedKeyTypes map[string]string
+	}{
+		{
+			name:    "Asymmetric keys",
+			keyRing: utils.NewKeyRing(rsaKey, edKey, expiredKey),
+			expectedKeyTypes: map[string]string{
+				rsaKey.ID: "RSA",
+				edKey.ID:  "OKP",
+			},
+		},
+		{
+			name:             "HS256 keys are not published",
+			keyRing:          utils.NewHMACKeyRing("secret"),
+			expectedKeyTypes: map[string]string{},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler := NewJWKSHandler(testCase.keyRing)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.GET("/.well-known/jwks.json", handler.GetJWKS)
+
+			ctx.Request, _ = http.NewRequest("GET", "/.well-known/jwks.json", nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var set utils.JWKSet
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+
+			keyTypes := map[string]string{}
+			for _, key := range set.Keys {
+				keyTypes[key.KeyID] = key.KeyType
+				assert.Equal(t, "sig", key.Use)
+			}
+			assert.Equal(t, testCase.expectedKeyTypes, keyTypes)
+		})
+	}
+}
@@ -23,7 +23,7 @@ func NewListingHandler(listingService service.ListingServiceInterface) *ListingH
 
 // CreateListing создает новое объявление
 // @Summary Создать объявление
-// @Description Создает новое объявление для авторизованного пользователя
+// @Description Создает новое объявление для авторизованного пользователя. Изображения передаются массивом images (первое станет обложкой) в пределах лимита на объявление; image_url — устаревший способ задать одно изображение
 // @Tags listings
 // @Security Bearer
 // @Accept json
@@ -32,6 +32,7 @@ func NewListingHandler(listingService service.ListingServiceInterface) *ListingH
 // @Success 201 {object} utils.SuccessResponse{data=models.Listing}
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /listings [post]
 func (h *ListingHandler) CreateListing(c *gin.Context) {
@@ -55,10 +56,23 @@ func (h *ListingHandler) CreateListing(c *gin.Context) {
 			err.Error() == "price must be greater than 0" ||
 			err.Error() == "invalid image URL format" ||
 			err.Error() == "title must be less than 255 characters" ||
-			err.Error() == "image URL must be less than 500 characters" {
+			err.Error() == "image URL must be less than 500 characters" ||
+			err.Error() == "image_url cannot be combined with images" ||
+			err.Error() == "too many images" ||
+			err.Error() == "image must have either url or file_key" ||
+			err.Error() == "invalid image file_key" ||
+			err.Error() == "image file not found" ||
+			err.Error() == "alt text must be less than 255 characters" ||
+			err.Error() == "image width and height must be positive" ||
+			err.Error() == "category is required" ||
+			err.Error() == "category not found" {
 			utils.BadRequest(c, err.Error())
 			return
 		}
+		if err.Error() == "email verification required" {
+			utils.Forbidden(c, "Verify your email address before publishing listings")
+			return
+		}
 		utils.InternalError(c, "Failed to create listing")
 		return
 	}
@@ -68,16 +82,22 @@ func (h *ListingHandler) CreateListing(c *gin.Context) {
 
 // GetListings получает список объявлений
 // @Summary Получить список объявлений
-// @Description Возвращает список объявлений с возможностью фильтрации и пагинации
+// @Description Возвращает список объявлений с возможностью фильтрации и пагинации. С параметром facets в ответ добавляются агрегаты по всей выборке для боковой панели фильтров. Авторизация необязательна: если передан токен, заполняется is_owner
 // @Tags listings
 // @Accept json
 // @Produce json
 // @Param min_price query number false "Минимальная цена"
 // @Param max_price query number false "Максимальная цена"
-// @Param sort_by query string false "Поле для сортировки" Enums(created_at, price)
+// @Param category query string false "Slug категории; включает подкатегории"
+// @Param q query string false "Поисковый запрос: слова, фраза в кавычках, OR, -исключение"
+// @Param created_after query string false "Только объявления, созданные начиная с этого момента (RFC 3339)"
+// @Param facets query string false "Фасеты через запятую: category, price, seller, created_at"
+// @Param sort_by query string false "Поле для сортировки; relevance доступна только с q и используется с ним по умолчанию" Enums(created_at, price, relevance)
 // @Param sort_dir query string false "Направление сортировки" Enums(asc, desc)
 // @Param page query int false "Номер страницы" default(1)
 // @Param limit query int false "Количество элементов на странице" default(20)
+// @Param cursor query string false "Курсор next_cursor или prev_cursor из предыдущего ответа; не сочетается с page"
+// @Param with_total query bool false "Считать ли total; по умолчанию только без cursor"
 // @Success 200 {object} utils.SuccessResponse{data=models.PaginatedListings}
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
@@ -100,6 +120,11 @@ func (h *ListingHandler) GetListings(c *gin.Context) {
 		if err.Error() == "min_price cannot be negative" ||
 			err.Error() == "max_price cannot be negative" ||
 			err.Error() == "min_price cannot be greater than max_price" ||
+			err.Error() == "category not found" ||
+			err.Error() == "sort_by=relevance requires q" ||
+			err.Error() == "invalid cursor" ||
+			err.Error() == "cursor cannot be combined with page" ||
+			err.Error() == "facets must be a comma-separated list of category, price, seller, created_at" ||
 			err.Error() == "page must be greater than 0" ||
 			err.Error() == "limit must be between 1 and 100" {
 			utils.BadRequest(c, err.Error())
@@ -114,7 +139,7 @@ func (h *ListingHandler) GetListings(c *gin.Context) {
 
 // GetListing получает объявление по ID
 // @Summary Получить объявление по ID
-// @Description Возвращает детальную информацию об объявлении
+// @Description Возвращает детальную информацию об объявлении. Авторизация необязательна: если передан токен, заполняется is_owner
 // @Tags listings
 // @Accept json
 // @Produce json
@@ -156,7 +181,7 @@ func (h *ListingHandler) GetListing(c *gin.Context) {
 
 // UpdateListing обновляет объявление
 // @Summary Обновить объявление
-// @Description Обновляет объявление. Только владелец может редактировать свое объявление
+// @Description Обновляет объявление. Владелец может редактировать свое объявление, модераторы и администраторы — любое. Переданный массив images заменяет все изображения объявления
 // @Tags listings
 // @Security Bearer
 // @Accept json
@@ -171,7 +196,7 @@ func (h *ListingHandler) GetListing(c *gin.Context) {
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /listings/{id} [put]
 func (h *ListingHandler) UpdateListing(c *gin.Context) {
-	userID, exists := middleware.GetUserID(c)
+	actor, exists := currentActor(c)
 	if !exists {
 		utils.Unauthorized(c, "User not found in context")
 		return
@@ -191,7 +216,7 @@ func (h *ListingHandler) UpdateListing(c *gin.Context) {
 		return
 	}
 
-	listing, err := h.listingService.UpdateListing(id, userID, req)
+	listing, err := h.listingService.UpdateListing(id, actor, req)
 	if err != nil {
 		if err.Error() == "listing not found" {
 			utils.NotFound(c, "Listing not found")
@@ -208,6 +233,15 @@ func (h *ListingHandler) UpdateListing(c *gin.Context) {
 			err.Error() == "invalid image URL format" ||
 			err.Error() == "title must be less than 255 characters" ||
 			err.Error() == "image URL must be less than 500 characters" ||
+			err.Error() == "image_url cannot be combined with images" ||
+			err.Error() == "too many images" ||
+			err.Error() == "image must have either url or file_key" ||
+			err.Error() == "invalid image file_key" ||
+			err.Error() == "image file not found" ||
+			err.Error() == "alt text must be less than 255 characters" ||
+			err.Error() == "image width and height must be positive" ||
+			err.Error() == "category is required" ||
+			err.Error() == "category not found" ||
 			err.Error() == "no fields to update" {
 			utils.BadRequest(c, err.Error())
 			return
@@ -221,7 +255,7 @@ func (h *ListingHandler) UpdateListing(c *gin.Context) {
 
 // DeleteListing удаляет объявление
 // @Summary Удалить объявление
-// @Description Удаляет объявление. Только владелец может удалить свое объявление
+// @Description Удаляет объявление. Владелец может удалить свое объявление, модераторы и администраторы — любое
 // @Tags listings
 // @Security Bearer
 // @Accept json
@@ -235,7 +269,7 @@ func (h *ListingHandler) UpdateListing(c *gin.Context) {
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /listings/{id} [delete]
 func (h *ListingHandler) DeleteListing(c *gin.Context) {
-	userID, exists := middleware.GetUserID(c)
+	actor, exists := currentActor(c)
 	if !exists {
 		utils.Unauthorized(c, "User not found in context")
 		return
@@ -248,7 +282,7 @@ func (h *ListingHandler) DeleteListing(c *gin.Context) {
 		return
 	}
 
-	err = h.listingService.DeleteListing(id, userID)
+	err = h.listingService.DeleteListing(id, actor)
 	if err != nil {
 		if err.Error() == "listing not found" {
 			utils.NotFound(c, "Listing not found")
@@ -269,6 +303,169 @@ func (h *ListingHandler) DeleteListing(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, nil, "Listing deleted successfully")
 }
 
+// AddListingImage добавляет изображение объявления
+// @Summary Добавить изображение
+// @Description Добавляет изображение в конец списка изображений объявления. Нужно указать ровно одно из полей url и file_key. Количество изображений ограничено настройкой LISTINGS_MAX_IMAGES
+// @Tags listings
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Param image body models.ListingImageRequest true "Изображение"
+// @Success 201 {object} utils.SuccessResponse{data=models.ListingImage}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /listings/{id}/images [post]
+func (h *ListingHandler) AddListingImage(c *gin.Context) {
+	actor, exists := currentActor(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid listing ID")
+		return
+	}
+
+	var req models.ListingImageRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format: "+err.Error())
+		return
+	}
+
+	image, err := h.listingService.AddListingImage(id, actor, req)
+	if err != nil {
+		switch err.Error() {
+		case "listing not found":
+			utils.NotFound(c, "Listing not found")
+		case "access denied: you can only edit your own listings":
+			utils.Forbidden(c, "You can only edit your own listings")
+		case "invalid listing ID", "too many images", "image must have either url or file_key",
+			"invalid image URL format", "image URL must be less than 500 characters", "invalid image file_key",
+			"image file not found", "alt text must be less than 255 characters", "image width and height must be positive":
+			utils.BadRequest(c, err.Error())
+		default:
+			utils.InternalError(c, "Failed to add image")
+		}
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusCreated, image, "Image added successfully")
+}
+
+// DeleteListingImage удаляет изображение объявления
+// @Summary Удалить изображение
+// @Description Удаляет изображение объявления; следующие изображения сдвигаются, и при удалении обложки ее место занимает следующее
+// @Tags listings
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Param imageId path int true "ID изображения"
+// @Success 200 {object} utils.SuccessResponse{data=nil}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /listings/{id}/images/{imageId} [delete]
+func (h *ListingHandler) DeleteListingImage(c *gin.Context) {
+	actor, exists := currentActor(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid listing ID")
+		return
+	}
+
+	imageID, err := strconv.Atoi(c.Param("imageId"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid image ID")
+		return
+	}
+
+	if err := h.listingService.DeleteListingImage(id, imageID, actor); err != nil {
+		switch err.Error() {
+		case "listing not found":
+			utils.NotFound(c, "Listing not found")
+		case "image not found":
+			utils.NotFound(c, "Image not found")
+		case "access denied: you can only edit your own listings":
+			utils.Forbidden(c, "You can only edit your own listings")
+		case "invalid listing ID", "invalid image ID":
+			utils.BadRequest(c, err.Error())
+		default:
+			utils.InternalError(c, "Failed to delete image")
+		}
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, nil, "Image deleted successfully")
+}
+
+// ReorderListingImages меняет порядок изображений объявления
+// @Summary Изменить порядок изображений
+// @Description Расставляет изображения объявления в порядке image_ids; список должен содержать каждое изображение объявления ровно один раз. Первое изображение становится обложкой
+// @Tags listings
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Param order body models.ReorderListingImagesRequest true "Новый порядок изображений"
+// @Success 200 {object} utils.SuccessResponse{data=[]models.ListingImage}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /listings/{id}/images/order [put]
+func (h *ListingHandler) ReorderListingImages(c *gin.Context) {
+	actor, exists := currentActor(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid listing ID")
+		return
+	}
+
+	var req models.ReorderListingImagesRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format: "+err.Error())
+		return
+	}
+
+	images, err := h.listingService.ReorderListingImages(id, actor, req)
+	if err != nil {
+		switch err.Error() {
+		case "listing not found":
+			utils.NotFound(c, "Listing not found")
+		case "access denied: you can only edit your own listings":
+			utils.Forbidden(c, "You can only edit your own listings")
+		case "invalid listing ID", "image_ids must list every image of the listing exactly once":
+			utils.BadRequest(c, err.Error())
+		default:
+			utils.InternalError(c, "Failed to reorder images")
+		}
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, images, "Images reordered successfully")
+}
+
 // GetMyListings получает объявления текущего пользователя
 // @Summary Получить мои объявления
 // @Description Возвращает список объявлений текущего авторизованного пользователя
@@ -280,6 +477,8 @@ func (h *ListingHandler) DeleteListing(c *gin.Context) {
 // @Param sort_dir query string false "Направление сортировки" Enums(asc, desc)
 // @Param page query int false "Номер страницы" default(1)
 // @Param limit query int false "Количество элементов на странице" default(20)
+// @Param cursor query string false "Курсор next_cursor или prev_cursor из предыдущего ответа; не сочетается с page"
+// @Param with_total query bool false "Считать ли total; по умолчанию только без cursor"
 // @Success 200 {object} utils.SuccessResponse{data=models.PaginatedListings}
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
@@ -302,6 +501,9 @@ func (h *ListingHandler) GetMyListings(c *gin.Context) {
 	listings, err := h.listingService.GetUserListings(userID, filter)
 	if err != nil {
 		if err.Error() == "invalid user ID" ||
+			err.Error() == "sort_by=relevance requires q" ||
+			err.Error() == "invalid cursor" ||
+			err.Error() == "cursor cannot be combined with page" ||
 			err.Error() == "page must be greater than 0" ||
 			err.Error() == "limit must be between 1 and 100" {
 			utils.BadRequest(c, err.Error())
@@ -313,3 +515,65 @@ func (h *ListingHandler) GetMyListings(c *gin.Context) {
 
 	utils.SendSuccess(c, http.StatusOK, listings, "")
 }
+
+// GetSellerListings получает объявления продавца
+// @Summary Объявления продавца
+// @Description Возвращает объявления пользователя по его логину с теми же фильтрами, сортировкой и пагинацией, что и общий список. Авторизация необязательна: если передан токен, заполняется is_owner
+// @Tags users
+// @Produce json
+// @Param login path string true "Логин продавца"
+// @Param min_price query number false "Минимальная цена"
+// @Param max_price query number false "Максимальная цена"
+// @Param category query string false "Slug категории; включает подкатегории"
+// @Param q query string false "Поисковый запрос: слова, фраза в кавычках, OR, -исключение"
+// @Param created_after query string false "Только объявления, созданные начиная с этого момента (RFC 3339)"
+// @Param facets query string false "Фасеты через запятую: category, price, seller, created_at"
+// @Param sort_by query string false "Поле для сортировки; relevance доступна только с q и используется с ним по умолчанию" Enums(created_at, price, relevance)
+// @Param sort_dir query string false "Направление сортировки" Enums(asc, desc)
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(20)
+// @Param cursor query string false "Курсор next_cursor или prev_cursor из предыдущего ответа; не сочетается с page"
+// @Param with_total query bool false "Считать ли total; по умолчанию только без cursor"
+// @Success 200 {object} utils.SuccessResponse{data=models.PaginatedListings}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /users/{login}/listings [get]
+func (h *ListingHandler) GetSellerListings(c *gin.Context) {
+	var filter models.ListingsFilter
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequest(c, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	var currentUserID *int
+	if userID, exists := middleware.GetUserID(c); exists {
+		currentUserID = &userID
+	}
+
+	listings, err := h.listingService.GetSellerListings(c.Param("login"), filter, currentUserID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			utils.NotFound(c, "User not found")
+			return
+		}
+		if err.Error() == "min_price cannot be negative" ||
+			err.Error() == "max_price cannot be negative" ||
+			err.Error() == "min_price cannot be greater than max_price" ||
+			err.Error() == "category not found" ||
+			err.Error() == "sort_by=relevance requires q" ||
+			err.Error() == "invalid cursor" ||
+			err.Error() == "cursor cannot be combined with page" ||
+			err.Error() == "facets must be a comma-separated list of category, price, seller, created_at" ||
+			err.Error() == "page must be greater than 0" ||
+			err.Error() == "limit must be between 1 and 100" {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		utils.InternalError(c, "Failed to get listings")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, listings, "")
+}
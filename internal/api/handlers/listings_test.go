@@ -15,6 +15,7 @@ import (
 
 	"marketplace-api/internal/models"
 	mockservice "marketplace-api/internal/service/mocks"
+	"marketplace-api/pkg/rbac"
 )
 
 func stringPtr(s string) *string {
@@ -29,6 +30,13 @@ func float64Ptr(f float64) *float64 {
 	return &f
 }
 
+func roleOrDefault(role rbac.Role) rbac.Role {
+	if role == "" {
+		return rbac.RoleUser
+	}
+	return role
+}
+
 func TestListingHandler_CreateListing(t *testing.T) {
 	type mockBehavior func(s *mockservice.MockListingService, userID int, req models.CreateListingRequest)
 
@@ -41,14 +49,81 @@ func TestListingHandler_CreateListing(t *testing.T) {
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
+		{
+			name:        "OK with images",
+			requestBody: `{"title":"Bike","description":"Road bike","price":150,"category_id":3,"images":[{"url":"https://example.com/bike.jpg","alt_text":"Вид сбоку","width":1200,"height":800},{"file_key":"ab/cdef.jpg"}]}`,
+			userID:      1,
+			request: models.CreateListingRequest{
+				Title:       "Bike",
+				Description: "Road bike",
+				Price:       150,
+				CategoryID:  3,
+				Images: []models.ListingImageRequest{
+					{URL: stringPtr("https://example.com/bike.jpg"), AltText: "Вид сбоку", Width: intPtr(1200), Height: intPtr(800)},
+					{FileKey: stringPtr("ab/cdef.jpg")},
+				},
+			},
+			mockBehavior: func(s *mockservice.MockListingService, userID int, req models.CreateListingRequest) {
+				createdAt := time.Date(2025, 7, 21, 20, 28, 29, 0, time.UTC)
+				cover := models.ListingImage{ID: 10, ListingID: 1, URL: stringPtr("https://example.com/bike.jpg"), AltText: "Вид сбоку", Width: intPtr(1200), Height: intPtr(800), CreatedAt: createdAt}
+				listing := &models.Listing{
+					ID:          1,
+					Title:       "Bike",
+					Description: "Road bike",
+					Price:       150,
+					CategoryID:  3,
+					ImageURL:    cover.URL,
+					UserID:      1,
+					IsOwner:     true,
+					CreatedAt:   createdAt,
+					UpdatedAt:   createdAt,
+					Cover:       &cover,
+					Images: []models.ListingImage{
+						cover,
+						{ID: 11, ListingID: 1, FileKey: stringPtr("ab/cdef.jpg"), Position: 1, CreatedAt: createdAt},
+					},
+				}
+				s.EXPECT().CreateListing(userID, req).Return(listing, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"message":"Listing created successfully","data":{"id":1,"title":"Bike","description":"Road bike","price":150,"image_url":"https://example.com/bike.jpg","category_id":3,"user_id":1,"is_owner":true,"created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:28:29Z","cover":{"id":10,"listing_id":1,"url":"https://example.com/bike.jpg","position":0,"alt_text":"Вид сбоку","width":1200,"height":800,"created_at":"2025-07-21T20:28:29Z"},"images":[{"id":10,"listing_id":1,"url":"https://example.com/bike.jpg","position":0,"alt_text":"Вид сбоку","width":1200,"height":800,"created_at":"2025-07-21T20:28:29Z"},{"id":11,"listing_id":1,"url":null,"file_key":"ab/cdef.jpg","position":1,"alt_text":"","created_at":"2025-07-21T20:28:29Z"}]}}`,
+		},
+		{
+			name:        "Too many images",
+			requestBody: `{"title":"Bike","description":"Road bike","price":150,"category_id":3,"images":[{"url":"https://example.com/1.jpg"},{"url":"https://example.com/2.jpg"}]}`,
+			userID:      1,
+			request: models.CreateListingRequest{
+				Title:       "Bike",
+				Description: "Road bike",
+				Price:       150,
+				CategoryID:  3,
+				Images: []models.ListingImageRequest{
+					{URL: stringPtr("https://example.com/1.jpg")},
+					{URL: stringPtr("https://example.com/2.jpg")},
+				},
+			},
+			mockBehavior: func(s *mockservice.MockListingService, userID int, req models.CreateListingRequest) {
+				s.EXPECT().CreateListing(userID, req).Return(nil, errors.New("too many images"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"too many images"}`,
+		},
+		{
+			name:                 "Invalid image width",
+			requestBody:          `{"title":"Bike","description":"Road bike","price":150,"category_id":3,"images":[{"url":"https://example.com/1.jpg","width":0}]}`,
+			userID:               1,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format: Key: 'CreateListingRequest.Images[0].Width' Error:Field validation for 'Width' failed on the 'min' tag"}`,
+		},
 		{
 			name:        "OK with image URL",
-			requestBody: `{"title":"iPhone 15","description":"Brand new iPhone 15 Pro Max","price":120000.50,"image_url":"https://example.com/iphone15.jpg"}`,
+			requestBody: `{"title":"iPhone 15","description":"Brand new iPhone 15 Pro Max","price":120000.50,"category_id":3,"image_url":"https://example.com/iphone15.jpg"}`,
 			userID:      1,
 			request: models.CreateListingRequest{
 				Title:       "iPhone 15",
 				Description: "Brand new iPhone 15 Pro Max",
 				Price:       120000.50,
+				CategoryID:  3,
 				ImageURL:    stringPtr("https://example.com/iphone15.jpg"),
 			},
 			mockBehavior: func(s *mockservice.MockListingService, userID int, req models.CreateListingRequest) {
@@ -57,6 +132,7 @@ func TestListingHandler_CreateListing(t *testing.T) {
 					Title:       "iPhone 15",
 					Description: "Brand new iPhone 15 Pro Max",
 					Price:       120000.50,
+					CategoryID:  3,
 					ImageURL:    stringPtr("https://example.com/iphone15.jpg"),
 					UserID:      1,
 					CreatedAt:   time.Date(2025, 7, 21, 20, 28, 29, 0, time.UTC),
@@ -65,16 +141,17 @@ func TestListingHandler_CreateListing(t *testing.T) {
 				s.EXPECT().CreateListing(userID, req).Return(listing, nil)
 			},
 			expectedStatusCode:   http.StatusCreated,
-			expectedResponseBody: `{"message":"Listing created successfully","data":{"id":1,"title":"iPhone 15","description":"Brand new iPhone 15 Pro Max","price":120000.5,"image_url":"https://example.com/iphone15.jpg","user_id":1,"created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:28:29Z"}}`,
+			expectedResponseBody: `{"message":"Listing created successfully","data":{"id":1,"title":"iPhone 15","description":"Brand new iPhone 15 Pro Max","price":120000.5,"image_url":"https://example.com/iphone15.jpg","category_id":3,"user_id":1,"created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:28:29Z"}}`,
 		},
 		{
 			name:        "OK without image URL",
-			requestBody: `{"title":"MacBook Pro","description":"Latest MacBook Pro 16 inch","price":250000.00}`,
+			requestBody: `{"title":"MacBook Pro","description":"Latest MacBook Pro 16 inch","price":250000.00,"category_id":3}`,
 			userID:      1,
 			request: models.CreateListingRequest{
 				Title:       "MacBook Pro",
 				Description: "Latest MacBook Pro 16 inch",
 				Price:       250000.00,
+				CategoryID:  3,
 				ImageURL:    nil,
 			},
 			mockBehavior: func(s *mockservice.MockListingService, userID int, req models.CreateListingRequest) {
@@ -83,6 +160,7 @@ func TestListingHandler_CreateListing(t *testing.T) {
 					Title:       "MacBook Pro",
 					Description: "Latest MacBook Pro 16 inch",
 					Price:       250000.00,
+					CategoryID:  3,
 					ImageURL:    nil,
 					UserID:      1,
 					CreatedAt:   time.Date(2025, 7, 21, 20, 28, 29, 0, time.UTC),
@@ -91,11 +169,11 @@ func TestListingHandler_CreateListing(t *testing.T) {
 				s.EXPECT().CreateListing(userID, req).Return(listing, nil)
 			},
 			expectedStatusCode:   http.StatusCreated,
-			expectedResponseBody: `{"message":"Listing created successfully","data":{"id":2,"title":"MacBook Pro","description":"Latest MacBook Pro 16 inch","price":250000,"image_url":null,"user_id":1,"created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:28:29Z"}}`,
+			expectedResponseBody: `{"message":"Listing created successfully","data":{"id":2,"title":"MacBook Pro","description":"Latest MacBook Pro 16 inch","price":250000,"image_url":null,"category_id":3,"user_id":1,"created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:28:29Z"}}`,
 		},
 		{
 			name:                 "User not found in context",
-			requestBody:          `{"title":"iPhone 15","description":"Brand new iPhone 15","price":120000.00}`,
+			requestBody:          `{"title":"iPhone 15","description":"Brand new iPhone 15","price":120000.00,"category_id":3}`,
 			expectedStatusCode:   http.StatusUnauthorized,
 			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
 		},
@@ -108,19 +186,52 @@ func TestListingHandler_CreateListing(t *testing.T) {
 		},
 		{
 			name:                 "Missing title field",
-			requestBody:          `{"description":"Brand new iPhone 15","price":120000.00}`,
+			requestBody:          `{"description":"Brand new iPhone 15","price":120000.00,"category_id":3}`,
 			userID:               1,
 			expectedStatusCode:   http.StatusBadRequest,
 			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format: Key: 'CreateListingRequest.Title' Error:Field validation for 'Title' failed on the 'required' tag"}`,
 		},
+		{
+			name:        "Category not found",
+			requestBody: `{"title":"iPhone 15","description":"Brand new iPhone 15","price":120000.00,"category_id":999}`,
+			userID:      1,
+			request: models.CreateListingRequest{
+				Title:       "iPhone 15",
+				Description: "Brand new iPhone 15",
+				Price:       120000.00,
+				CategoryID:  999,
+			},
+			mockBehavior: func(s *mockservice.MockListingService, userID int, req models.CreateListingRequest) {
+				s.EXPECT().CreateListing(userID, req).Return(nil, errors.New("category not found"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"category not found"}`,
+		},
+		{
+			name:        "Email not verified",
+			requestBody: `{"title":"iPhone 15","description":"Brand new iPhone 15","price":120000.00,"category_id":3}`,
+			userID:      1,
+			request: models.CreateListingRequest{
+				Title:       "iPhone 15",
+				Description: "Brand new iPhone 15",
+				Price:       120000.00,
+				CategoryID:  3,
+			},
+			mockBehavior: func(s *mockservice.MockListingService, userID int, req models.CreateListingRequest) {
+				s.EXPECT().CreateListing(userID, req).Return(nil, errors.New("email verification required"))
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: `{"error":"forbidden", "message":"Verify your email address before publishing listings"}`,
+		},
 		{
 			name:        "Internal server error",
-			requestBody: `{"title":"iPhone 15","description":"Brand new iPhone 15","price":120000.00}`,
+			requestBody: `{"title":"iPhone 15","description":"Brand new iPhone 15","price":120000.00,"category_id":3}`,
 			userID:      1,
 			request: models.CreateListingRequest{
 				Title:       "iPhone 15",
 				Description: "Brand new iPhone 15",
 				Price:       120000.00,
+				CategoryID:  3,
 			},
 			mockBehavior: func(s *mockservice.MockListingService, userID int, req models.CreateListingRequest) {
 				s.EXPECT().CreateListing(userID, req).Return(nil, errors.New("database connection failed"))
@@ -168,6 +279,183 @@ func TestListingHandler_CreateListing(t *testing.T) {
 	}
 }
 
+func TestListingHandler_GetListings(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockListingService)
+
+	testTable := []struct {
+		name                 string
+		url                  string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Search with highlight",
+			url:  "/listings?q=%D0%B2%D0%B5%D0%BB%D0%BE%D1%81%D0%B8%D0%BF%D0%B5%D0%B4&category=sport",
+			mockBehavior: func(s *mockservice.MockListingService) {
+				filter := models.ListingsFilter{Query: "велосипед", Category: "sport"}
+				s.EXPECT().GetListings(filter, (*int)(nil)).Return(&models.PaginatedListings{
+					Data: []models.Listing{
+						{
+							ID:          3,
+							Title:       "Горный велосипед",
+							Description: "Велосипед <b>почти</b> новый",
+							Price:       150,
+							CategoryID:  5,
+							UserID:      2,
+							UserLogin:   "seller01",
+							CreatedAt:   time.Date(2025, 7, 21, 20, 28, 29, 0, time.UTC),
+							UpdatedAt:   time.Date(2025, 7, 21, 20, 28, 29, 0, time.UTC),
+							Highlight: &models.ListingHighlight{
+								Title:       "Горный <mark>велосипед</mark>",
+								Description: "<mark>Велосипед</mark> &lt;b&gt;почти&lt;/b&gt; новый",
+							},
+						},
+					},
+					Total:      intPtr(1),
+					Page:       1,
+					Limit:      20,
+					TotalPages: intPtr(1),
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":{"data":[{"id":3,"title":"Горный велосипед","description":"Велосипед <b>почти</b> новый","image_url":null,"price":150,"category_id":5,"user_id":2,"user_login":"seller01","created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:28:29Z","highlight":{"title":"Горный <mark>велосипед</mark>","description":"<mark>Велосипед</mark> &lt;b&gt;почти&lt;/b&gt; новый"}}],"total":1,"page":1,"limit":20,"total_pages":1}}`,
+		},
+		{
+			name: "With facets",
+			url:  "/listings?facets=category,price,created_at&created_after=2025-07-01T00:00:00Z",
+			mockBehavior: func(s *mockservice.MockListingService) {
+				createdAfter := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+				filter := models.ListingsFilter{Facets: "category,price,created_at", CreatedAfter: &createdAfter}
+				s.EXPECT().GetListings(filter, (*int)(nil)).Return(&models.PaginatedListings{
+					Data:       []models.Listing{},
+					Total:      intPtr(3),
+					Page:       1,
+					Limit:      20,
+					TotalPages: intPtr(1),
+					Facets: &models.ListingFacets{
+						Categories: []models.CategoryFacet{
+							{CategoryID: 5, Slug: "bicycles", Name: "Велосипеды", Count: 2},
+							{CategoryID: 1, Slug: "other", Name: "Другое", Count: 1},
+						},
+						Price: []models.PriceFacet{
+							{From: 100, To: 150, Count: 2},
+							{From: 150, To: 200, Count: 1},
+						},
+						CreatedAt: []models.DateRangeFacet{
+							{Key: "last_24h", From: time.Date(2025, 7, 20, 20, 0, 0, 0, time.UTC), Count: 1},
+							{Key: "last_7d", From: time.Date(2025, 7, 14, 20, 0, 0, 0, time.UTC), Count: 3},
+						},
+					},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":{"data":[],"total":3,"page":1,"limit":20,"total_pages":1,"facets":{"categories":[{"category_id":5,"slug":"bicycles","name":"Велосипеды","count":2},{"category_id":1,"slug":"other","name":"Другое","count":1}],"price":[{"from":100,"to":150,"count":2},{"from":150,"to":200,"count":1}],"created_at":[{"key":"last_24h","from":"2025-07-20T20:00:00Z","count":1},{"key":"last_7d","from":"2025-07-14T20:00:00Z","count":3}]}}}`,
+		},
+		{
+			name: "Unknown facet",
+			url:  "/listings?facets=color",
+			mockBehavior: func(s *mockservice.MockListingService) {
+				filter := models.ListingsFilter{Facets: "color"}
+				s.EXPECT().GetListings(filter, (*int)(nil)).Return(nil, errors.New("facets must be a comma-separated list of category, price, seller, created_at"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"facets must be a comma-separated list of category, price, seller, created_at"}`,
+		},
+		{
+			name: "Cursor page",
+			url:  "/listings?cursor=eyJpZCI6M30.sig&limit=1",
+			mockBehavior: func(s *mockservice.MockListingService) {
+				filter := models.ListingsFilter{Cursor: "eyJpZCI6M30.sig", Limit: 1}
+				s.EXPECT().GetListings(filter, (*int)(nil)).Return(&models.PaginatedListings{
+					Data: []models.Listing{
+						{
+							ID:          2,
+							Title:       "Bike",
+							Description: "Road bike",
+							Price:       150,
+							CategoryID:  3,
+							UserID:      2,
+							UserLogin:   "seller01",
+							CreatedAt:   time.Date(2025, 7, 21, 20, 28, 29, 0, time.UTC),
+							UpdatedAt:   time.Date(2025, 7, 21, 20, 28, 29, 0, time.UTC),
+						},
+					},
+					Limit:      1,
+					NextCursor: "eyJpZCI6Mn0.next",
+					PrevCursor: "eyJpZCI6MiwiYiI6dHJ1ZX0.prev",
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":{"data":[{"id":2,"title":"Bike","description":"Road bike","image_url":null,"price":150,"category_id":3,"user_id":2,"user_login":"seller01","created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:28:29Z"}],"limit":1,"next_cursor":"eyJpZCI6Mn0.next","prev_cursor":"eyJpZCI6MiwiYiI6dHJ1ZX0.prev"}}`,
+		},
+		{
+			name: "Invalid cursor",
+			url:  "/listings?cursor=forged",
+			mockBehavior: func(s *mockservice.MockListingService) {
+				filter := models.ListingsFilter{Cursor: "forged"}
+				s.EXPECT().GetListings(filter, (*int)(nil)).Return(nil, errors.New("invalid cursor"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"invalid cursor"}`,
+		},
+		{
+			name: "Relevance without query",
+			url:  "/listings?sort_by=relevance",
+			mockBehavior: func(s *mockservice.MockListingService) {
+				filter := models.ListingsFilter{SortBy: "relevance"}
+				s.EXPECT().GetListings(filter, (*int)(nil)).Return(nil, errors.New("sort_by=relevance requires q"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"sort_by=relevance requires q"}`,
+		},
+		{
+			name: "Unknown category",
+			url:  "/listings?category=spaceships",
+			mockBehavior: func(s *mockservice.MockListingService) {
+				filter := models.ListingsFilter{Category: "spaceships"}
+				s.EXPECT().GetListings(filter, (*int)(nil)).Return(nil, errors.New("category not found"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"category not found"}`,
+		},
+		{
+			name: "Internal server error",
+			url:  "/listings",
+			mockBehavior: func(s *mockservice.MockListingService) {
+				s.EXPECT().GetListings(models.ListingsFilter{}, (*int)(nil)).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to get listings"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			listingService := mockservice.NewMockListingService(c)
+			testCase.mockBehavior(listingService)
+
+			handler := NewListingHandler(listingService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.GET("/listings", handler.GetListings)
+
+			ctx.Request, _ = http.NewRequest("GET", testCase.url, nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
 func TestListingHandler_GetListing(t *testing.T) {
 	type mockBehavior func(s *mockservice.MockListingService, id int, currentUserID *int)
 
@@ -190,6 +478,7 @@ func TestListingHandler_GetListing(t *testing.T) {
 					Title:       "iPhone 15",
 					Description: "Brand new iPhone 15 Pro Max",
 					Price:       120000.00,
+					CategoryID:  3,
 					ImageURL:    stringPtr("https://example.com/iphone15.jpg"),
 					UserID:      1,
 					CreatedAt:   time.Date(2025, 7, 21, 20, 28, 29, 0, time.UTC),
@@ -198,7 +487,7 @@ func TestListingHandler_GetListing(t *testing.T) {
 				s.EXPECT().GetListingByID(id, currentUserID).Return(listing, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"data":{"id":1,"title":"iPhone 15","description":"Brand new iPhone 15 Pro Max","price":120000,"image_url":"https://example.com/iphone15.jpg","user_id":1,"created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:28:29Z"}}`,
+			expectedResponseBody: `{"data":{"id":1,"title":"iPhone 15","description":"Brand new iPhone 15 Pro Max","price":120000,"image_url":"https://example.com/iphone15.jpg","category_id":3,"user_id":1,"created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:28:29Z"}}`,
 		},
 		{
 			name:          "OK with user",
@@ -211,6 +500,7 @@ func TestListingHandler_GetListing(t *testing.T) {
 					Title:       "MacBook Pro",
 					Description: "16-inch MacBook Pro with M2 chip",
 					Price:       250000.00,
+					CategoryID:  3,
 					ImageURL:    nil,
 					UserID:      2,
 					CreatedAt:   time.Date(2025, 7, 21, 20, 28, 29, 0, time.UTC),
@@ -219,7 +509,7 @@ func TestListingHandler_GetListing(t *testing.T) {
 				s.EXPECT().GetListingByID(id, currentUserID).Return(listing, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"data":{"id":2,"title":"MacBook Pro","description":"16-inch MacBook Pro with M2 chip","price":250000,"image_url":null,"user_id":2,"created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:28:29Z"}}`,
+			expectedResponseBody: `{"data":{"id":2,"title":"MacBook Pro","description":"16-inch MacBook Pro with M2 chip","price":250000,"image_url":null,"category_id":3,"user_id":2,"created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:28:29Z"}}`,
 		},
 		{
 			name:                 "Invalid listing ID - non-numeric",
@@ -304,13 +594,14 @@ func TestListingHandler_GetListing(t *testing.T) {
 }
 
 func TestListingHandler_UpdateListing(t *testing.T) {
-	type mockBehavior func(s *mockservice.MockListingService, id int, userID int, req models.UpdateListingRequest)
+	type mockBehavior func(s *mockservice.MockListingService, id int, actor models.Actor, req models.UpdateListingRequest)
 
 	testTable := []struct {
 		name                 string
 		listingID            string
 		requestBody          string
 		userID               interface{}
+		role                 rbac.Role
 		request              models.UpdateListingRequest
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
@@ -327,21 +618,45 @@ func TestListingHandler_UpdateListing(t *testing.T) {
 				Price:       float64Ptr(130000.00),
 				ImageURL:    stringPtr("https://example.com/updated.jpg"),
 			},
-			mockBehavior: func(s *mockservice.MockListingService, id int, userID int, req models.UpdateListingRequest) {
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor, req models.UpdateListingRequest) {
 				listing := &models.Listing{
 					ID:          1,
 					Title:       "iPhone 15 Pro Updated",
 					Description: "Updated description",
 					Price:       130000.00,
+					CategoryID:  3,
 					ImageURL:    stringPtr("https://example.com/updated.jpg"),
 					UserID:      1,
 					CreatedAt:   time.Date(2025, 7, 21, 20, 28, 29, 0, time.UTC),
 					UpdatedAt:   time.Date(2025, 7, 21, 20, 30, 0, 0, time.UTC),
 				}
-				s.EXPECT().UpdateListing(id, userID, req).Return(listing, nil)
+				s.EXPECT().UpdateListing(id, actor, req).Return(listing, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"message":"Listing updated successfully","data":{"id":1,"title":"iPhone 15 Pro Updated","description":"Updated description","price":130000,"image_url":"https://example.com/updated.jpg","user_id":1,"created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:30:00Z"}}`,
+			expectedResponseBody: `{"message":"Listing updated successfully","data":{"id":1,"title":"iPhone 15 Pro Updated","description":"Updated description","price":130000,"image_url":"https://example.com/updated.jpg","category_id":3,"user_id":1,"created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:30:00Z"}}`,
+		},
+		{
+			name:                 "Invalid image in replacement",
+			listingID:            "1",
+			requestBody:          `{"images":[{"url":"not a url"}]}`,
+			userID:               1,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format: Key: 'UpdateListingRequest.Images[0].URL' Error:Field validation for 'URL' failed on the 'url' tag"}`,
+		},
+		{
+			name:        "Image URL combined with images",
+			listingID:   "1",
+			requestBody: `{"image_url":"https://example.com/a.jpg","images":[]}`,
+			userID:      1,
+			request: models.UpdateListingRequest{
+				ImageURL: stringPtr("https://example.com/a.jpg"),
+				Images:   &[]models.ListingImageRequest{},
+			},
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor, req models.UpdateListingRequest) {
+				s.EXPECT().UpdateListing(id, actor, req).Return(nil, errors.New("image_url cannot be combined with images"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"image_url cannot be combined with images"}`,
 		},
 		{
 			name:        "OK - partial update",
@@ -351,21 +666,22 @@ func TestListingHandler_UpdateListing(t *testing.T) {
 			request: models.UpdateListingRequest{
 				Price: float64Ptr(140000.00),
 			},
-			mockBehavior: func(s *mockservice.MockListingService, id int, userID int, req models.UpdateListingRequest) {
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor, req models.UpdateListingRequest) {
 				listing := &models.Listing{
 					ID:          2,
 					Title:       "MacBook Pro",
 					Description: "16-inch MacBook Pro",
 					Price:       140000.00,
+					CategoryID:  3,
 					ImageURL:    nil,
 					UserID:      1,
 					CreatedAt:   time.Date(2025, 7, 21, 20, 28, 29, 0, time.UTC),
 					UpdatedAt:   time.Date(2025, 7, 21, 20, 30, 0, 0, time.UTC),
 				}
-				s.EXPECT().UpdateListing(id, userID, req).Return(listing, nil)
+				s.EXPECT().UpdateListing(id, actor, req).Return(listing, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"message":"Listing updated successfully","data":{"id":2,"title":"MacBook Pro","description":"16-inch MacBook Pro","price":140000,"image_url":null,"user_id":1,"created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:30:00Z"}}`,
+			expectedResponseBody: `{"message":"Listing updated successfully","data":{"id":2,"title":"MacBook Pro","description":"16-inch MacBook Pro","price":140000,"image_url":null,"category_id":3,"user_id":1,"created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:30:00Z"}}`,
 		},
 		{
 			name:                 "User not found in context",
@@ -398,8 +714,8 @@ func TestListingHandler_UpdateListing(t *testing.T) {
 			request: models.UpdateListingRequest{
 				Title: stringPtr("Updated title"),
 			},
-			mockBehavior: func(s *mockservice.MockListingService, id int, userID int, req models.UpdateListingRequest) {
-				s.EXPECT().UpdateListing(id, userID, req).Return(nil, errors.New("listing not found"))
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor, req models.UpdateListingRequest) {
+				s.EXPECT().UpdateListing(id, actor, req).Return(nil, errors.New("listing not found"))
 			},
 			expectedStatusCode:   http.StatusNotFound,
 			expectedResponseBody: `{"error":"not_found", "message":"Listing not found"}`,
@@ -412,8 +728,8 @@ func TestListingHandler_UpdateListing(t *testing.T) {
 			request: models.UpdateListingRequest{
 				Title: stringPtr("Updated title"),
 			},
-			mockBehavior: func(s *mockservice.MockListingService, id int, userID int, req models.UpdateListingRequest) {
-				s.EXPECT().UpdateListing(id, userID, req).Return(nil, errors.New("access denied: you can only edit your own listings"))
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor, req models.UpdateListingRequest) {
+				s.EXPECT().UpdateListing(id, actor, req).Return(nil, errors.New("access denied: you can only edit your own listings"))
 			},
 			expectedStatusCode:   http.StatusForbidden,
 			expectedResponseBody: `{"error":"forbidden", "message":"You can only edit your own listings"}`,
@@ -426,8 +742,8 @@ func TestListingHandler_UpdateListing(t *testing.T) {
 			request: models.UpdateListingRequest{
 				Title: stringPtr("Updated title"),
 			},
-			mockBehavior: func(s *mockservice.MockListingService, id int, userID int, req models.UpdateListingRequest) {
-				s.EXPECT().UpdateListing(id, userID, req).Return(nil, errors.New("invalid listing ID"))
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor, req models.UpdateListingRequest) {
+				s.EXPECT().UpdateListing(id, actor, req).Return(nil, errors.New("invalid listing ID"))
 			},
 			expectedStatusCode:   http.StatusBadRequest,
 			expectedResponseBody: `{"error":"bad_request", "message":"invalid listing ID"}`,
@@ -438,8 +754,8 @@ func TestListingHandler_UpdateListing(t *testing.T) {
 			requestBody: `{}`,
 			userID:      1,
 			request:     models.UpdateListingRequest{},
-			mockBehavior: func(s *mockservice.MockListingService, id int, userID int, req models.UpdateListingRequest) {
-				s.EXPECT().UpdateListing(id, userID, req).Return(nil, errors.New("no fields to update"))
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor, req models.UpdateListingRequest) {
+				s.EXPECT().UpdateListing(id, actor, req).Return(nil, errors.New("no fields to update"))
 			},
 			expectedStatusCode:   http.StatusBadRequest,
 			expectedResponseBody: `{"error":"bad_request", "message":"no fields to update"}`,
@@ -452,8 +768,8 @@ func TestListingHandler_UpdateListing(t *testing.T) {
 			request: models.UpdateListingRequest{
 				Title: stringPtr("Updated title"),
 			},
-			mockBehavior: func(s *mockservice.MockListingService, id int, userID int, req models.UpdateListingRequest) {
-				s.EXPECT().UpdateListing(id, userID, req).Return(nil, errors.New("database connection failed"))
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor, req models.UpdateListingRequest) {
+				s.EXPECT().UpdateListing(id, actor, req).Return(nil, errors.New("database connection failed"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
 			expectedResponseBody: `{"error":"internal_error", "message":"Failed to update listing"}`,
@@ -470,7 +786,7 @@ func TestListingHandler_UpdateListing(t *testing.T) {
 			if testCase.mockBehavior != nil {
 				if id, err := strconv.Atoi(testCase.listingID); err == nil {
 					if userID, ok := testCase.userID.(int); ok {
-						testCase.mockBehavior(listingService, id, userID, testCase.request)
+						testCase.mockBehavior(listingService, id, models.Actor{UserID: userID, Role: roleOrDefault(testCase.role)}, testCase.request)
 					}
 				}
 			}
@@ -485,6 +801,9 @@ func TestListingHandler_UpdateListing(t *testing.T) {
 				if testCase.userID != nil {
 					ctx.Set("user_id", testCase.userID)
 				}
+				if testCase.role != "" {
+					ctx.Set("user_role", testCase.role)
+				}
 			})
 
 			r.PUT("/listings/:id", handler.UpdateListing)
@@ -502,12 +821,13 @@ func TestListingHandler_UpdateListing(t *testing.T) {
 }
 
 func TestListingHandler_DeleteListing(t *testing.T) {
-	type mockBehavior func(s *mockservice.MockListingService, id int, userID int)
+	type mockBehavior func(s *mockservice.MockListingService, id int, actor models.Actor)
 
 	testTable := []struct {
 		name                 string
 		listingID            string
 		userID               interface{}
+		role                 rbac.Role
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
@@ -516,8 +836,8 @@ func TestListingHandler_DeleteListing(t *testing.T) {
 			name:      "OK",
 			listingID: "1",
 			userID:    1,
-			mockBehavior: func(s *mockservice.MockListingService, id int, userID int) {
-				s.EXPECT().DeleteListing(id, userID).Return(nil)
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor) {
+				s.EXPECT().DeleteListing(id, actor).Return(nil)
 			},
 			expectedStatusCode:   http.StatusOK,
 			expectedResponseBody: `{"message":"Listing deleted successfully"}`,
@@ -539,18 +859,29 @@ func TestListingHandler_DeleteListing(t *testing.T) {
 			name:      "Listing not found",
 			listingID: "999",
 			userID:    1,
-			mockBehavior: func(s *mockservice.MockListingService, id int, userID int) {
-				s.EXPECT().DeleteListing(id, userID).Return(errors.New("listing not found"))
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor) {
+				s.EXPECT().DeleteListing(id, actor).Return(errors.New("listing not found"))
 			},
 			expectedStatusCode:   http.StatusNotFound,
 			expectedResponseBody: `{"error":"not_found", "message":"Listing not found"}`,
 		},
+		{
+			name:      "OK - moderator deletes another user's listing",
+			listingID: "1",
+			userID:    3,
+			role:      rbac.RoleModerator,
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor) {
+				s.EXPECT().DeleteListing(id, models.Actor{UserID: 3, Role: rbac.RoleModerator}).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Listing deleted successfully"}`,
+		},
 		{
 			name:      "Access denied - not owner",
 			listingID: "1",
 			userID:    2,
-			mockBehavior: func(s *mockservice.MockListingService, id int, userID int) {
-				s.EXPECT().DeleteListing(id, userID).Return(errors.New("access denied: you can only delete your own listings"))
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor) {
+				s.EXPECT().DeleteListing(id, actor).Return(errors.New("access denied: you can only delete your own listings"))
 			},
 			expectedStatusCode:   http.StatusForbidden,
 			expectedResponseBody: `{"error":"forbidden", "message":"You can only delete your own listings"}`,
@@ -559,8 +890,8 @@ func TestListingHandler_DeleteListing(t *testing.T) {
 			name:      "Invalid listing ID from service",
 			listingID: "-1",
 			userID:    1,
-			mockBehavior: func(s *mockservice.MockListingService, id int, userID int) {
-				s.EXPECT().DeleteListing(id, userID).Return(errors.New("invalid listing ID"))
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor) {
+				s.EXPECT().DeleteListing(id, actor).Return(errors.New("invalid listing ID"))
 			},
 			expectedStatusCode:   http.StatusBadRequest,
 			expectedResponseBody: `{"error":"bad_request", "message":"invalid listing ID"}`,
@@ -569,8 +900,8 @@ func TestListingHandler_DeleteListing(t *testing.T) {
 			name:      "Zero listing ID",
 			listingID: "0",
 			userID:    1,
-			mockBehavior: func(s *mockservice.MockListingService, id int, userID int) {
-				s.EXPECT().DeleteListing(id, userID).Return(errors.New("invalid listing ID"))
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor) {
+				s.EXPECT().DeleteListing(id, actor).Return(errors.New("invalid listing ID"))
 			},
 			expectedStatusCode:   http.StatusBadRequest,
 			expectedResponseBody: `{"error":"bad_request", "message":"invalid listing ID"}`,
@@ -579,8 +910,8 @@ func TestListingHandler_DeleteListing(t *testing.T) {
 			name:      "Internal server error",
 			listingID: "1",
 			userID:    1,
-			mockBehavior: func(s *mockservice.MockListingService, id int, userID int) {
-				s.EXPECT().DeleteListing(id, userID).Return(errors.New("database connection failed"))
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor) {
+				s.EXPECT().DeleteListing(id, actor).Return(errors.New("database connection failed"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
 			expectedResponseBody: `{"error":"internal_error", "message":"Failed to delete listing"}`,
@@ -597,7 +928,7 @@ func TestListingHandler_DeleteListing(t *testing.T) {
 			if testCase.mockBehavior != nil {
 				if id, err := strconv.Atoi(testCase.listingID); err == nil {
 					if userID, ok := testCase.userID.(int); ok {
-						testCase.mockBehavior(listingService, id, userID)
+						testCase.mockBehavior(listingService, id, models.Actor{UserID: userID, Role: roleOrDefault(testCase.role)})
 					}
 				}
 			}
@@ -612,6 +943,9 @@ func TestListingHandler_DeleteListing(t *testing.T) {
 				if testCase.userID != nil {
 					ctx.Set("user_id", testCase.userID)
 				}
+				if testCase.role != "" {
+					ctx.Set("user_role", testCase.role)
+				}
 			})
 
 			r.DELETE("/listings/:id", handler.DeleteListing)
@@ -626,3 +960,405 @@ func TestListingHandler_DeleteListing(t *testing.T) {
 		})
 	}
 }
+
+func TestListingHandler_AddListingImage(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockListingService, id int, actor models.Actor, req models.ListingImageRequest)
+
+	testTable := []struct {
+		name                 string
+		listingID            string
+		requestBody          string
+		userID               interface{}
+		request              models.ListingImageRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			listingID:   "1",
+			requestBody: `{"url":"https://example.com/bike.jpg","alt_text":"Руль"}`,
+			userID:      1,
+			request:     models.ListingImageRequest{URL: stringPtr("https://example.com/bike.jpg"), AltText: "Руль"},
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor, req models.ListingImageRequest) {
+				s.EXPECT().AddListingImage(id, actor, req).Return(&models.ListingImage{
+					ID:        12,
+					ListingID: 1,
+					URL:       stringPtr("https://example.com/bike.jpg"),
+					Position:  2,
+					AltText:   "Руль",
+					CreatedAt: time.Date(2025, 7, 21, 20, 28, 29, 0, time.UTC),
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"message":"Image added successfully","data":{"id":12,"listing_id":1,"url":"https://example.com/bike.jpg","position":2,"alt_text":"Руль","created_at":"2025-07-21T20:28:29Z"}}`,
+		},
+		{
+			name:        "Too many images",
+			listingID:   "1",
+			requestBody: `{"file_key":"ab/cdef.jpg"}`,
+			userID:      1,
+			request:     models.ListingImageRequest{FileKey: stringPtr("ab/cdef.jpg")},
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor, req models.ListingImageRequest) {
+				s.EXPECT().AddListingImage(id, actor, req).Return(nil, errors.New("too many images"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"too many images"}`,
+		},
+		{
+			name:        "No image source",
+			listingID:   "1",
+			requestBody: `{"alt_text":"Руль"}`,
+			userID:      1,
+			request:     models.ListingImageRequest{AltText: "Руль"},
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor, req models.ListingImageRequest) {
+				s.EXPECT().AddListingImage(id, actor, req).Return(nil, errors.New("image must have either url or file_key"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"image must have either url or file_key"}`,
+		},
+		{
+			name:        "Not owner",
+			listingID:   "1",
+			requestBody: `{"url":"https://example.com/bike.jpg"}`,
+			userID:      2,
+			request:     models.ListingImageRequest{URL: stringPtr("https://example.com/bike.jpg")},
+			mockBehavior: func(s *mockservice.MockListingService, id int, actor models.Actor, req models.ListingImageRequest) {
+				s.EXPECT().AddListingImage(id, actor, req).Return(nil, errors.New("access denied: you can only edit your own listings"))
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: `{"error":"forbidden", "message":"You can only edit your own listings"}`,
+		},
+		{
+			name:                 "User not found in context",
+			listingID:            "1",
+			requestBody:          `{"url":"https://example.com/bike.jpg"}`,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			listingService := mockservice.NewMockListingService(c)
+
+			if testCase.mockBehavior != nil {
+				id, _ := strconv.Atoi(testCase.listingID)
+				testCase.mockBehavior(listingService, id, models.Actor{UserID: testCase.userID.(int), Role: roleOrDefault("")}, testCase.request)
+			}
+
+			handler := NewListingHandler(listingService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+			})
+
+			r.POST("/listings/:id/images", handler.AddListingImage)
+
+			ctx.Request, _ = http.NewRequest("POST", "/listings/"+testCase.listingID+"/images", bytes.NewBufferString(testCase.requestBody))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestListingHandler_DeleteListingImage(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockListingService, actor models.Actor)
+
+	testTable := []struct {
+		name                 string
+		url                  string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			url:  "/listings/1/images/10",
+			mockBehavior: func(s *mockservice.MockListingService, actor models.Actor) {
+				s.EXPECT().DeleteListingImage(1, 10, actor).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Image deleted successfully"}`,
+		},
+		{
+			name: "Image not found",
+			url:  "/listings/1/images/99",
+			mockBehavior: func(s *mockservice.MockListingService, actor models.Actor) {
+				s.EXPECT().DeleteListingImage(1, 99, actor).Return(errors.New("image not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"not_found", "message":"Image not found"}`,
+		},
+		{
+			name:                 "Invalid image ID",
+			url:                  "/listings/1/images/abc",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid image ID"}`,
+		},
+		{
+			name: "Internal server error",
+			url:  "/listings/1/images/10",
+			mockBehavior: func(s *mockservice.MockListingService, actor models.Actor) {
+				s.EXPECT().DeleteListingImage(1, 10, actor).Return(errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to delete image"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			listingService := mockservice.NewMockListingService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(listingService, models.Actor{UserID: 1, Role: roleOrDefault("")})
+			}
+
+			handler := NewListingHandler(listingService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				ctx.Set("user_id", 1)
+			})
+
+			r.DELETE("/listings/:id/images/:imageId", handler.DeleteListingImage)
+
+			ctx.Request, _ = http.NewRequest("DELETE", testCase.url, nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestListingHandler_ReorderListingImages(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockListingService, actor models.Actor)
+
+	testTable := []struct {
+		name                 string
+		requestBody          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			requestBody: `{"image_ids":[11,10]}`,
+			mockBehavior: func(s *mockservice.MockListingService, actor models.Actor) {
+				createdAt := time.Date(2025, 7, 21, 20, 28, 29, 0, time.UTC)
+				s.EXPECT().ReorderListingImages(1, actor, models.ReorderListingImagesRequest{ImageIDs: []int{11, 10}}).Return([]models.ListingImage{
+					{ID: 11, ListingID: 1, URL: stringPtr("https://example.com/b.jpg"), Position: 0, CreatedAt: createdAt},
+					{ID: 10, ListingID: 1, URL: stringPtr("https://example.com/a.jpg"), Position: 1, CreatedAt: createdAt},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Images reordered successfully","data":[{"id":11,"listing_id":1,"url":"https://example.com/b.jpg","position":0,"alt_text":"","created_at":"2025-07-21T20:28:29Z"},{"id":10,"listing_id":1,"url":"https://example.com/a.jpg","position":1,"alt_text":"","created_at":"2025-07-21T20:28:29Z"}]}`,
+		},
+		{
+			name:        "Incomplete order",
+			requestBody: `{"image_ids":[11]}`,
+			mockBehavior: func(s *mockservice.MockListingService, actor models.Actor) {
+				s.EXPECT().ReorderListingImages(1, actor, models.ReorderListingImagesRequest{ImageIDs: []int{11}}).
+					Return(nil, errors.New("image_ids must list every image of the listing exactly once"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"image_ids must list every image of the listing exactly once"}`,
+		},
+		{
+			name:                 "Empty order",
+			requestBody:          `{"image_ids":[]}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format: Key: 'ReorderListingImagesRequest.ImageIDs' Error:Field validation for 'ImageIDs' failed on the 'min' tag"}`,
+		},
+		{
+			name:        "Listing not found",
+			requestBody: `{"image_ids":[10]}`,
+			mockBehavior: func(s *mockservice.MockListingService, actor models.Actor) {
+				s.EXPECT().ReorderListingImages(1, actor, models.ReorderListingImagesRequest{ImageIDs: []int{10}}).Return(nil, errors.New("listing not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"not_found", "message":"Listing not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			listingService := mockservice.NewMockListingService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(listingService, models.Actor{UserID: 1, Role: roleOrDefault("")})
+			}
+
+			handler := NewListingHandler(listingService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				ctx.Set("user_id", 1)
+			})
+
+			r.PUT("/listings/:id/images/order", handler.ReorderListingImages)
+
+			ctx.Request, _ = http.NewRequest("PUT", "/listings/1/images/order", bytes.NewBufferString(testCase.requestBody))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestListingHandler_GetSellerListings(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockListingService)
+
+	testTable := []struct {
+		name                 string
+		url                  string
+		userID               interface{}
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			url:  "/users/seller01/listings?sort_by=price&sort_dir=asc",
+			mockBehavior: func(s *mockservice.MockListingService) {
+				filter := models.ListingsFilter{SortBy: "price", SortDir: "asc"}
+				s.EXPECT().GetSellerListings("seller01", filter, (*int)(nil)).Return(&models.PaginatedListings{
+					Data: []models.Listing{
+						{
+							ID:          3,
+							Title:       "Bike",
+							Description: "Road bike",
+							Price:       150,
+							CategoryID:  3,
+							UserID:      2,
+							UserLogin:   "seller01",
+							CreatedAt:   time.Date(2025, 7, 21, 20, 28, 29, 0, time.UTC),
+							UpdatedAt:   time.Date(2025, 7, 21, 20, 28, 29, 0, time.UTC),
+						},
+					},
+					Total:      intPtr(1),
+					Page:       1,
+					Limit:      20,
+					TotalPages: intPtr(1),
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":{"data":[{"id":3,"title":"Bike","description":"Road bike","image_url":null,"price":150,"category_id":3,"user_id":2,"user_login":"seller01","created_at":"2025-07-21T20:28:29Z","updated_at":"2025-07-21T20:28:29Z"}],"total":1,"page":1,"limit":20,"total_pages":1}}`,
+		},
+		{
+			name:   "OK with user",
+			url:    "/users/seller01/listings",
+			userID: 2,
+			mockBehavior: func(s *mockservice.MockListingService) {
+				s.EXPECT().GetSellerListings("seller01", models.ListingsFilter{}, intPtr(2)).Return(&models.PaginatedListings{
+					Data:       []models.Listing{},
+					Total:      intPtr(0),
+					Page:       1,
+					Limit:      20,
+					TotalPages: intPtr(0),
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":{"data":[],"total":0,"page":1,"limit":20,"total_pages":0}}`,
+		},
+		{
+			name:                 "Invalid query parameters",
+			url:                  "/users/seller01/listings?sort_by=title",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid query parameters: Key: 'ListingsFilter.SortBy' Error:Field validation for 'SortBy' failed on the 'oneof' tag"}`,
+		},
+		{
+			name: "Invalid price range",
+			url:  "/users/seller01/listings?min_price=100&max_price=10",
+			mockBehavior: func(s *mockservice.MockListingService) {
+				filter := models.ListingsFilter{MinPrice: float64Ptr(100), MaxPrice: float64Ptr(10)}
+				s.EXPECT().GetSellerListings("seller01", filter, (*int)(nil)).Return(nil, errors.New("min_price cannot be greater than max_price"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"min_price cannot be greater than max_price"}`,
+		},
+		{
+			name: "User not found",
+			url:  "/users/ghost/listings",
+			mockBehavior: func(s *mockservice.MockListingService) {
+				s.EXPECT().GetSellerListings("ghost", models.ListingsFilter{}, (*int)(nil)).Return(nil, errors.New("user not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"not_found", "message":"User not found"}`,
+		},
+		{
+			name: "Internal server error",
+			url:  "/users/seller01/listings",
+			mockBehavior: func(s *mockservice.MockListingService) {
+				s.EXPECT().GetSellerListings("seller01", models.ListingsFilter{}, (*int)(nil)).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to get listings"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			listingService := mockservice.NewMockListingService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(listingService)
+			}
+
+			handler := NewListingHandler(listingService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+			})
+
+			r.GET("/users/:login/listings", handler.GetSellerListings)
+
+			ctx.Request, _ = http.NewRequest("GET", testCase.url, nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/internal/models"
+	"marketplace-api/internal/service"
+	"marketplace-api/pkg/middleware"
+	"marketplace-api/pkg/utils"
+)
+
+type OIDCHandler struct {
+	oidcService service.OIDCServiceInterface
+}
+
+func NewOIDCHandler(oidcService service.OIDCServiceInterface) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+	}
+}
+
+// Authorize начинает вход через внешнего провайдера
+// @Summary Вход через провайдера OpenID Connect
+// @Description Возвращает адрес страницы входа у провайдера. После входа провайдер перенаправит пользователя на OIDC_REDIRECT_URL с параметрами code и state
+// @Tags auth
+// @Produce json
+// @Success 200 {object} utils.SuccessResponse{data=models.OIDCAuthorization}
+// @Failure 500 {object} utils.ErrorResponse
+// @Failure 502 {object} utils.ErrorResponse
+// @Router /auth/oidc/authorize [get]
+func (h *OIDCHandler) Authorize(c *gin.Context) {
+	authorization, err := h.oidcService.AuthorizationURL()
+	if err != nil {
+		if err.Error() == "oidc provider unavailable" {
+			utils.BadGateway(c, "Identity provider is unavailable")
+			return
+		}
+
+		utils.InternalError(c, "Failed to start external login")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, authorization, "Authorization URL created")
+}
+
+// Callback завершает вход через внешнего провайдера
+// @Summary Завершение входа через провайдера OpenID Connect
+// @Description Обменивает код авторизации на токены. Учетная запись провайдера привязывается к пользователю с тем же подтвержденным email, иначе создается новый пользователь. При включенном втором факторе возвращается two_factor, как в /auth/login
+// @Tags auth
+// @Produce json
+// @Param code query string false "Код авторизации"
+// @Param state query string true "Значение state из адреса авторизации"
+// @Param error query string false "Ошибка, возвращенная провайдером"
+// @Success 200 {object} models.LoginResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Failure 502 {object} utils.ErrorResponse
+// @Router /auth/oidc/callback [get]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	var req models.OIDCCallbackRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	response, err := h.oidcService.Callback(req, clientInfo(c))
+	if err != nil {
+		switch err.Error() {
+		case "oidc state invalid":
+			utils.BadRequest(c, "Invalid or expired state")
+		case "oidc login failed":
+			utils.Unauthorized(c, "External login failed")
+		case "oidc provider unavailable":
+			utils.BadGateway(c, "Identity provider is unavailable")
+		case "email already in use":
+			utils.Conflict(c, "Email is already used by another account. Sign in with password and verify your email to link the identity")
+		case "email is required":
+			utils.BadRequest(c, "Identity provider did not return an email")
+		default:
+			utils.InternalError(c, "Login failed")
+		}
+		return
+	}
+
+	if response.TwoFactor != nil {
+		utils.SendSuccess(c, http.StatusOK, response, "Two-factor authentication required")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, response, "Login successful")
+}
+
+// ListIdentities возвращает привязанные учетные записи провайдеров
+// @Summary Привязанные учетные записи провайдеров
+// @Description Возвращает учетные записи внешних провайдеров, через которые можно войти в аккаунт
+// @Tags auth
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.SuccessResponse{data=[]models.UserIdentity}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/identities [get]
+func (h *OIDCHandler) ListIdentities(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	identities, err := h.oidcService.ListIdentities(userID)
+	if err != nil {
+		utils.InternalError(c, "Failed to get identities")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, identities, "Identities retrieved successfully")
+}
@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"marketplace-api/internal/models"
+	mockservice "marketplace-api/internal/service/mocks"
+)
+
+func TestOIDCHandler_Authorize(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockOIDCService)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mockservice.MockOIDCService) {
+				s.EXPECT().AuthorizationURL().Return(&models.OIDCAuthorization{
+					AuthorizationURL: "https://id.example.com/authorize?state=abc",
+					ExpiresAt:        time.Date(2025, 7, 21, 20, 6, 37, 0, time.UTC),
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Authorization URL created","data":{"authorization_url":"https://id.example.com/authorize?state=abc","expires_at":"2025-07-21T20:06:37Z"}}`,
+		},
+		{
+			name: "Provider unavailable",
+			mockBehavior: func(s *mockservice.MockOIDCService) {
+				s.EXPECT().AuthorizationURL().Return(nil, errors.New("oidc provider unavailable"))
+			},
+			expectedStatusCode:   http.StatusBadGateway,
+			expectedResponseBody: `{"error":"bad_gateway", "message":"Identity provider is unavailable"}`,
+		},
+		{
+			name: "Internal server error",
+			mockBehavior: func(s *mockservice.MockOIDCService) {
+				s.EXPECT().AuthorizationURL().Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to start external login"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			oidcService := mockservice.NewMockOIDCService(c)
+			testCase.mockBehavior(oidcService)
+
+			handler := NewOIDCHandler(oidcService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.GET("/auth/oidc/authorize", handler.Authorize)
+
+			ctx.Request, _ = http.NewRequest("GET", "/auth/oidc/authorize", nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestOIDCHandler_Callback(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockOIDCService, req models.OIDCCallbackRequest)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		request              models.OIDCCallbackRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:    "OK",
+			query:   "?code=auth-code&state=abc",
+			request: models.OIDCCallbackRequest{Code: "auth-code", State: "abc"},
+			mockBehavior: func(s *mockservice.MockOIDCService, req models.OIDCCallbackRequest) {
+				response := &models.AuthResponse{
+					User: models.User{
+						ID:        1,
+						Login:     "alice",
+						CreatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+						UpdatedAt: time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+					},
+					Token:                 "jwt.token.here",
+					TokenExpiresAt:        time.Date(2025, 7, 21, 20, 11, 37, 0, time.UTC),
+					RefreshToken:          "refresh.token.here",
+					RefreshTokenExpiresAt: time.Date(2025, 8, 20, 19, 56, 37, 0, time.UTC),
+				}
+				s.EXPECT().Callback(req, testClient).Return(&models.LoginResponse{AuthResponse: response}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Login successful","data":{"user":{"id":1,"login":"alice","created_at":"2025-07-21T19:56:37Z","updated_at":"2025-07-21T19:56:37Z"},"token":"jwt.token.here","token_expires_at":"2025-07-21T20:11:37Z","refresh_token":"refresh.token.here","refresh_token_expires_at":"2025-08-20T19:56:37Z"}}`,
+		},
+		{
+			name:    "Two-factor required",
+			query:   "?code=auth-code&state=abc",
+			request: models.OIDCCallbackRequest{Code: "auth-code", State: "abc"},
+			mockBehavior: func(s *mockservice.MockOIDCService, req models.OIDCCallbackRequest) {
+				response := &models.LoginResponse{
+					TwoFactor: &models.TwoFactorChallenge{
+						ChallengeToken: "challenge.token.here",
+						ExpiresAt:      time.Date(2025, 7, 21, 20, 1, 37, 0, time.UTC),
+					},
+				}
+				s.EXPECT().Callback(req, testClient).Return(response, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Two-factor authentication required","data":{"two_factor":{"challenge_token":"challenge.token.here","expires_at":"2025-07-21T20:01:37Z"}}}`,
+		},
+		{
+			name:                 "Missing state",
+			query:                "?code=auth-code",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
+		},
+		{
+			name:    "Invalid state",
+			query:   "?code=auth-code&state=abc",
+			request: models.OIDCCallbackRequest{Code: "auth-code", State: "abc"},
+			mockBehavior: func(s *mockservice.MockOIDCService, req models.OIDCCallbackRequest) {
+				s.EXPECT().Callback(req, testClient).Return(nil, errors.New("oidc state invalid"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid or expired state"}`,
+		},
+		{
+			name:    "Denied by provider",
+			query:   "?error=access_denied&error_description=User+cancelled&state=abc",
+			request: models.OIDCCallbackRequest{State: "abc", Error: "access_denied", ErrorDescription: "User cancelled"},
+			mockBehavior: func(s *mockservice.MockOIDCService, req models.OIDCCallbackRequest) {
+				s.EXPECT().Callback(req, testClient).Return(nil, errors.New("oidc login failed"))
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"External login failed"}`,
+		},
+		{
+			name:    "Provider unavailable",
+			query:   "?code=auth-code&state=abc",
+			request: models.OIDCCallbackRequest{Code: "auth-code", State: "abc"},
+			mockBehavior: func(s *mockservice.MockOIDCService, req models.OIDCCallbackRequest) {
+				s.EXPECT().Callback(req, testClient).Return(nil, errors.New("oidc provider unavailable"))
+			},
+			expectedStatusCode:   http.StatusBadGateway,
+			expectedResponseBody: `{"error":"bad_gateway", "message":"Identity provider is unavailable"}`,
+		},
+		{
+			name:    "Email used by unverified account",
+			query:   "?code=auth-code&state=abc",
+			request: models.OIDCCallbackRequest{Code: "auth-code", State: "abc"},
+			mockBehavior: func(s *mockservice.MockOIDCService, req models.OIDCCallbackRequest) {
+				s.EXPECT().Callback(req, testClient).Return(nil, errors.New("email already in use"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"conflict", "message":"Email is already used by another account. Sign in with password and verify your email to link the identity"}`,
+		},
+		{
+			name:    "Email required",
+			query:   "?code=auth-code&state=abc",
+			request: models.OIDCCallbackRequest{Code: "auth-code", State: "abc"},
+			mockBehavior: func(s *mockservice.MockOIDCService, req models.OIDCCallbackRequest) {
+				s.EXPECT().Callback(req, testClient).Return(nil, errors.New("email is required"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Identity provider did not return an email"}`,
+		},
+		{
+			name:    "Internal server error",
+			query:   "?code=auth-code&state=abc",
+			request: models.OIDCCallbackRequest{Code: "auth-code", State: "abc"},
+			mockBehavior: func(s *mockservice.MockOIDCService, req models.OIDCCallbackRequest) {
+				s.EXPECT().Callback(req, testClient).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Login failed"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			oidcService := mockservice.NewMockOIDCService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(oidcService, testCase.request)
+			}
+
+			handler := NewOIDCHandler(oidcService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.GET("/auth/oidc/callback", handler.Callback)
+
+			ctx.Request, _ = http.NewRequest("GET", "/auth/oidc/callback"+testCase.query, nil)
+			setTestClient(ctx.Request)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestOIDCHandler_ListIdentities(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockOIDCService)
+
+	testTable := []struct {
+		name                 string
+		userID               interface{}
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockOIDCService) {
+				identities := []models.UserIdentity{
+					{
+						ID:          3,
+						UserID:      1,
+						Issuer:      "https://id.example.com",
+						Subject:     "user-42",
+						Email:       "alice@example.com",
+						LastLoginAt: time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+						CreatedAt:   time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+					},
+				}
+				s.EXPECT().ListIdentities(1).Return(identities, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Identities retrieved successfully","data":[{"id":3,"issuer":"https://id.example.com","subject":"user-42","email":"alice@example.com","last_login_at":"2025-07-22T10:00:00Z","created_at":"2025-07-21T19:56:37Z"}]}`,
+		},
+		{
+			name:                 "User not found in context",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:   "Internal server error",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockOIDCService) {
+				s.EXPECT().ListIdentities(1).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to get identities"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			oidcService := mockservice.NewMockOIDCService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(oidcService)
+			}
+
+			handler := NewOIDCHandler(oidcService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+			})
+
+			r.GET("/auth/identities", handler.ListIdentities)
+
+			ctx.Request, _ = http.NewRequest("GET", "/auth/identities", nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
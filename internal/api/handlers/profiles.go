@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/internal/models"
+	"marketplace-api/internal/service"
+	"marketplace-api/pkg/middleware"
+	"marketplace-api/pkg/utils"
+)
+
+type ProfileHandler struct {
+	profileService service.ProfileServiceInterface
+}
+
+func NewProfileHandler(profileService service.ProfileServiceInterface) *ProfileHandler {
+	return &ProfileHandler{
+		profileService: profileService,
+	}
+}
+
+// GetMyProfile возвращает профиль текущего пользователя
+// @Summary Мой профиль
+// @Description Возвращает профиль текущего пользователя, включая контакты, скрытые от других пользователей
+// @Tags users
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.SuccessResponse{data=models.UserProfile}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /users/me [get]
+func (h *ProfileHandler) GetMyProfile(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	profile, err := h.profileService.GetProfile(userID)
+	if err != nil {
+		utils.InternalError(c, "Failed to get profile")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, profile, "")
+}
+
+// UpdateMyProfile изменяет профиль текущего пользователя
+// @Summary Изменить профиль
+// @Description Изменяет отображаемое имя, описание, аватар, город и настройки связи. Незаданные поля не меняются, пустая строка очищает поле. Предпочтительный способ связи должен быть виден в публичном профиле
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body models.UpdateProfileRequest true "Изменяемые поля профиля"
+// @Success 200 {object} utils.SuccessResponse{data=models.UserProfile}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /users/me [put]
+func (h *ProfileHandler) UpdateMyProfile(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	var req models.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	profile, err := h.profileService.UpdateProfile(userID, req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid avatar URL format", "invalid phone format", "preferred contact must be visible":
+			utils.BadRequest(c, err.Error())
+		case "email verification required":
+			utils.Forbidden(c, "Verify your email address before making it your preferred contact")
+		default:
+			utils.InternalError(c, "Failed to update profile")
+		}
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, profile, "Profile updated successfully")
+}
+
+// GetPublicProfile возвращает публичный профиль продавца
+// @Summary Профиль продавца
+// @Description Возвращает публичный профиль пользователя по логину. Email и телефон показываются, только если пользователь разрешил их показ
+// @Tags users
+// @Produce json
+// @Param login path string true "Логин пользователя"
+// @Success 200 {object} utils.SuccessResponse{data=models.PublicProfile}
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /users/{login} [get]
+func (h *ProfileHandler) GetPublicProfile(c *gin.Context) {
+	profile, err := h.profileService.GetPublicProfile(c.Param("login"))
+	if err != nil {
+		if err.Error() == "user not found" {
+			utils.NotFound(c, "User not found")
+			return
+		}
+
+		utils.InternalError(c, "Failed to get profile")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, profile, "")
+}
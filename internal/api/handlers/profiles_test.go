@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"marketplace-api/internal/models"
+	mockservice "marketplace-api/internal/service/mocks"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestProfileHandler_GetMyProfile(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockProfileService)
+
+	testTable := []struct {
+		name                 string
+		userID               interface{}
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockProfileService) {
+				s.EXPECT().GetProfile(1).Return(&models.UserProfile{
+					DisplayName: "Ivan",
+					Phone:       "+7 900 000-00-00",
+					ShowPhone:   false,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":{"display_name":"Ivan","bio":"","avatar_url":null,"location":"","phone":"+7 900 000-00-00","show_email":false,"show_phone":false,"preferred_contact":""}}`,
+		},
+		{
+			name:                 "User not found in context",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:   "Internal server error",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockProfileService) {
+				s.EXPECT().GetProfile(1).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to get profile"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			profileService := mockservice.NewMockProfileService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(profileService)
+			}
+
+			handler := NewProfileHandler(profileService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+			})
+
+			r.GET("/users/me", handler.GetMyProfile)
+
+			ctx.Request, _ = http.NewRequest("GET", "/users/me", nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestProfileHandler_UpdateMyProfile(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockProfileService, req models.UpdateProfileRequest)
+
+	updatedAt := time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                 string
+		userID               interface{}
+		requestBody          string
+		request              models.UpdateProfileRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			userID:      1,
+			requestBody: `{"display_name":"Ivan","phone":"+7 900 000-00-00","show_phone":true,"preferred_contact":"phone"}`,
+			request: models.UpdateProfileRequest{
+				DisplayName:      stringPtr("Ivan"),
+				Phone:            stringPtr("+7 900 000-00-00"),
+				ShowPhone:        boolPtr(true),
+				PreferredContact: stringPtr("phone"),
+			},
+			mockBehavior: func(s *mockservice.MockProfileService, req models.UpdateProfileRequest) {
+				s.EXPECT().UpdateProfile(1, req).Return(&models.UserProfile{
+					DisplayName:      "Ivan",
+					Phone:            "+7 900 000-00-00",
+					ShowPhone:        true,
+					PreferredContact: "phone",
+					UpdatedAt:        &updatedAt,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Profile updated successfully","data":{"display_name":"Ivan","bio":"","avatar_url":null,"location":"","phone":"+7 900 000-00-00","show_email":false,"show_phone":true,"preferred_contact":"phone","updated_at":"2025-07-22T10:00:00Z"}}`,
+		},
+		{
+			name:                 "Invalid request format",
+			userID:               1,
+			requestBody:          `{"display_name":}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
+		},
+		{
+			name:                 "Unknown preferred contact",
+			userID:               1,
+			requestBody:          `{"preferred_contact":"telegram"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
+		},
+		{
+			name:        "Invalid phone",
+			userID:      1,
+			requestBody: `{"phone":"call me"}`,
+			request:     models.UpdateProfileRequest{Phone: stringPtr("call me")},
+			mockBehavior: func(s *mockservice.MockProfileService, req models.UpdateProfileRequest) {
+				s.EXPECT().UpdateProfile(1, req).Return(nil, errors.New("invalid phone format"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"invalid phone format"}`,
+		},
+		{
+			name:        "Preferred contact hidden",
+			userID:      1,
+			requestBody: `{"preferred_contact":"email"}`,
+			request:     models.UpdateProfileRequest{PreferredContact: stringPtr("email")},
+			mockBehavior: func(s *mockservice.MockProfileService, req models.UpdateProfileRequest) {
+				s.EXPECT().UpdateProfile(1, req).Return(nil, errors.New("preferred contact must be visible"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"preferred contact must be visible"}`,
+		},
+		{
+			name:        "Email not verified",
+			userID:      1,
+			requestBody: `{"show_email":true,"preferred_contact":"email"}`,
+			request:     models.UpdateProfileRequest{ShowEmail: boolPtr(true), PreferredContact: stringPtr("email")},
+			mockBehavior: func(s *mockservice.MockProfileService, req models.UpdateProfileRequest) {
+				s.EXPECT().UpdateProfile(1, req).Return(nil, errors.New("email verification required"))
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: `{"error":"forbidden", "message":"Verify your email address before making it your preferred contact"}`,
+		},
+		{
+			name:                 "User not found in context",
+			requestBody:          `{"display_name":"Ivan"}`,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:        "Internal server error",
+			userID:      1,
+			requestBody: `{"display_name":"Ivan"}`,
+			request:     models.UpdateProfileRequest{DisplayName: stringPtr("Ivan")},
+			mockBehavior: func(s *mockservice.MockProfileService, req models.UpdateProfileRequest) {
+				s.EXPECT().UpdateProfile(1, req).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to update profile"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			profileService := mockservice.NewMockProfileService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(profileService, testCase.request)
+			}
+
+			handler := NewProfileHandler(profileService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+			})
+
+			r.PUT("/users/me", handler.UpdateMyProfile)
+
+			ctx.Request, _ = http.NewRequest("PUT", "/users/me", bytes.NewBufferString(testCase.requestBody))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestProfileHandler_GetPublicProfile(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockProfileService)
+
+	testTable := []struct {
+		name                 string
+		login                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "OK",
+			login: "seller01",
+			mockBehavior: func(s *mockservice.MockProfileService) {
+				s.EXPECT().GetPublicProfile("seller01").Return(&models.PublicProfile{
+					ID:               2,
+					Login:            "seller01",
+					DisplayName:      "Ivan",
+					Location:         "Moscow",
+					Phone:            "+7 900 000-00-00",
+					PreferredContact: "phone",
+					ListingsCount:    3,
+					CreatedAt:        time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":{"id":2,"login":"seller01","display_name":"Ivan","bio":"","avatar_url":null,"location":"Moscow","phone":"+7 900 000-00-00","preferred_contact":"phone","listings_count":3,"created_at":"2025-07-21T19:56:37Z"}}`,
+		},
+		{
+			name:  "User not found",
+			login: "ghost",
+			mockBehavior: func(s *mockservice.MockProfileService) {
+				s.EXPECT().GetPublicProfile("ghost").Return(nil, errors.New("user not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"not_found", "message":"User not found"}`,
+		},
+		{
+			name:  "Internal server error",
+			login: "seller01",
+			mockBehavior: func(s *mockservice.MockProfileService) {
+				s.EXPECT().GetPublicProfile("seller01").Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to get profile"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			profileService := mockservice.NewMockProfileService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(profileService)
+			}
+
+			handler := NewProfileHandler(profileService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.GET("/users/:login", handler.GetPublicProfile)
+
+			ctx.Request, _ = http.NewRequest("GET", "/users/"+testCase.login, nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/internal/models"
+	"marketplace-api/internal/service"
+	"marketplace-api/pkg/middleware"
+	"marketplace-api/pkg/utils"
+)
+
+type SecurityEventHandler struct {
+	securityEventService service.SecurityEventServiceInterface
+}
+
+func NewSecurityEventHandler(securityEventService service.SecurityEventServiceInterface) *SecurityEventHandler {
+	return &SecurityEventHandler{
+		securityEventService: securityEventService,
+	}
+}
+
+// ListMyEvents получает журнал безопасности текущего пользователя
+// @Summary Журнал безопасности
+// @Description Возвращает постраничный журнал событий аккаунта, начиная с последних: входы и неудачные попытки входа, смены пароля, завершения сеансов, API-ключи и действия администраторов с аккаунтом. Время задается в формате RFC 3339, from включительно, to не включительно
+// @Tags users
+// @Security Bearer
+// @Produce json
+// @Param type query string false "Тип события" Enums(login_succeeded, login_failed, registered, password_changed, password_reset_requested, password_reset, logout, logout_all, session_revoked, refresh_token_reused, api_key_created, api_key_revoked, role_changed, user_unlocked, impersonation_started, two_factor_enabled, two_factor_disabled, recovery_code_used, email_changed, account_deletion_requested, account_deletion_cancelled, account_purged, category_created, category_updated, category_deleted)
+// @Param from query string false "Начало периода"
+// @Param to query string false "Конец периода"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(20)
+// @Success 200 {object} utils.SuccessResponse{data=models.PaginatedSecurityEvents}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /users/me/security-events [get]
+func (h *SecurityEventHandler) ListMyEvents(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	var filter models.SecurityEventsFilter
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequest(c, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	events, err := h.securityEventService.ListUserEvents(userID, filter)
+	if err != nil {
+		h.handleListError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, events, "")
+}
+
+// SearchEvents ищет события по журналу безопасности
+// @Summary Поиск по журналу безопасности
+// @Description Возвращает постраничный журнал безопасности всех пользователей, начиная с последних событий. Фильтр login находит и неудачные попытки входа с этим логином, и события аккаунта с таким логином. Доступно администраторам
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param user_id query int false "Фильтр по пользователю"
+// @Param actor_id query int false "Фильтр по администратору, выполнившему действие"
+// @Param login query string false "Фильтр по логину"
+// @Param ip_address query string false "Фильтр по IP-адресу"
+// @Param request_id query string false "Фильтр по идентификатору запроса"
+// @Param type query string false "Тип события"
+// @Param from query string false "Начало периода"
+// @Param to query string false "Конец периода"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(20)
+// @Success 200 {object} utils.SuccessResponse{data=models.PaginatedSecurityEvents}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/security-events [get]
+func (h *SecurityEventHandler) SearchEvents(c *gin.Context) {
+	var filter models.AdminSecurityEventsFilter
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequest(c, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	events, err := h.securityEventService.SearchEvents(filter)
+	if err != nil {
+		h.handleListError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, events, "")
+}
+
+func (h *SecurityEventHandler) handleListError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "invalid event type", "invalid time range":
+		utils.BadRequest(c, err.Error())
+	default:
+		utils.InternalError(c, "Failed to get security events")
+	}
+}
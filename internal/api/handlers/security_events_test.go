@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"marketplace-api/internal/models"
+	mockservice "marketplace-api/internal/service/mocks"
+)
+
+func TestSecurityEventHandler_ListMyEvents(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockSecurityEventService, userID int, filter models.SecurityEventsFilter)
+
+	from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                 string
+		userID               interface{}
+		query                string
+		filter               models.SecurityEventsFilter
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			query:  "?type=login_failed&from=2025-07-01T00:00:00Z&limit=10",
+			filter: models.SecurityEventsFilter{Type: models.SecurityEventLoginFailed, From: &from, Limit: 10},
+			mockBehavior: func(s *mockservice.MockSecurityEventService, userID int, filter models.SecurityEventsFilter) {
+				events := &models.PaginatedSecurityEvents{
+					Data: []models.SecurityEvent{
+						{
+							ID:        12,
+							Type:      models.SecurityEventLoginFailed,
+							UserID:    1,
+							IPAddress: "203.0.113.7",
+							UserAgent: "marketplace-tests/1.0",
+							RequestID: "3f2a9c1e",
+							Details:   map[string]string{"reason": "invalid_password"},
+							CreatedAt: time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+						},
+					},
+					Total:      1,
+					Page:       1,
+					Limit:      10,
+					TotalPages: 1,
+				}
+				s.EXPECT().ListUserEvents(userID, filter).Return(events, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":{"data":[{"id":12,"type":"login_failed","user_id":1,"ip_address":"203.0.113.7","user_agent":"marketplace-tests/1.0","request_id":"3f2a9c1e","details":{"reason":"invalid_password"},"created_at":"2025-07-22T10:00:00Z"}],"total":1,"page":1,"limit":10,"total_pages":1}}`,
+		},
+		{
+			name:                 "User not found in context",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:                 "Invalid time",
+			userID:               1,
+			query:                "?from=yesterday",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid query parameters: parsing time \"yesterday\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"yesterday\" as \"2006\""}`,
+		},
+		{
+			name:   "Invalid event type",
+			userID: 1,
+			query:  "?type=unknown",
+			filter: models.SecurityEventsFilter{Type: "unknown"},
+			mockBehavior: func(s *mockservice.MockSecurityEventService, userID int, filter models.SecurityEventsFilter) {
+				s.EXPECT().ListUserEvents(userID, filter).Return(nil, errors.New("invalid event type"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"invalid event type"}`,
+		},
+		{
+			name:   "Internal server error",
+			userID: 1,
+			filter: models.SecurityEventsFilter{},
+			mockBehavior: func(s *mockservice.MockSecurityEventService, userID int, filter models.SecurityEventsFilter) {
+				s.EXPECT().ListUserEvents(userID, filter).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to get security events"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			securityEventService := mockservice.NewMockSecurityEventService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(securityEventService, 1, testCase.filter)
+			}
+
+			handler := NewSecurityEventHandler(securityEventService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.GET("/users/me/security-events", func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+				handler.ListMyEvents(ctx)
+			})
+
+			ctx.Request, _ = http.NewRequest("GET", "/users/me/security-events"+testCase.query, nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestSecurityEventHandler_SearchEvents(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockSecurityEventService, filter models.AdminSecurityEventsFilter)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		filter               models.AdminSecurityEventsFilter
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "OK",
+			query: "?login=ghost&ip_address=203.0.113.7",
+			filter: models.AdminSecurityEventsFilter{
+				Login:     "ghost",
+				IPAddress: "203.0.113.7",
+			},
+			mockBehavior: func(s *mockservice.MockSecurityEventService, filter models.AdminSecurityEventsFilter) {
+				events := &models.PaginatedSecurityEvents{
+					Data: []models.SecurityEvent{
+						{
+							ID:        15,
+							Type:      models.SecurityEventLoginFailed,
+							Login:     "ghost",
+							IPAddress: "203.0.113.7",
+							UserAgent: "curl/8.5.0",
+							Details:   map[string]string{"reason": "unknown_login"},
+							CreatedAt: time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+						},
+					},
+					Total:      1,
+					Page:       1,
+					Limit:      20,
+					TotalPages: 1,
+				}
+				s.EXPECT().SearchEvents(filter).Return(events, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":{"data":[{"id":15,"type":"login_failed","login":"ghost","ip_address":"203.0.113.7","user_agent":"curl/8.5.0","details":{"reason":"unknown_login"},"created_at":"2025-07-22T10:00:00Z"}],"total":1,"page":1,"limit":20,"total_pages":1}}`,
+		},
+		{
+			name:                 "Invalid IP address",
+			query:                "?ip_address=localhost",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid query parameters: Key: 'AdminSecurityEventsFilter.IPAddress' Error:Field validation for 'IPAddress' failed on the 'ip' tag"}`,
+		},
+		{
+			name:   "Invalid time range",
+			query:  "?from=2025-07-22T00:00:00Z&to=2025-07-01T00:00:00Z",
+			filter: adminEventsFilterWithRange(time.Date(2025, 7, 22, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)),
+			mockBehavior: func(s *mockservice.MockSecurityEventService, filter models.AdminSecurityEventsFilter) {
+				s.EXPECT().SearchEvents(filter).Return(nil, errors.New("invalid time range"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"invalid time range"}`,
+		},
+		{
+			name:   "Internal server error",
+			filter: models.AdminSecurityEventsFilter{},
+			mockBehavior: func(s *mockservice.MockSecurityEventService, filter models.AdminSecurityEventsFilter) {
+				s.EXPECT().SearchEvents(filter).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to get security events"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			securityEventService := mockservice.NewMockSecurityEventService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(securityEventService, testCase.filter)
+			}
+
+			handler := NewSecurityEventHandler(securityEventService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.GET("/admin/security-events", handler.SearchEvents)
+
+			ctx.Request, _ = http.NewRequest("GET", "/admin/security-events"+testCase.query, nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func adminEventsFilterWithRange(from, to time.Time) models.AdminSecurityEventsFilter {
+	var filter models.AdminSecurityEventsFilter
+	filter.From = &from
+	filter.To = &to
+	return filter
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/internal/service"
+	"marketplace-api/pkg/middleware"
+	"marketplace-api/pkg/utils"
+)
+
+type SessionHandler struct {
+	sessionService service.SessionServiceInterface
+}
+
+func NewSessionHandler(sessionService service.SessionServiceInterface) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// ListSessions возвращает активные сеансы текущего пользователя
+// @Summary Активные сеансы
+// @Description Возвращает сеансы входа пользователя с user agent, IP-адресом, временем входа и последней активности. Сеанс текущего запроса отмечен current
+// @Tags auth
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.SuccessResponse{data=[]models.Session}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/sessions [get]
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	currentSessionID := 0
+	if claims, ok := middleware.GetTokenClaims(c); ok {
+		currentSessionID = claims.SessionID
+	}
+
+	sessions, err := h.sessionService.ListSessions(userID, currentSessionID)
+	if err != nil {
+		utils.InternalError(c, "Failed to get sessions")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, sessions, "Sessions retrieved successfully")
+}
+
+// RevokeSession завершает сеанс
+// @Summary Завершить сеанс
+// @Description Завершает сеанс пользователя на другом устройстве: его refresh-токены отзываются, access-токены перестают приниматься
+// @Tags auth
+// @Security Bearer
+// @Produce json
+// @Param id path int true "ID сеанса"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/sessions/{id} [delete]
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid session ID")
+		return
+	}
+
+	if err := h.sessionService.RevokeSession(userID, id, clientInfo(c)); err != nil {
+		if err.Error() == "session not found" {
+			utils.NotFound(c, "Session not found")
+			return
+		}
+
+		utils.InternalError(c, "Failed to revoke session")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, nil, "Session revoked")
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"marketplace-api/internal/models"
+	mockservice "marketplace-api/internal/service/mocks"
+	"marketplace-api/pkg/utils"
+)
+
+func TestSessionHandler_ListSessions(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockSessionService)
+
+	testTable := []struct {
+		name                 string
+		userID               interface{}
+		claims               *utils.Claims
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			claims: &utils.Claims{UserID: 1, SessionID: 5},
+			mockBehavior: func(s *mockservice.MockSessionService) {
+				sessions := []models.Session{
+					{
+						ID:         5,
+						UserID:     1,
+						UserAgent:  "Mozilla/5.0",
+						IPAddress:  "203.0.113.7",
+						ExpiresAt:  time.Date(2025, 8, 21, 10, 0, 0, 0, time.UTC),
+						LastSeenAt: time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+						CreatedAt:  time.Date(2025, 7, 21, 19, 56, 37, 0, time.UTC),
+						Current:    true,
+					},
+				}
+				s.EXPECT().ListSessions(1, 5).Return(sessions, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Sessions retrieved successfully","data":[{"id":5,"user_agent":"Mozilla/5.0","ip_address":"203.0.113.7","expires_at":"2025-08-21T10:00:00Z","last_seen_at":"2025-07-22T10:00:00Z","created_at":"2025-07-21T19:56:37Z","current":true}]}`,
+		},
+		{
+			name:   "Token without session",
+			userID: 1,
+			claims: &utils.Claims{UserID: 1},
+			mockBehavior: func(s *mockservice.MockSessionService) {
+				s.EXPECT().ListSessions(1, 0).Return([]models.Session{}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Sessions retrieved successfully","data":[]}`,
+		},
+		{
+			name:                 "User not found in context",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:   "Internal server error",
+			userID: 1,
+			claims: &utils.Claims{UserID: 1, SessionID: 5},
+			mockBehavior: func(s *mockservice.MockSessionService) {
+				s.EXPECT().ListSessions(1, 5).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to get sessions"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sessionService := mockservice.NewMockSessionService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(sessionService)
+			}
+
+			handler := NewSessionHandler(sessionService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+				if testCase.claims != nil {
+					ctx.Set("token_claims", testCase.claims)
+				}
+			})
+
+			r.GET("/auth/sessions", handler.ListSessions)
+
+			ctx.Request, _ = http.NewRequest("GET", "/auth/sessions", nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestSessionHandler_RevokeSession(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockSessionService, userID, sessionID int)
+
+	testTable := []struct {
+		name                 string
+		sessionParam         string
+		sessionID            int
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:         "OK",
+			sessionParam: "5",
+			sessionID:    5,
+			mockBehavior: func(s *mockservice.MockSessionService, userID, sessionID int) {
+				s.EXPECT().RevokeSession(userID, sessionID, testClient).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Session revoked"}`,
+		},
+		{
+			name:                 "Invalid ID",
+			sessionParam:         "abc",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid session ID"}`,
+		},
+		{
+			name:         "Not found",
+			sessionParam: "99",
+			sessionID:    99,
+			mockBehavior: func(s *mockservice.MockSessionService, userID, sessionID int) {
+				s.EXPECT().RevokeSession(userID, sessionID, testClient).Return(errors.New("session not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"not_found", "message":"Session not found"}`,
+		},
+		{
+			name:         "Internal server error",
+			sessionParam: "5",
+			sessionID:    5,
+			mockBehavior: func(s *mockservice.MockSessionService, userID, sessionID int) {
+				s.EXPECT().RevokeSession(userID, sessionID, testClient).Return(errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to revoke session"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sessionService := mockservice.NewMockSessionService(c)
+
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(sessionService, 1, testCase.sessionID)
+			}
+
+			handler := NewSessionHandler(sessionService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				ctx.Set("user_id", 1)
+			})
+
+			r.DELETE("/auth/sessions/:id", handler.RevokeSession)
+
+			ctx.Request, _ = http.NewRequest("DELETE", "/auth/sessions/"+testCase.sessionParam, nil)
+			setTestClient(ctx.Request)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
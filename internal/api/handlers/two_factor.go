@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/internal/models"
+	"marketplace-api/internal/service"
+	"marketplace-api/pkg/middleware"
+	"marketplace-api/pkg/utils"
+)
+
+type TwoFactorHandler struct {
+	twoFactorService service.TwoFactorServiceInterface
+}
+
+func NewTwoFactorHandler(twoFactorService service.TwoFactorServiceInterface) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+	}
+}
+
+// Enroll начинает подключение двухфакторной аутентификации
+// @Summary Подключение TOTP
+// @Description Создает секрет TOTP и возвращает otpauth URI и QR-код (PNG в base64). Второй фактор включается после подтверждения кодом
+// @Tags auth
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.SuccessResponse{data=models.TwoFactorEnrollment}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/2fa/enroll [post]
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	enrollment, err := h.twoFactorService.Enroll(userID)
+	if err != nil {
+		if err.Error() == "two-factor already enabled" {
+			utils.Conflict(c, "Two-factor authentication is already enabled")
+			return
+		}
+
+		utils.InternalError(c, "Failed to start two-factor enrollment")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, enrollment, "Scan the QR code and confirm with a code from the app")
+}
+
+// Confirm включает двухфакторную аутентификацию
+// @Summary Подтверждение TOTP
+// @Description Включает второй фактор после проверки первого кода и возвращает одноразовые коды восстановления. Коды показываются один раз
+// @Tags auth
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body models.TwoFactorCodeRequest true "Код из приложения-аутентификатора"
+// @Success 200 {object} utils.SuccessResponse{data=models.RecoveryCodesResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/2fa/confirm [post]
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	codes, err := h.twoFactorService.Confirm(userID, req, clientInfo(c))
+	if err != nil {
+		if err.Error() == "invalid two-factor code" {
+			utils.BadRequest(c, "Invalid two-factor code")
+			return
+		}
+		if err.Error() == "two-factor enrollment not started" {
+			utils.BadRequest(c, "Two-factor enrollment has not been started")
+			return
+		}
+		if err.Error() == "two-factor already enabled" {
+			utils.Conflict(c, "Two-factor authentication is already enabled")
+			return
+		}
+
+		utils.InternalError(c, "Failed to enable two-factor authentication")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, codes, "Two-factor authentication enabled")
+}
+
+// Disable отключает двухфакторную аутентификацию
+// @Summary Отключение TOTP
+// @Description Отключает второй фактор после проверки пароля и кода TOTP или кода восстановления
+// @Tags auth
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body models.DisableTwoFactorRequest true "Пароль и код"
+// @Success 200 {object} utils.SuccessResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /auth/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	var req models.DisableTwoFactorRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request format")
+		return
+	}
+
+	if err := h.twoFactorService.Disable(userID, req, clientInfo(c)); err != nil {
+		if err.Error() == "two-factor not enabled" {
+			utils.BadRequest(c, "Two-factor authentication is not enabled")
+			return
+		}
+		if err.Error() == "invalid current password" {
+			utils.BadRequest(c, "Current password is incorrect")
+			return
+		}
+		if err.Error() == "invalid two-factor code" {
+			utils.BadRequest(c, "Invalid two-factor code")
+			return
+		}
+
+		utils.InternalError(c, "Failed to disable two-factor authentication")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, nil, "Two-factor authentication disabled")
+}
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"marketplace-api/internal/models"
+	mockservice "marketplace-api/internal/service/mocks"
+)
+
+func TestTwoFactorHandler_Enroll(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockTwoFactorService, userID int)
+
+	testTable := []struct {
+		name                 string
+		userID               interface{}
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockTwoFactorService, userID int) {
+				enrollment := &models.TwoFactorEnrollment{
+					Secret:     "JBSWY3DPEHPK3PXP",
+					OTPAuthURI: "otpauth://totp/Marketplace:artificial00?issuer=Marketplace&secret=JBSWY3DPEHPK3PXP",
+					QRCodePNG:  []byte{0x89, 0x50, 0x4e, 0x47},
+				}
+				s.EXPECT().Enroll(userID).Return(enrollment, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Scan the QR code and confirm with a code from the app","data":{"secret":"JBSWY3DPEHPK3PXP","otpauth_uri":"otpauth://totp/Marketplace:artificial00?issuer=Marketplace&secret=JBSWY3DPEHPK3PXP","qr_code_png":"iVBORw=="}}`,
+		},
+		{
+			name:                 "User not found in context",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:   "Already enabled",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockTwoFactorService, userID int) {
+				s.EXPECT().Enroll(userID).Return(nil, errors.New("two-factor already enabled"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"conflict", "message":"Two-factor authentication is already enabled"}`,
+		},
+		{
+			name:   "Internal server error",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockTwoFactorService, userID int) {
+				s.EXPECT().Enroll(userID).Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to start two-factor enrollment"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			twoFactorService := mockservice.NewMockTwoFactorService(c)
+
+			if testCase.mockBehavior != nil {
+				if userID, ok := testCase.userID.(int); ok {
+					testCase.mockBehavior(twoFactorService, userID)
+				}
+			}
+
+			handler := NewTwoFactorHandler(twoFactorService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+			})
+
+			r.POST("/auth/2fa/enroll", handler.Enroll)
+
+			ctx.Request, _ = http.NewRequest("POST", "/auth/2fa/enroll", nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestTwoFactorHandler_Confirm(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockTwoFactorService, userID int, req models.TwoFactorCodeRequest)
+
+	testTable := []struct {
+		name                 string
+		userID               interface{}
+		requestBody          string
+		request              models.TwoFactorCodeRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			userID:      1,
+			requestBody: `{"code":"123456"}`,
+			request:     models.TwoFactorCodeRequest{Code: "123456"},
+			mockBehavior: func(s *mockservice.MockTwoFactorService, userID int, req models.TwoFactorCodeRequest) {
+				codes := &models.RecoveryCodesResponse{
+					RecoveryCodes: []string{"abcd-efgh-ijkl-mnop", "qrst-uvwx-yz23-4567"},
+				}
+				s.EXPECT().Confirm(userID, req, testClient).Return(codes, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Two-factor authentication enabled","data":{"recovery_codes":["abcd-efgh-ijkl-mnop","qrst-uvwx-yz23-4567"]}}`,
+		},
+		{
+			name:                 "User not found in context",
+			requestBody:          `{"code":"123456"}`,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:                 "Missing code",
+			userID:               1,
+			requestBody:          `{}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
+		},
+		{
+			name:        "Invalid code",
+			userID:      1,
+			requestBody: `{"code":"000000"}`,
+			request:     models.TwoFactorCodeRequest{Code: "000000"},
+			mockBehavior: func(s *mockservice.MockTwoFactorService, userID int, req models.TwoFactorCodeRequest) {
+				s.EXPECT().Confirm(userID, req, testClient).Return(nil, errors.New("invalid two-factor code"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid two-factor code"}`,
+		},
+		{
+			name:        "Enrollment not started",
+			userID:      1,
+			requestBody: `{"code":"123456"}`,
+			request:     models.TwoFactorCodeRequest{Code: "123456"},
+			mockBehavior: func(s *mockservice.MockTwoFactorService, userID int, req models.TwoFactorCodeRequest) {
+				s.EXPECT().Confirm(userID, req, testClient).Return(nil, errors.New("two-factor enrollment not started"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Two-factor enrollment has not been started"}`,
+		},
+		{
+			name:        "Already enabled",
+			userID:      1,
+			requestBody: `{"code":"123456"}`,
+			request:     models.TwoFactorCodeRequest{Code: "123456"},
+			mockBehavior: func(s *mockservice.MockTwoFactorService, userID int, req models.TwoFactorCodeRequest) {
+				s.EXPECT().Confirm(userID, req, testClient).Return(nil, errors.New("two-factor already enabled"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"conflict", "message":"Two-factor authentication is already enabled"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			twoFactorService := mockservice.NewMockTwoFactorService(c)
+
+			if testCase.mockBehavior != nil {
+				if userID, ok := testCase.userID.(int); ok {
+					testCase.mockBehavior(twoFactorService, userID, testCase.request)
+				}
+			}
+
+			handler := NewTwoFactorHandler(twoFactorService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+			})
+
+			r.POST("/auth/2fa/confirm", handler.Confirm)
+
+			ctx.Request, _ = http.NewRequest("POST", "/auth/2fa/confirm", bytes.NewBufferString(testCase.requestBody))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+			setTestClient(ctx.Request)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestTwoFactorHandler_Disable(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockTwoFactorService, userID int, req models.DisableTwoFactorRequest)
+
+	testTable := []struct {
+		name                 string
+		userID               interface{}
+		requestBody          string
+		request              models.DisableTwoFactorRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			userID:      1,
+			requestBody: `{"password":"password123","code":"123456"}`,
+			request:     models.DisableTwoFactorRequest{Password: "password123", Code: "123456"},
+			mockBehavior: func(s *mockservice.MockTwoFactorService, userID int, req models.DisableTwoFactorRequest) {
+				s.EXPECT().Disable(userID, req, testClient).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message":"Two-factor authentication disabled"}`,
+		},
+		{
+			name:                 "Missing password",
+			userID:               1,
+			requestBody:          `{"code":"123456"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Invalid request format"}`,
+		},
+		{
+			name:        "Not enabled",
+			userID:      1,
+			requestBody: `{"password":"password123","code":"123456"}`,
+			request:     models.DisableTwoFactorRequest{Password: "password123", Code: "123456"},
+			mockBehavior: func(s *mockservice.MockTwoFactorService, userID int, req models.DisableTwoFactorRequest) {
+				s.EXPECT().Disable(userID, req, testClient).Return(errors.New("two-factor not enabled"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Two-factor authentication is not enabled"}`,
+		},
+		{
+			name:        "Wrong password",
+			userID:      1,
+			requestBody: `{"password":"wrongpass1","code":"123456"}`,
+			request:     models.DisableTwoFactorRequest{Password: "wrongpass1", Code: "123456"},
+			mockBehavior: func(s *mockservice.MockTwoFactorService, userID int, req models.DisableTwoFactorRequest) {
+				s.EXPECT().Disable(userID, req, testClient).Return(errors.New("invalid current password"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"Current password is incorrect"}`,
+		},
+		{
+			name:        "Internal server error",
+			userID:      1,
+			requestBody: `{"password":"password123","code":"123456"}`,
+			request:     models.DisableTwoFactorRequest{Password: "password123", Code: "123456"},
+			mockBehavior: func(s *mockservice.MockTwoFactorService, userID int, req models.DisableTwoFactorRequest) {
+				s.EXPECT().Disable(userID, req, testClient).Return(errors.New("database connection failed"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to disable two-factor authentication"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			twoFactorService := mockservice.NewMockTwoFactorService(c)
+
+			if testCase.mockBehavior != nil {
+				if userID, ok := testCase.userID.(int); ok {
+					testCase.mockBehavior(twoFactorService, userID, testCase.request)
+				}
+			}
+
+			handler := NewTwoFactorHandler(twoFactorService)
+
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+			})
+
+			r.POST("/auth/2fa/disable", handler.Disable)
+
+			ctx.Request, _ = http.NewRequest("POST", "/auth/2fa/disable", bytes.NewBufferString(testCase.requestBody))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+			setTestClient(ctx.Request)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
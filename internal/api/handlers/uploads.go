@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/internal/service"
+	"marketplace-api/pkg/middleware"
+	"marketplace-api/pkg/utils"
+)
+
+type UploadHandler struct {
+	uploadService service.UploadServiceInterface
+}
+
+func NewUploadHandler(uploadService service.UploadServiceInterface) *UploadHandler {
+	return &UploadHandler{
+		uploadService: uploadService,
+	}
+}
+
+// Upload загружает изображение
+// @Summary Загрузить изображение
+// @Description Принимает файл в поле file формы multipart/form-data. Тип файла определяется по содержимому; разрешены изображения из настроек (по умолчанию JPEG, PNG, GIF и WebP) в пределах лимита размера. Повторная загрузка того же файла возвращает существующую загрузку с кодом 200. Полученный file_key указывается в изображениях объявления; неиспользуемые загрузки удаляются через сутки
+// @Tags uploads
+// @Security Bearer
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Изображение"
+// @Success 200 {object} utils.SuccessResponse{data=models.Upload}
+// @Success 201 {object} utils.SuccessResponse{data=models.Upload}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
+// @Failure 415 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /uploads [post]
+func (h *UploadHandler) Upload(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.PayloadTooLarge(c, "File too large")
+			return
+		}
+		utils.BadRequest(c, "file is required")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.InternalError(c, "Failed to read file")
+		return
+	}
+	defer file.Close()
+
+	upload, created, err := h.uploadService.Upload(userID, file)
+	if err != nil {
+		switch err.Error() {
+		case "file too large":
+			utils.PayloadTooLarge(c, "File too large")
+		case "unsupported file type":
+			utils.UnsupportedMediaType(c, "Unsupported file type")
+		case "file is empty", "invalid image file":
+			utils.BadRequest(c, err.Error())
+		default:
+			utils.InternalError(c, "Failed to upload file")
+		}
+		return
+	}
+
+	if !created {
+		utils.SendSuccess(c, http.StatusOK, upload, "File already uploaded")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusCreated, upload, "File uploaded successfully")
+}
+
+// GetFile отдает загруженный файл по подписанной ссылке
+// @Summary Скачать файл
+// @Description Отдает файл по ссылке из поля url загрузки или изображения объявления. Ссылка подписана и действует ограниченное время. Используется хранилищем local; ссылки хранилища S3 ведут напрямую в бакет
+// @Tags uploads
+// @Produce octet-stream
+// @Param key path string true "Ключ файла"
+// @Param expires query int true "Срок действия ссылки (Unix time)"
+// @Param signature query string true "Подпись ссылки"
+// @Success 200 {file} file
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /uploads/files/{key} [get]
+func (h *UploadHandler) GetFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	file, contentType, err := h.uploadService.OpenFile(key, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		switch err.Error() {
+		case "invalid signature":
+			utils.Forbidden(c, "Invalid file link")
+		case "link expired":
+			utils.Forbidden(c, "File link expired")
+		case "file not found":
+			utils.NotFound(c, "File not found")
+		default:
+			utils.InternalError(c, "Failed to get file")
+		}
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, -1, contentType, file, map[string]string{
+		"Cache-Control":          "private, max-age=300",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
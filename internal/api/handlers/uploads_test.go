@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"marketplace-api/internal/models"
+	mockservice "marketplace-api/internal/service/mocks"
+	"marketplace-api/pkg/middleware"
+)
+
+// multipartFile собирает тело multipart/form-data с одним файлом
+func multipartFile(t *testing.T, field, content string) (*bytes.Buffer, string) {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(field, "photo.png")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return body, writer.FormDataContentType()
+}
+
+func TestUploadHandler_Upload(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockUploadService, userID int)
+
+	hash := strings.Repeat("ab", 32)
+	upload := &models.Upload{
+		ID:          7,
+		UserID:      1,
+		ContentHash: hash,
+		FileKey:     "ab/" + hash + ".png",
+		ContentType: "image/png",
+		Size:        12,
+		Width:       intPtr(640),
+		Height:      intPtr(480),
+		CreatedAt:   time.Date(2025, 7, 22, 10, 0, 0, 0, time.UTC),
+		URL:         "http://localhost:8080/api/uploads/files/ab/" + hash + ".png?expires=1753182000&signature=abc",
+	}
+	uploadJSON := `{"id":7,"content_hash":"` + hash + `","file_key":"ab/` + hash + `.png","content_type":"image/png","size":12,"width":640,"height":480,"created_at":"2025-07-22T10:00:00Z","url":"http://localhost:8080/api/uploads/files/ab/` + hash + `.png?expires=1753182000&signature=abc"}`
+
+	testTable := []struct {
+		name                 string
+		field                string
+		userID               interface{}
+		bodyLimit            int64
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Created",
+			field:  "file",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockUploadService, userID int) {
+				s.EXPECT().Upload(userID, gomock.Any()).DoAndReturn(func(userID int, file io.Reader) (*models.Upload, bool, error) {
+					data, err := io.ReadAll(file)
+					if err != nil || string(data) != "image data" {
+						return nil, false, errors.New("unexpected file")
+					}
+					return upload, true, nil
+				})
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"data":` + uploadJSON + `,"message":"File uploaded successfully"}`,
+		},
+		{
+			name:   "Already uploaded",
+			field:  "file",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockUploadService, userID int) {
+				s.EXPECT().Upload(userID, gomock.Any()).Return(upload, false, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":` + uploadJSON + `,"message":"File already uploaded"}`,
+		},
+		{
+			name:                 "Missing file",
+			field:                "photo",
+			userID:               1,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"file is required"}`,
+		},
+		{
+			name:                 "Body limit exceeded",
+			field:                "file",
+			userID:               1,
+			bodyLimit:            64,
+			expectedStatusCode:   http.StatusRequestEntityTooLarge,
+			expectedResponseBody: `{"error":"payload_too_large", "message":"File too large"}`,
+		},
+		{
+			name:   "File too large",
+			field:  "file",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockUploadService, userID int) {
+				s.EXPECT().Upload(userID, gomock.Any()).Return(nil, false, errors.New("file too large"))
+			},
+			expectedStatusCode:   http.StatusRequestEntityTooLarge,
+			expectedResponseBody: `{"error":"payload_too_large", "message":"File too large"}`,
+		},
+		{
+			name:   "Unsupported file type",
+			field:  "file",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockUploadService, userID int) {
+				s.EXPECT().Upload(userID, gomock.Any()).Return(nil, false, errors.New("unsupported file type"))
+			},
+			expectedStatusCode:   http.StatusUnsupportedMediaType,
+			expectedResponseBody: `{"error":"unsupported_media_type", "message":"Unsupported file type"}`,
+		},
+		{
+			name:   "Invalid image",
+			field:  "file",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockUploadService, userID int) {
+				s.EXPECT().Upload(userID, gomock.Any()).Return(nil, false, errors.New("invalid image file"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"bad_request", "message":"invalid image file"}`,
+		},
+		{
+			name:                 "No user ID in context",
+			field:                "file",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"User not found in context"}`,
+		},
+		{
+			name:   "Internal server error",
+			field:  "file",
+			userID: 1,
+			mockBehavior: func(s *mockservice.MockUploadService, userID int) {
+				s.EXPECT().Upload(userID, gomock.Any()).Return(nil, false, errors.New("failed to store file: connection refused"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to upload file"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			uploadService := mockservice.NewMockUploadService(c)
+
+			if testCase.mockBehavior != nil {
+				if userID, ok := testCase.userID.(int); ok {
+					testCase.mockBehavior(uploadService, userID)
+				}
+			}
+
+			handler := NewUploadHandler(uploadService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+			})
+			if testCase.bodyLimit > 0 {
+				r.Use(middleware.BodyLimit(testCase.bodyLimit))
+			}
+
+			r.POST("/uploads", handler.Upload)
+
+			body, contentType := multipartFile(t, testCase.field, "image data")
+			ctx.Request, _ = http.NewRequest("POST", "/uploads", body)
+			ctx.Request.Header.Set("Content-Type", contentType)
+			// размер тела неизвестен заранее, как при chunked-передаче
+			ctx.Request.ContentLength = -1
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestUploadHandler_GetFile(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockUploadService)
+
+	testTable := []struct {
+		name                 string
+		url                  string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedContentType  string
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			url:  "/uploads/files/ab/abcdef.png?expires=1753182000&signature=abc",
+			mockBehavior: func(s *mockservice.MockUploadService) {
+				s.EXPECT().OpenFile("ab/abcdef.png", "1753182000", "abc").
+					Return(io.NopCloser(strings.NewReader("image data")), "image/png", nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "image/png",
+			expectedResponseBody: "image data",
+		},
+		{
+			name: "Invalid signature",
+			url:  "/uploads/files/ab/abcdef.png?expires=1753182000&signature=bad",
+			mockBehavior: func(s *mockservice.MockUploadService) {
+				s.EXPECT().OpenFile("ab/abcdef.png", "1753182000", "bad").Return(nil, "", errors.New("invalid signature"))
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"forbidden","message":"Invalid file link"}`,
+		},
+		{
+			name: "Link expired",
+			url:  "/uploads/files/ab/abcdef.png?expires=1753182000&signature=abc",
+			mockBehavior: func(s *mockservice.MockUploadService) {
+				s.EXPECT().OpenFile("ab/abcdef.png", "1753182000", "abc").Return(nil, "", errors.New("link expired"))
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"forbidden","message":"File link expired"}`,
+		},
+		{
+			name: "File not found",
+			url:  "/uploads/files/ab/abcdef.png?expires=1753182000&signature=abc",
+			mockBehavior: func(s *mockservice.MockUploadService) {
+				s.EXPECT().OpenFile("ab/abcdef.png", "1753182000", "abc").Return(nil, "", errors.New("file not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"not_found","message":"File not found"}`,
+		},
+		{
+			name: "Internal server error",
+			url:  "/uploads/files/ab/abcdef.png?expires=1753182000&signature=abc",
+			mockBehavior: func(s *mockservice.MockUploadService) {
+				s.EXPECT().OpenFile("ab/abcdef.png", "1753182000", "abc").Return(nil, "", errors.New("failed to open file: permission denied"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"internal_error","message":"Failed to get file"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			uploadService := mockservice.NewMockUploadService(c)
+			testCase.mockBehavior(uploadService)
+
+			handler := NewUploadHandler(uploadService)
+
+			w := httptest.NewRecorder()
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.GET("/uploads/files/*key", handler.GetFile)
+
+			ctx.Request, _ = http.NewRequest("GET", testCase.url, nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
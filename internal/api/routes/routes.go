@@ -1,29 +1,176 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"log/slog"
 	"marketplace-api/internal/database"
 
 	"github.com/gin-gonic/gin"
 	"marketplace-api/internal/api/handlers"
 	"marketplace-api/internal/config"
 	"marketplace-api/internal/database/postgres"
+	"marketplace-api/internal/mail"
+	"marketplace-api/internal/oidc"
+	"marketplace-api/internal/policy"
 	"marketplace-api/internal/service"
+	"marketplace-api/internal/storage"
 	"marketplace-api/pkg/middleware"
+	"marketplace-api/pkg/rbac"
+	"marketplace-api/pkg/utils"
 )
 
-// SetupRoutes настраивает все маршруты приложения согласно ТЗ
-func SetupRoutes(router *gin.Engine, db *sql.DB, cfg *config.Config) {
-	router.Use(middleware.CORSMiddleware())
+// SetupRoutes настраивает все маршруты приложения согласно ТЗ.
+// Фоновые задачи сервисов работают до отмены ctx
+func SetupRoutes(ctx context.Context, router *gin.Engine, db *sql.DB, cfg *config.Config, log *slog.Logger) error {
+	router.Use(middleware.RequestID(), middleware.CORSMiddleware())
 
 	userRepo := postgres.NewUserRepository(db)
 	listingRepo := postgres.NewListingRepository(db)
+	refreshTokenRepo := postgres.NewRefreshTokenRepository(db)
+	tokenRevocationRepo := postgres.NewTokenRevocationRepository(db)
+	signingKeyRepo := postgres.NewSigningKeyRepository(db)
+	passwordResetRepo := postgres.NewPasswordResetRepository(db)
+	passwordHistoryRepo := postgres.NewPasswordHistoryRepository(db)
+	emailVerificationRepo := postgres.NewEmailVerificationRepository(db)
+	loginAttemptRepo := postgres.NewLoginAttemptRepository(db)
+	twoFactorRepo := postgres.NewTwoFactorRepository(db)
+	apiKeyRepo := postgres.NewAPIKeyRepository(db)
+	sessionRepo := postgres.NewSessionRepository(db)
+	identityRepo := postgres.NewIdentityRepository(db)
+	accountRepo := postgres.NewAccountRepository(db)
+	profileRepo := postgres.NewProfileRepository(db)
+	impersonationRepo := postgres.NewImpersonationRepository(db)
+	securityEventRepo := postgres.NewSecurityEventRepository(db)
+	categoryRepo := postgres.NewCategoryRepository(db)
+	uploadRepo := postgres.NewUploadRepository(db)
 
-	authService := service.NewAuthService(userRepo, cfg.JWT.Secret)
-	listingService := service.NewListingService(listingRepo)
+	mailer, err := mail.NewSender(cfg.Mail, log)
+	if err != nil {
+		return fmt.Errorf("failed to initialize mail sender: %w", err)
+	}
+
+	blobStore, err := storage.NewBlobStore(cfg.Uploads)
+	if err != nil {
+		return fmt.Errorf("failed to initialize upload storage: %w", err)
+	}
+
+	keyRing := utils.NewHMACKeyRing(cfg.JWT.Secret)
+	if cfg.JWT.Algorithm != utils.AlgorithmHS256 {
+		keyRing = utils.NewKeyRing()
+		keyRotationService := service.NewKeyRotationService(signingKeyRepo, keyRing, cfg.JWT, log)
+		if err := keyRotationService.Init(); err != nil {
+			return fmt.Errorf("failed to initialize signing keys: %w", err)
+		}
+		go keyRotationService.Run(ctx)
+	}
+
+	revocationStore := service.NewTokenRevocationStore(tokenRevocationRepo, cfg.JWT.RevocationCacheTTL)
+
+	loginThrottle := service.NewLoginThrottle(loginAttemptRepo, cfg.LoginThrottle, log)
+	go loginThrottle.Run(ctx)
+
+	bcryptHasher := utils.NewBcryptHasher(cfg.PasswordHash.BcryptCost)
+	argon2idHasher := utils.NewArgon2idHasher(utils.Argon2idParams{
+		Memory:      uint32(cfg.PasswordHash.Argon2Memory),
+		Iterations:  uint32(cfg.PasswordHash.Argon2Iterations),
+		Parallelism: uint8(cfg.PasswordHash.Argon2Parallelism),
+	})
+	passwordHasher := utils.NewPasswordHashers(argon2idHasher, bcryptHasher)
+	if cfg.PasswordHash.Algorithm == utils.PasswordAlgorithmBcrypt {
+		passwordHasher = utils.NewPasswordHashers(bcryptHasher, argon2idHasher)
+	}
+
+	loginPolicy, err := policy.NewLoginPolicy(cfg.LoginPolicy)
+	if err != nil {
+		return fmt.Errorf("failed to initialize login policy: %w", err)
+	}
+	var breachedPasswords *policy.BreachedList
+	if cfg.PasswordPolicy.BreachedCheck {
+		breachedPasswords, err = policy.LoadBreachedList(cfg.PasswordPolicy.BreachedFile)
+		if err != nil {
+			return fmt.Errorf("failed to load breached passwords: %w", err)
+		}
+	}
+	passwordPolicy := policy.NewPasswordPolicy(cfg.PasswordPolicy, breachedPasswords)
+
+	securityEvents := service.NewSecurityEventService(securityEventRepo, cfg.SecurityEvents, log)
+	go securityEvents.Run(ctx)
+	emailService := service.NewEmailService(userRepo, emailVerificationRepo, passwordHasher, securityEvents, mailer, cfg.Auth)
+	twoFactorService := service.NewTwoFactorService(userRepo, twoFactorRepo, passwordHasher, securityEvents, cfg.Auth)
+	sessionService := service.NewSessionService(sessionRepo, revocationStore, securityEvents, log)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, securityEvents, log)
+	authService := service.NewAuthService(
+		userRepo,
+		refreshTokenRepo,
+		passwordResetRepo,
+		passwordHistoryRepo,
+		emailService,
+		loginThrottle,
+		twoFactorService,
+		sessionService,
+		revocationStore,
+		securityEvents,
+		passwordHasher,
+		loginPolicy,
+		passwordPolicy,
+		keyRing,
+		mailer,
+		cfg.JWT,
+		cfg.Auth,
+		log,
+	)
+	var oidcProvider *oidc.Provider
+	if cfg.OIDC.Enabled() {
+		oidcProvider = oidc.NewProvider(cfg.OIDC)
+	}
+	oidcService := service.NewOIDCService(oidcProvider, identityRepo, userRepo, authService, cfg.OIDC, cfg.Auth, log)
+	uploadService := service.NewUploadService(uploadRepo, blobStore, cfg.Uploads, log)
+	go uploadService.Run(ctx)
+	accountService := service.NewAccountService(
+		userRepo,
+		accountRepo,
+		listingRepo,
+		sessionRepo,
+		apiKeyRepo,
+		identityRepo,
+		profileRepo,
+		securityEventRepo,
+		uploadService,
+		authService,
+		mailer,
+		cfg.Auth,
+		log,
+	)
+	go accountService.Run(ctx)
+	listingService := service.NewListingService(listingRepo, userRepo, categoryRepo, uploadService, cfg.Listings)
+	categoryService := service.NewCategoryService(categoryRepo, securityEvents)
+	profileService := service.NewProfileService(userRepo, profileRepo)
+	adminService := service.NewAdminService(userRepo, revocationStore, loginThrottle, securityEvents)
+	impersonationService := service.NewImpersonationService(userRepo, impersonationRepo, keyRing, securityEvents, cfg.Auth, log)
 
 	authHandler := handlers.NewAuthHandler(authService)
+	emailHandler := handlers.NewEmailHandler(emailService)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	oidcHandler := handlers.NewOIDCHandler(oidcService)
+	accountHandler := handlers.NewAccountHandler(accountService)
 	listingHandler := handlers.NewListingHandler(listingService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	uploadHandler := handlers.NewUploadHandler(uploadService)
+	profileHandler := handlers.NewProfileHandler(profileService)
+	jwksHandler := handlers.NewJWKSHandler(keyRing)
+	adminHandler := handlers.NewAdminHandler(adminService)
+	impersonationHandler := handlers.NewImpersonationHandler(impersonationService)
+	securityEventHandler := handlers.NewSecurityEventHandler(securityEvents)
+
+	// Запросы с токеном входа от имени пользователя пишутся в журнал, изменяющие запросы без разрешения отклоняются
+	impersonationGuard := middleware.ImpersonationGuard(impersonationService)
+	denyImpersonation := middleware.DenyImpersonation()
+
+	router.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
 
 	api := router.Group("/api")
 	{
@@ -49,25 +196,115 @@ func SetupRoutes(router *gin.Engine, db *sql.DB, cfg *config.Config) {
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/login/2fa", authHandler.LoginTwoFactor)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.POST("/password/forgot", authHandler.ForgotPassword)
+			auth.POST("/password/reset", authHandler.ResetPassword)
+			auth.POST("/email/verify", emailHandler.VerifyEmail)
+
+			if cfg.OIDC.Enabled() {
+				auth.GET("/oidc/authorize", oidcHandler.Authorize)
+				auth.GET("/oidc/callback", oidcHandler.Callback)
+			}
 		}
 
+		api.GET("/categories", categoryHandler.GetCategories)
+
+		// Маршруты объявлений принимают и персональные API-ключи, права ключа проверяет RequireScope
 		listings := api.Group("/listings")
+		listings.Use(middleware.OptionalAuthMiddleware(keyRing, revocationStore, sessionService, apiKeyService), impersonationGuard)
 		{
-			listings.GET("/", listingHandler.GetListings)
-			listings.GET("/:id", listingHandler.GetListing)
+			listings.GET("/", middleware.RequireScope(rbac.ScopeListingsRead), listingHandler.GetListings)
+			listings.GET("/:id", middleware.RequireScope(rbac.ScopeListingsRead), listingHandler.GetListing)
 		}
 
+		protectedListings := api.Group("/listings")
+		protectedListings.Use(middleware.AuthMiddleware(keyRing, revocationStore, sessionService, apiKeyService), impersonationGuard)
+		{
+			protectedListings.POST("/", middleware.RequireScope(rbac.ScopeListingsWrite), listingHandler.CreateListing)
+			protectedListings.GET("/my", middleware.RequireScope(rbac.ScopeListingsRead), listingHandler.GetMyListings)
+			protectedListings.PUT("/:id", middleware.RequireScope(rbac.ScopeListingsWrite), listingHandler.UpdateListing)
+			protectedListings.DELETE("/:id", middleware.RequireScope(rbac.ScopeListingsWrite), listingHandler.DeleteListing)
+			protectedListings.POST("/:id/images", middleware.RequireScope(rbac.ScopeListingsWrite), listingHandler.AddListingImage)
+			protectedListings.PUT("/:id/images/order", middleware.RequireScope(rbac.ScopeListingsWrite), listingHandler.ReorderListingImages)
+			protectedListings.DELETE("/:id/images/:imageId", middleware.RequireScope(rbac.ScopeListingsWrite), listingHandler.DeleteListingImage)
+		}
+
+		// Загрузка изображений для объявлений; размер тела ограничен лимитом файла с запасом на заголовки multipart
+		uploads := api.Group("/uploads")
+		{
+			uploads.POST("/",
+				middleware.AuthMiddleware(keyRing, revocationStore, sessionService, apiKeyService),
+				impersonationGuard,
+				middleware.RequireScope(rbac.ScopeListingsWrite),
+				middleware.BodyLimit(cfg.Uploads.MaxSize+1<<20),
+				uploadHandler.Upload,
+			)
+			// Файлы хранилища local отдаются по подписанным ссылкам без авторизации
+			uploads.GET("/files/*key", uploadHandler.GetFile)
+		}
+
+		// Публичные страницы продавцов
+		users := api.Group("/users")
+		users.Use(middleware.OptionalAuthMiddleware(keyRing, revocationStore, sessionService, apiKeyService), impersonationGuard)
+		{
+			users.GET("/:login", profileHandler.GetPublicProfile)
+			users.GET("/:login/listings", middleware.RequireScope(rbac.ScopeListingsRead), listingHandler.GetSellerListings)
+		}
+
+		// Управление аккаунтом, ключами и администрирование доступны только с JWT
 		protected := api.Group("/")
-		protected.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+		protected.Use(middleware.AuthMiddleware(keyRing, revocationStore, sessionService, nil), impersonationGuard)
 		{
 			protected.GET("/auth/me", authHandler.Me)
+			protected.POST("/auth/logout", authHandler.Logout)
+			protected.POST("/auth/logout-all", denyImpersonation, authHandler.LogoutAll)
+			protected.GET("/auth/sessions", sessionHandler.ListSessions)
+			protected.DELETE("/auth/sessions/:id", denyImpersonation, sessionHandler.RevokeSession)
+			protected.GET("/auth/identities", oidcHandler.ListIdentities)
+			protected.PUT("/auth/password", denyImpersonation, authHandler.ChangePassword)
+			protected.PUT("/auth/email", denyImpersonation, emailHandler.ChangeEmail)
+			protected.POST("/auth/email/resend", denyImpersonation, emailHandler.ResendVerification)
+			protected.POST("/auth/2fa/enroll", denyImpersonation, twoFactorHandler.Enroll)
+			protected.POST("/auth/2fa/confirm", denyImpersonation, twoFactorHandler.Confirm)
+			protected.POST("/auth/2fa/disable", denyImpersonation, twoFactorHandler.Disable)
+			protected.POST("/auth/api-keys", denyImpersonation, apiKeyHandler.CreateAPIKey)
+			protected.GET("/auth/api-keys", apiKeyHandler.ListAPIKeys)
+			protected.DELETE("/auth/api-keys/:id", denyImpersonation, apiKeyHandler.RevokeAPIKey)
+			protected.GET("/users/me", profileHandler.GetMyProfile)
+			protected.PUT("/users/me", profileHandler.UpdateMyProfile)
+			protected.GET("/users/me/security-events", securityEventHandler.ListMyEvents)
+			protected.GET("/users/me/export", denyImpersonation, accountHandler.ExportData)
+			protected.DELETE("/users/me", denyImpersonation, accountHandler.DeleteAccount)
 
-			protectedListings := protected.Group("/listings")
+			admin := protected.Group("/admin")
+			admin.Use(middleware.RequirePermission(rbac.PermAdminAccess))
 			{
-				protectedListings.POST("/", listingHandler.CreateListing)
-				protectedListings.GET("/my", listingHandler.GetMyListings)
-				protectedListings.PUT("/:id", listingHandler.UpdateListing)
-				protectedListings.DELETE("/:id", listingHandler.DeleteListing)
+				adminUsers := admin.Group("/users")
+				adminUsers.Use(middleware.RequirePermission(rbac.PermUsersManage))
+				{
+					adminUsers.GET("/", adminHandler.ListUsers)
+					adminUsers.PUT("/:id/role", adminHandler.UpdateUserRole)
+					adminUsers.POST("/:id/unlock", adminHandler.UnlockUser)
+					adminUsers.POST("/:id/impersonate", middleware.RequirePermission(rbac.PermUsersImpersonate), impersonationHandler.Impersonate)
+				}
+
+				impersonations := admin.Group("/impersonations")
+				impersonations.Use(middleware.RequirePermission(rbac.PermUsersImpersonate))
+				{
+					impersonations.GET("/", impersonationHandler.ListImpersonations)
+					impersonations.GET("/:id", impersonationHandler.GetImpersonation)
+				}
+
+				categories := admin.Group("/categories")
+				categories.Use(middleware.RequirePermission(rbac.PermCategoriesManage))
+				{
+					categories.POST("/", categoryHandler.CreateCategory)
+					categories.PUT("/:id", categoryHandler.UpdateCategory)
+					categories.DELETE("/:id", categoryHandler.DeleteCategory)
+				}
+
+				admin.GET("/security-events", middleware.RequirePermission(rbac.PermSecurityEventsRead), securityEventHandler.SearchEvents)
 			}
 		}
 	}
@@ -92,4 +329,6 @@ func SetupRoutes(router *gin.Engine, db *sql.DB, cfg *config.Config) {
 			"path":    c.Request.URL.Path,
 		})
 	})
+
+	return nil
 }
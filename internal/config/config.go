@@ -1,19 +1,43 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
 	JWT      JWTConfig
+	Auth     AuthConfig
+	Mail     MailConfig
+	Listings ListingsConfig
+	// LoginThrottle защита входа от перебора паролей
+	LoginThrottle LoginThrottleConfig
+	// OIDC вход через внешнего провайдера OpenID Connect
+	OIDC OIDCConfig
+	// PasswordHash алгоритм и параметры хеширования паролей
+	PasswordHash PasswordHashConfig
+	// PasswordPolicy требования к паролям
+	PasswordPolicy PasswordPolicyConfig
+	// LoginPolicy требования к логинам
+	LoginPolicy LoginPolicyConfig
+	// Uploads загрузка файлов и хранилище
+	Uploads UploadsConfig
+	// SecurityEvents хранение журнала безопасности
+	SecurityEvents SecurityEventsConfig
 }
 
 type ServerConfig struct {
 	Host string
 	Port string
+	// TrustedProxies адреса прокси, которым доверяется заголовок X-Forwarded-For при определении IP клиента
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -23,28 +47,305 @@ type DatabaseConfig struct {
 	Password string
 	Database string
 	SSLMode  string
+	// AutoMigrate применяет миграции при старте сервера
+	AutoMigrate bool
 }
 
 type JWTConfig struct {
-	Secret string
+	// Algorithm алгоритм подписи токенов: HS256, RS256 или EdDSA
+	Algorithm       string
+	Secret          string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// RevocationCacheTTL время, в течение которого реплика кэширует результат проверки отзыва токена
+	RevocationCacheTTL time.Duration
+	// KeyRotationInterval как часто выпускается новый асимметричный ключ подписи
+	KeyRotationInterval time.Duration
+	// KeyGracePeriod сколько старый ключ принимается при проверке после вывода из оборота
+	KeyGracePeriod time.Duration
+	// KeyRefreshInterval как часто реплика перечитывает ключи из базы данных
+	KeyRefreshInterval time.Duration
+	// KeyEncryptionKey ключ AES-256 в base64, которым шифруются закрытые ключи подписи в базе данных
+	KeyEncryptionKey string
+}
+
+type AuthConfig struct {
+	// PasswordResetTTL время жизни токена сброса пароля
+	PasswordResetTTL time.Duration
+	// PasswordResetURL адрес страницы сброса пароля, к которому добавляется ?token=...
+	PasswordResetURL string
+	// PasswordResetInterval минимальный интервал между письмами сброса пароля одному пользователю
+	PasswordResetInterval time.Duration
+	// EmailRequired делает email обязательным при регистрации
+	EmailRequired bool
+	// EmailVerificationTTL время жизни токена подтверждения email
+	EmailVerificationTTL time.Duration
+	// EmailVerificationURL адрес страницы подтверждения email, к которому добавляется ?token=...
+	EmailVerificationURL string
+	// EmailResendInterval минимальный интервал между письмами подтверждения одному пользователю
+	EmailResendInterval time.Duration
+	// TOTPIssuer название сервиса, которое показывает приложение-аутентификатор
+	TOTPIssuer string
+	// TwoFactorChallengeTTL время на ввод второго фактора после проверки пароля
+	TwoFactorChallengeTTL time.Duration
+	// AccountDeletionGracePeriod время между запросом удаления аккаунта и удалением данных; вход в этот период отменяет удаление
+	AccountDeletionGracePeriod time.Duration
+	// AccountPurgeInterval как часто фоновая задача удаляет аккаунты с наступившим сроком
+	AccountPurgeInterval time.Duration
+	// ImpersonationTokenTTL время жизни токена администратора для работы от имени пользователя
+	ImpersonationTokenTTL time.Duration
+}
+
+// LoginThrottleConfig пороги защиты входа от перебора.
+// Неудачные попытки считаются отдельно по логину и по IP-адресу клиента в пределах FailureWindow.
+// Начиная с DelayAfterFailures неудач, следующая попытка разрешается только через DelayBase,
+// удваивающийся с каждой неудачей до DelayMax. После MaxFailures неудач по логину (MaxFailuresPerIP по IP)
+// вход блокируется на LockoutDuration. Блокировка и задержка по логину не действуют для IP-адресов,
+// с которых в этот аккаунт входили за последние KnownIPTTL: логины публичны, и иначе любой мог бы заблокировать
+// владельцу вход в его аккаунт
+type LoginThrottleConfig struct {
+	MaxFailures      int
+	MaxFailuresPerIP int
+	// PasswordResetsPerIP сколько запросов сброса пароля разрешено с одного IP-адреса за FailureWindow
+	PasswordResetsPerIP int
+	FailureWindow       time.Duration
+	LockoutDuration     time.Duration
+	DelayAfterFailures  int
+	DelayBase           time.Duration
+	DelayMax            time.Duration
+	KnownIPTTL          time.Duration
+}
+
+// PasswordHashConfig хеширование паролей. Новые пароли хешируются алгоритмом Algorithm,
+// хеши другого алгоритма или с другими параметрами пересчитываются при входе пользователя
+type PasswordHashConfig struct {
+	// Algorithm алгоритм хеширования: bcrypt или argon2id
+	Algorithm  string
+	BcryptCost int
+	// Argon2Memory объем памяти Argon2id в КиБ
+	Argon2Memory      int
+	Argon2Iterations  int
+	Argon2Parallelism int
+}
+
+// PasswordPolicyConfig требования к новым паролям при регистрации, смене и сбросе
+type PasswordPolicyConfig struct {
+	// MinLength минимальная длина в символах
+	MinLength int
+	// MaxLength максимальная длина в байтах UTF-8: bcrypt учитывает только первые 72 байта
+	MaxLength int
+	// RequiredClasses обязательные классы символов: lower, upper, letter, digit, symbol
+	RequiredClasses []string
+	// History сколько последних паролей, включая текущий, нельзя использовать повторно; 0 — не проверять
+	History int
+	// BreachedCheck запрещает пароли из списка утекших
+	BreachedCheck bool
+	// BreachedFile файл со списком SHA-1 утекших паролей; пустой — встроенный список
+	BreachedFile string
+}
+
+// LoginPolicyConfig требования к логинам новых пользователей
+type LoginPolicyConfig struct {
+	MinLength int
+	MaxLength int
+	// Pattern регулярное выражение допустимого логина
+	Pattern string
+	// Reserved логины, которые нельзя занять (без учета регистра)
+	Reserved []string
+}
+
+type ListingsConfig struct {
+	// RequireVerifiedEmail запрещает публиковать объявления пользователям без подтвержденного email
+	RequireVerifiedEmail bool
+	// CursorSecret ключ подписи курсоров пагинации списков объявлений
+	CursorSecret string
+	// MaxImages максимальное количество изображений у одного объявления
+	MaxImages int
+}
+
+type MailConfig struct {
+	// Driver способ отправки писем: log, file или smtp
+	Driver       string
+	From         string
+	FileDir      string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUser     string
+	SMTPPassword string
+}
+
+// UploadsConfig загрузка изображений пользователями и хранилище файлов
+type UploadsConfig struct {
+	// Driver хранилище файлов: local или s3
+	Driver string
+	// MaxSize максимальный размер файла в байтах
+	MaxSize int64
+	// AllowedTypes MIME-типы, которые разрешено загружать; тип определяется по содержимому файла
+	AllowedTypes []string
+	// LocalDir каталог файлов для драйвера local
+	LocalDir string
+	// PublicURL внешний адрес API, от которого строятся ссылки на файлы драйвера local
+	PublicURL string
+	// URLSecret ключ подписи ссылок на файлы драйвера local
+	URLSecret string
+	// URLTTL время жизни подписанной ссылки на файл
+	URLTTL time.Duration
+	// OrphanTTL через сколько удаляется загрузка, которая не используется ни в одном объявлении
+	OrphanTTL time.Duration
+	// CleanupInterval как часто фоновая задача удаляет неиспользуемые загрузки
+	CleanupInterval time.Duration
+	// S3Endpoint адрес S3-совместимого хранилища, например http://localhost:9000
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+}
+
+// OIDCConfig клиент OpenID Connect (authorization code + PKCE).
+// Пустой Issuer отключает вход через внешнего провайдера
+// SecurityEventsConfig хранение журнала безопасности. Записи не удаляются, но через ClientDataRetention
+// в них стираются логин из неудачной попытки входа, IP-адрес и user agent
+type SecurityEventsConfig struct {
+	ClientDataRetention time.Duration
+	CleanupInterval     time.Duration
+}
+
+type OIDCConfig struct {
+	// Issuer адрес провайдера, по нему загружается /.well-known/openid-configuration
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// Scopes запрашиваемые права; openid добавляется всегда
+	Scopes []string
+	// RedirectURL адрес, на который провайдер вернет пользователя с кодом авторизации
+	RedirectURL string
+	// StateTTL время на прохождение входа у провайдера
+	StateTTL time.Duration
+}
+
+// Enabled проверяет, настроен ли вход через внешнего провайдера
+func (c OIDCConfig) Enabled() bool {
+	return c.Issuer != ""
 }
 
 func Load() (*Config, error) {
 	config := &Config{
 		Server: ServerConfig{
-			Host: getEnv("SERVER_HOST", "localhost"),
-			Port: getEnv("SERVER_PORT", "8080"),
+			Host:           getEnv("SERVER_HOST", "localhost"),
+			Port:           getEnv("SERVER_PORT", "8080"),
+			TrustedProxies: getEnvList("SERVER_TRUSTED_PROXIES"),
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "user"),
-			Password: getEnv("DB_PASSWORD", "password"),
-			Database: getEnv("DB_NAME", "marketplace"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Host:        getEnv("DB_HOST", "localhost"),
+			Port:        getEnv("DB_PORT", "5432"),
+			User:        getEnv("DB_USER", "user"),
+			Password:    getEnv("DB_PASSWORD", "password"),
+			Database:    getEnv("DB_NAME", "marketplace"),
+			SSLMode:     getEnv("DB_SSLMODE", "disable"),
+			AutoMigrate: getEnvBool("DB_AUTO_MIGRATE", true),
 		},
 		JWT: JWTConfig{
-			Secret: getEnv("JWT_SECRET_KEY", "secret_jwt"),
+			Secret:              getEnv("JWT_SECRET_KEY", "secret_jwt"),
+			Algorithm:           getEnv("JWT_ALGORITHM", "HS256"),
+			AccessTokenTTL:      getEnvDuration("JWT_ACCESS_TOKEN_TTL", 15*time.Minute),
+			RefreshTokenTTL:     getEnvDuration("JWT_REFRESH_TOKEN_TTL", 30*24*time.Hour),
+			RevocationCacheTTL:  getEnvDuration("JWT_REVOCATION_CACHE_TTL", 30*time.Second),
+			KeyRotationInterval: getEnvDuration("JWT_KEY_ROTATION_INTERVAL", 30*24*time.Hour),
+			KeyGracePeriod:      getEnvDuration("JWT_KEY_GRACE_PERIOD", 24*time.Hour),
+			KeyRefreshInterval:  getEnvDuration("JWT_KEY_REFRESH_INTERVAL", time.Minute),
+			KeyEncryptionKey:    getEnv("JWT_KEY_ENCRYPTION_KEY", ""),
+		},
+		Auth: AuthConfig{
+			PasswordResetTTL:           getEnvDuration("PASSWORD_RESET_TTL", time.Hour),
+			PasswordResetURL:           getEnv("PASSWORD_RESET_URL", "http://localhost:8080/reset-password"),
+			PasswordResetInterval:      getEnvDuration("PASSWORD_RESET_INTERVAL", time.Minute),
+			EmailRequired:              getEnvBool("AUTH_EMAIL_REQUIRED", false),
+			EmailVerificationTTL:       getEnvDuration("EMAIL_VERIFICATION_TTL", 48*time.Hour),
+			EmailVerificationURL:       getEnv("EMAIL_VERIFICATION_URL", "http://localhost:8080/verify-email"),
+			EmailResendInterval:        getEnvDuration("EMAIL_RESEND_INTERVAL", time.Minute),
+			TOTPIssuer:                 getEnv("TOTP_ISSUER", "Marketplace"),
+			TwoFactorChallengeTTL:      getEnvDuration("TWO_FACTOR_CHALLENGE_TTL", 5*time.Minute),
+			AccountDeletionGracePeriod: getEnvDuration("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour),
+			AccountPurgeInterval:       getEnvDuration("ACCOUNT_PURGE_INTERVAL", time.Hour),
+			ImpersonationTokenTTL:      getEnvDuration("IMPERSONATION_TOKEN_TTL", 15*time.Minute),
+		},
+		LoginThrottle: LoginThrottleConfig{
+			MaxFailures:         getEnvInt("LOGIN_MAX_FAILURES", 5),
+			MaxFailuresPerIP:    getEnvInt("LOGIN_MAX_FAILURES_PER_IP", 50),
+			PasswordResetsPerIP: getEnvInt("PASSWORD_RESET_MAX_PER_IP", 10),
+			FailureWindow:       getEnvDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+			LockoutDuration:     getEnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+			DelayAfterFailures:  getEnvInt("LOGIN_DELAY_AFTER_FAILURES", 3),
+			DelayBase:           getEnvDuration("LOGIN_DELAY_BASE", time.Second),
+			DelayMax:            getEnvDuration("LOGIN_DELAY_MAX", 30*time.Second),
+			KnownIPTTL:          getEnvDuration("LOGIN_KNOWN_IP_TTL", 30*24*time.Hour),
+		},
+		PasswordHash: PasswordHashConfig{
+			Algorithm:         getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			BcryptCost:        getEnvInt("BCRYPT_COST", 10),
+			Argon2Memory:      getEnvInt("ARGON2_MEMORY", 64*1024),
+			Argon2Iterations:  getEnvInt("ARGON2_ITERATIONS", 3),
+			Argon2Parallelism: getEnvInt("ARGON2_PARALLELISM", 2),
+		},
+		PasswordPolicy: PasswordPolicyConfig{
+			MinLength:       getEnvInt("PASSWORD_MIN_LENGTH", 6),
+			MaxLength:       getEnvInt("PASSWORD_MAX_LENGTH", 72),
+			RequiredClasses: getEnvListOrDefault("PASSWORD_REQUIRED_CLASSES", []string{"letter", "digit"}),
+			History:         getEnvInt("PASSWORD_HISTORY", 5),
+			BreachedCheck:   getEnvBool("PASSWORD_BREACHED_CHECK", true),
+			BreachedFile:    getEnv("PASSWORD_BREACHED_FILE", ""),
+		},
+		LoginPolicy: LoginPolicyConfig{
+			MinLength: getEnvInt("LOGIN_MIN_LENGTH", 3),
+			MaxLength: getEnvInt("LOGIN_MAX_LENGTH", 50),
+			Pattern:   getEnv("LOGIN_PATTERN", "^[a-zA-Z0-9_]+$"),
+			Reserved: getEnvListOrDefault("LOGIN_RESERVED", []string{
+				"admin", "administrator", "root", "support", "help", "moderator",
+				"system", "security", "api", "marketplace", "null", "undefined",
+			}),
+		},
+		Listings: ListingsConfig{
+			RequireVerifiedEmail: getEnvBool("LISTINGS_REQUIRE_VERIFIED_EMAIL", false),
+			CursorSecret:         getEnv("LISTINGS_CURSOR_SECRET", "secret_listings_cursor"),
+			MaxImages:            getEnvInt("LISTINGS_MAX_IMAGES", 10),
+		},
+		OIDC: OIDCConfig{
+			Issuer:       getEnv("OIDC_ISSUER", ""),
+			ClientID:     getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+			Scopes:       getEnvList("OIDC_SCOPES"),
+			RedirectURL:  getEnv("OIDC_REDIRECT_URL", "http://localhost:8080/api/auth/oidc/callback"),
+			StateTTL:     getEnvDuration("OIDC_STATE_TTL", 10*time.Minute),
+		},
+		Uploads: UploadsConfig{
+			Driver:          getEnv("UPLOAD_DRIVER", "local"),
+			MaxSize:         int64(getEnvInt("UPLOAD_MAX_SIZE", 10<<20)),
+			AllowedTypes:    getEnvListOrDefault("UPLOAD_ALLOWED_TYPES", []string{"image/jpeg", "image/png", "image/gif", "image/webp"}),
+			LocalDir:        getEnv("UPLOAD_LOCAL_DIR", "./uploads"),
+			PublicURL:       getEnv("UPLOAD_PUBLIC_URL", "http://localhost:8080"),
+			URLSecret:       getEnv("UPLOAD_URL_SECRET", "secret_upload_url"),
+			URLTTL:          getEnvDuration("UPLOAD_URL_TTL", time.Hour),
+			OrphanTTL:       getEnvDuration("UPLOAD_ORPHAN_TTL", 24*time.Hour),
+			CleanupInterval: getEnvDuration("UPLOAD_CLEANUP_INTERVAL", time.Hour),
+			S3Endpoint:      getEnv("S3_ENDPOINT", ""),
+			S3Region:        getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:        getEnv("S3_BUCKET", ""),
+			S3AccessKey:     getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:     getEnv("S3_SECRET_KEY", ""),
+		},
+		SecurityEvents: SecurityEventsConfig{
+			ClientDataRetention: getEnvDuration("SECURITY_EVENTS_CLIENT_DATA_RETENTION", 90*24*time.Hour),
+			CleanupInterval:     getEnvDuration("SECURITY_EVENTS_CLEANUP_INTERVAL", time.Hour),
+		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "no-reply@marketplace.local"),
+			FileDir:      getEnv("MAIL_FILE_DIR", "./mail"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     getEnv("SMTP_PORT", "25"),
+			SMTPUser:     getEnv("SMTP_USER", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
 		},
 	}
 
@@ -56,8 +357,114 @@ func Load() (*Config, error) {
 }
 
 func (c *Config) validate() error {
-	if c.JWT.Secret == "" {
-		return fmt.Errorf("JWT_SECRET_KEY is required")
+	switch c.JWT.Algorithm {
+	case "HS256":
+		if c.JWT.Secret == "" {
+			return fmt.Errorf("JWT_SECRET_KEY is required")
+		}
+	case "RS256", "EdDSA":
+		if c.JWT.KeyGracePeriod < c.JWT.AccessTokenTTL {
+			return fmt.Errorf("JWT_KEY_GRACE_PERIOD must not be shorter than JWT_ACCESS_TOKEN_TTL")
+		}
+		if c.JWT.KeyRotationInterval <= 0 || c.JWT.KeyRefreshInterval <= 0 {
+			return fmt.Errorf("JWT key rotation and refresh intervals must be positive")
+		}
+		if c.JWT.KeyEncryptionKey == "" {
+			return fmt.Errorf("JWT_KEY_ENCRYPTION_KEY is required for %s", c.JWT.Algorithm)
+		}
+		if key, err := base64.StdEncoding.DecodeString(c.JWT.KeyEncryptionKey); err != nil || len(key) != 32 {
+			return fmt.Errorf("JWT_KEY_ENCRYPTION_KEY must be 32 base64-encoded bytes")
+		}
+	default:
+		return fmt.Errorf("unsupported JWT_ALGORITHM: %s", c.JWT.Algorithm)
+	}
+	if c.JWT.AccessTokenTTL <= 0 || c.JWT.RefreshTokenTTL <= 0 {
+		return fmt.Errorf("JWT token TTLs must be positive")
+	}
+	if c.LoginThrottle.MaxFailures <= 0 || c.LoginThrottle.MaxFailuresPerIP <= 0 {
+		return fmt.Errorf("LOGIN_MAX_FAILURES and LOGIN_MAX_FAILURES_PER_IP must be positive")
+	}
+	if c.LoginThrottle.PasswordResetsPerIP <= 0 {
+		return fmt.Errorf("PASSWORD_RESET_MAX_PER_IP must be positive")
+	}
+	if c.LoginThrottle.FailureWindow <= 0 || c.LoginThrottle.LockoutDuration <= 0 {
+		return fmt.Errorf("LOGIN_FAILURE_WINDOW and LOGIN_LOCKOUT_DURATION must be positive")
+	}
+	if c.LoginThrottle.KnownIPTTL < 0 {
+		return fmt.Errorf("LOGIN_KNOWN_IP_TTL must not be negative")
+	}
+	if c.Auth.PasswordResetInterval < 0 {
+		return fmt.Errorf("PASSWORD_RESET_INTERVAL must not be negative")
+	}
+	switch c.Mail.Driver {
+	case "log", "file", "smtp":
+	default:
+		return fmt.Errorf("unsupported MAIL_DRIVER: %s", c.Mail.Driver)
+	}
+	if c.Auth.AccountDeletionGracePeriod < 0 || c.Auth.AccountPurgeInterval <= 0 {
+		return fmt.Errorf("ACCOUNT_DELETION_GRACE_PERIOD must not be negative and ACCOUNT_PURGE_INTERVAL must be positive")
+	}
+	if c.PasswordHash.Algorithm != "bcrypt" && c.PasswordHash.Algorithm != "argon2id" {
+		return fmt.Errorf("unsupported PASSWORD_HASH_ALGORITHM: %s", c.PasswordHash.Algorithm)
+	}
+	if c.PasswordHash.BcryptCost < 4 || c.PasswordHash.BcryptCost > 31 {
+		return fmt.Errorf("BCRYPT_COST must be between 4 and 31")
+	}
+	if c.PasswordHash.Argon2Memory < 8*c.PasswordHash.Argon2Parallelism || c.PasswordHash.Argon2Iterations <= 0 ||
+		c.PasswordHash.Argon2Parallelism <= 0 || c.PasswordHash.Argon2Parallelism > 255 {
+		return fmt.Errorf("ARGON2_MEMORY must be at least 8 KiB per lane, ARGON2_ITERATIONS and ARGON2_PARALLELISM (up to 255) must be positive")
+	}
+	if c.PasswordPolicy.MinLength <= 0 || c.PasswordPolicy.MaxLength < c.PasswordPolicy.MinLength {
+		return fmt.Errorf("PASSWORD_MIN_LENGTH must be positive and not greater than PASSWORD_MAX_LENGTH")
+	}
+	if c.PasswordHash.Algorithm == "bcrypt" && c.PasswordPolicy.MaxLength > 72 {
+		return fmt.Errorf("PASSWORD_MAX_LENGTH must not exceed 72 with bcrypt")
+	}
+	for _, class := range c.PasswordPolicy.RequiredClasses {
+		switch class {
+		case "lower", "upper", "letter", "digit", "symbol":
+		default:
+			return fmt.Errorf("unsupported character class in PASSWORD_REQUIRED_CLASSES: %s", class)
+		}
+	}
+	if c.PasswordPolicy.History < 0 {
+		return fmt.Errorf("PASSWORD_HISTORY must not be negative")
+	}
+	if c.LoginPolicy.MinLength <= 0 || c.LoginPolicy.MaxLength < c.LoginPolicy.MinLength || c.LoginPolicy.MaxLength > 50 {
+		return fmt.Errorf("LOGIN_MIN_LENGTH must be positive and LOGIN_MAX_LENGTH must be between LOGIN_MIN_LENGTH and 50")
+	}
+	if _, err := regexp.Compile(c.LoginPolicy.Pattern); err != nil {
+		return fmt.Errorf("invalid LOGIN_PATTERN: %w", err)
+	}
+	if c.Listings.CursorSecret == "" {
+		return fmt.Errorf("LISTINGS_CURSOR_SECRET is required")
+	}
+	if c.Listings.MaxImages <= 0 {
+		return fmt.Errorf("LISTINGS_MAX_IMAGES must be positive")
+	}
+	if c.Uploads.MaxSize <= 0 || c.Uploads.URLTTL <= 0 || c.Uploads.OrphanTTL <= 0 || c.Uploads.CleanupInterval <= 0 {
+		return fmt.Errorf("UPLOAD_MAX_SIZE, UPLOAD_URL_TTL, UPLOAD_ORPHAN_TTL and UPLOAD_CLEANUP_INTERVAL must be positive")
+	}
+	if c.Uploads.URLSecret == "" {
+		return fmt.Errorf("UPLOAD_URL_SECRET is required")
+	}
+	switch c.Uploads.Driver {
+	case "local":
+	case "s3":
+		if c.Uploads.S3Endpoint == "" || c.Uploads.S3Bucket == "" || c.Uploads.S3AccessKey == "" || c.Uploads.S3SecretKey == "" {
+			return fmt.Errorf("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required when UPLOAD_DRIVER is s3")
+		}
+	default:
+		return fmt.Errorf("unsupported UPLOAD_DRIVER: %s", c.Uploads.Driver)
+	}
+	if c.SecurityEvents.ClientDataRetention <= 0 || c.SecurityEvents.CleanupInterval <= 0 {
+		return fmt.Errorf("SECURITY_EVENTS_CLIENT_DATA_RETENTION and SECURITY_EVENTS_CLEANUP_INTERVAL must be positive")
+	}
+	if c.Auth.ImpersonationTokenTTL <= 0 {
+		return fmt.Errorf("IMPERSONATION_TOKEN_TTL must be positive")
+	}
+	if c.OIDC.Enabled() && (c.OIDC.ClientID == "" || c.OIDC.RedirectURL == "") {
+		return fmt.Errorf("OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required when OIDC_ISSUER is set")
 	}
 	if c.Database.Password == "" {
 		return fmt.Errorf("DB_PASSWORD is required")
@@ -86,3 +493,48 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList разбирает список значений через запятую
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvListOrDefault(key string, defaultValue []string) []string {
+	if values := getEnvList(key); len(values) > 0 {
+		return values
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"marketplace-api/internal/database/migrations"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	testTable := []struct {
+		name             string
+		files            fstest.MapFS
+		expectedVersions []int64
+		expectedError    string
+	}{
+		{
+			name: "OK - sorted by version",
+			files: fstest.MapFS{
+				"0010_add_index.up.sql":     {Data: []byte("CREATE INDEX i ON t (c);")},
+				"0010_add_index.down.sql":   {Data: []byte("DROP INDEX i;")},
+				"0002_add_table.up.sql":     {Data: []byte("CREATE TABLE t (c INT);")},
+				"0002_add_table.down.sql":   {Data: []byte("DROP TABLE t;")},
+				"README.md":                 {Data: []byte("ignored")},
+				"0003_other_thing.up.sql":   {Data: []byte("SELECT 1;")},
+				"0003_other_thing.down.sql": {Data: []byte("SELECT 1;")},
+			},
+			expectedVersions: []int64{2, 3, 10},
+		},
+		{
+			name: "Missing down file",
+			files: fstest.MapFS{
+				"0001_init.up.sql": {Data: []byte("SELECT 1;")},
+			},
+			expectedError: "migration 1_init has no down file",
+		},
+		{
+			name: "Duplicate version",
+			files: fstest.MapFS{
+				"0001_init.up.sql":    {Data: []byte("SELECT 1;")},
+				"0001_init.down.sql":  {Data: []byte("SELECT 1;")},
+				"0001_other.up.sql":   {Data: []byte("SELECT 1;")},
+				"0001_other.down.sql": {Data: []byte("SELECT 1;")},
+			},
+			expectedError: "duplicate migration version 1: init and other",
+		},
+		{
+			name: "Invalid file name",
+			files: fstest.MapFS{
+				"init.sql": {Data: []byte("SELECT 1;")},
+			},
+			expectedError: "invalid migration file name: init.sql",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := LoadMigrations(testCase.files)
+
+			if testCase.expectedError != "" {
+				assert.EqualError(t, err, testCase.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			var versions []int64
+			for _, m := range result {
+				versions = append(versions, m.Version)
+				assert.Len(t, m.Checksum, 64)
+			}
+			assert.Equal(t, testCase.expectedVersions, versions)
+		})
+	}
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	result, err := LoadMigrations(migrations.FS)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, result)
+	assert.Equal(t, int64(1), result[0].Version)
+	for i := 1; i < len(result); i++ {
+		assert.Greater(t, result[i].Version, result[i-1].Version)
+	}
+}
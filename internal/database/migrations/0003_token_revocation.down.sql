@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS token_version;
+
+DROP TABLE IF EXISTS revoked_tokens;
@@ -0,0 +1,11 @@
+CREATE TABLE revoked_tokens (
+	jti VARCHAR(64) PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	expires_at TIMESTAMP NOT NULL,
+	revoked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
+
+-- массовый отзыв токенов увеличивает версию; токены с меньшей версией в claim ver не принимаются
+ALTER TABLE users ADD COLUMN token_version INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,10 @@
+CREATE TABLE signing_keys (
+	kid VARCHAR(64) PRIMARY KEY,
+	algorithm VARCHAR(16) NOT NULL,
+	-- закрытый ключ в PEM, зашифрованный ключом JWT_KEY_ENCRYPTION_KEY
+	private_key TEXT NOT NULL,
+	activates_at TIMESTAMP NOT NULL,
+	retires_at TIMESTAMP,
+	expires_at TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS login_known_ips;
+DROP TABLE IF EXISTS login_attempts;
@@ -0,0 +1,20 @@
+CREATE TABLE login_attempts (
+	scope VARCHAR(20) NOT NULL CHECK (scope IN ('login', 'ip', 'password_reset')),
+	key VARCHAR(255) NOT NULL,
+	failures INTEGER NOT NULL DEFAULT 0,
+	last_failure_at TIMESTAMP NOT NULL,
+	locked_until TIMESTAMP,
+	PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idx_login_attempts_last_failure_at ON login_attempts (last_failure_at);
+
+-- адреса успешных входов: для них не действуют блокировка и задержка по логину
+CREATE TABLE login_known_ips (
+	login VARCHAR(255) NOT NULL,
+	ip_address VARCHAR(45) NOT NULL,
+	last_success_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (login, ip_address)
+);
+
+CREATE INDEX idx_login_known_ips_last_success_at ON login_known_ips (last_success_at);
@@ -0,0 +1,21 @@
+CREATE TABLE sessions (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	family_id VARCHAR(64) UNIQUE NOT NULL,
+	user_agent VARCHAR(512) NOT NULL DEFAULT '',
+	ip_address VARCHAR(45) NOT NULL DEFAULT '',
+	expires_at TIMESTAMP NOT NULL,
+	last_seen_at TIMESTAMP NOT NULL,
+	revoked_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions (user_id);
+
+-- действующие семейства refresh-токенов становятся сеансами без сведений об устройстве
+INSERT INTO sessions (user_id, family_id, expires_at, last_seen_at, created_at)
+SELECT user_id, family_id, MAX(expires_at), MAX(created_at), MIN(created_at)
+FROM refresh_tokens
+WHERE revoked_at IS NULL
+GROUP BY user_id, family_id
+HAVING MAX(expires_at) > (NOW() AT TIME ZONE 'UTC');
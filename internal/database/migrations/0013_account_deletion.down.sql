@@ -0,0 +1,8 @@
+ALTER TABLE listings DROP CONSTRAINT listings_user_id_fkey;
+ALTER TABLE listings ADD CONSTRAINT listings_user_id_fkey
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS idx_users_deletion_scheduled_at;
+
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deletion_scheduled_at;
@@ -0,0 +1,10 @@
+ALTER TABLE users ADD COLUMN deletion_scheduled_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_users_deletion_scheduled_at ON users (deletion_scheduled_at)
+	WHERE deletion_scheduled_at IS NOT NULL AND deleted_at IS NULL;
+
+-- удаленные пользователи обезличиваются, а не удаляются; объявления удаляются явно при очистке аккаунта
+ALTER TABLE listings DROP CONSTRAINT listings_user_id_fkey;
+ALTER TABLE listings ADD CONSTRAINT listings_user_id_fkey
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
@@ -0,0 +1,12 @@
+CREATE TABLE user_profiles (
+	user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+	display_name VARCHAR(100) NOT NULL DEFAULT '',
+	bio TEXT NOT NULL DEFAULT '',
+	avatar_url VARCHAR(500),
+	location VARCHAR(100) NOT NULL DEFAULT '',
+	phone VARCHAR(32) NOT NULL DEFAULT '',
+	show_email BOOLEAN NOT NULL DEFAULT FALSE,
+	show_phone BOOLEAN NOT NULL DEFAULT FALSE,
+	preferred_contact VARCHAR(10) NOT NULL DEFAULT '' CHECK (preferred_contact IN ('', 'email', 'phone')),
+	updated_at TIMESTAMP NOT NULL
+);
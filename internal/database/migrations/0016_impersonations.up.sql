@@ -0,0 +1,30 @@
+-- записи не удаляются вместе с аккаунтом: это журнал действий администраторов
+CREATE TABLE impersonations (
+	id SERIAL PRIMARY KEY,
+	admin_id INTEGER NOT NULL REFERENCES users(id),
+	user_id INTEGER NOT NULL REFERENCES users(id),
+	token_id VARCHAR(64) UNIQUE NOT NULL,
+	reason VARCHAR(500) NOT NULL,
+	allow_write BOOLEAN NOT NULL DEFAULT FALSE,
+	ip_address VARCHAR(45) NOT NULL DEFAULT '',
+	user_agent VARCHAR(512) NOT NULL DEFAULT '',
+	expires_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_impersonations_admin_id ON impersonations (admin_id, created_at DESC);
+CREATE INDEX idx_impersonations_user_id ON impersonations (user_id, created_at DESC);
+
+CREATE TABLE impersonation_requests (
+	id BIGSERIAL PRIMARY KEY,
+	impersonation_id INTEGER NOT NULL REFERENCES impersonations(id) ON DELETE CASCADE,
+	method VARCHAR(10) NOT NULL,
+	path VARCHAR(2048) NOT NULL,
+	ip_address VARCHAR(45) NOT NULL DEFAULT '',
+	blocked BOOLEAN NOT NULL DEFAULT FALSE,
+	-- NULL, пока запрос выполняется
+	status INTEGER,
+	created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_impersonation_requests_impersonation_id ON impersonation_requests (impersonation_id, id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS security_events;
+DROP FUNCTION IF EXISTS security_events_append_only();
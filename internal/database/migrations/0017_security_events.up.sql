@@ -0,0 +1,42 @@
+-- журнал только пополняется: записи не удаляются, в том числе при удалении аккаунта.
+-- Очистка аккаунта и истечение срока хранения обезличивают записи: стирают логин, IP-адрес и user agent.
+-- Изменение разрешено только в транзакции, где включен флаг marketplace.anonymize_security_events,
+-- и только если остальные поля записи не меняются
+CREATE TABLE security_events (
+	id BIGSERIAL PRIMARY KEY,
+	event_type VARCHAR(50) NOT NULL,
+	-- пользователь, к аккаунту которого относится событие; NULL при входе с неизвестным логином
+	user_id INTEGER REFERENCES users(id),
+	-- кто выполнил действие, если это не сам пользователь: администратор или вход от имени пользователя
+	actor_id INTEGER REFERENCES users(id),
+	-- логин, указанный при входе, если пользователь с таким логином не найден
+	login VARCHAR(255) NOT NULL DEFAULT '',
+	ip_address VARCHAR(45) NOT NULL DEFAULT '',
+	user_agent VARCHAR(512) NOT NULL DEFAULT '',
+	request_id VARCHAR(64) NOT NULL DEFAULT '',
+	details JSONB NOT NULL DEFAULT '{}',
+	created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_security_events_user_id ON security_events (user_id, created_at DESC);
+CREATE INDEX idx_security_events_actor_id ON security_events (actor_id, created_at DESC);
+CREATE INDEX idx_security_events_created_at ON security_events (created_at DESC);
+
+CREATE FUNCTION security_events_append_only() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'UPDATE'
+	   AND current_setting('marketplace.anonymize_security_events', true) = 'on'
+	   AND NEW.login = '' AND NEW.ip_address = '' AND NEW.user_agent = ''
+	   AND (NEW.id, NEW.event_type, NEW.user_id, NEW.actor_id, NEW.request_id, NEW.details, NEW.created_at)
+	       IS NOT DISTINCT FROM
+	       (OLD.id, OLD.event_type, OLD.user_id, OLD.actor_id, OLD.request_id, OLD.details, OLD.created_at) THEN
+		RETURN NEW;
+	END IF;
+
+	RAISE EXCEPTION 'security_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER security_events_append_only
+	BEFORE UPDATE OR DELETE ON security_events
+	FOR EACH ROW EXECUTE FUNCTION security_events_append_only();
@@ -0,0 +1,22 @@
+-- удалить категорию, у которой есть подкатегории или объявления, база не даст
+CREATE TABLE categories (
+	id SERIAL PRIMARY KEY,
+	parent_id INTEGER REFERENCES categories(id),
+	slug VARCHAR(100) UNIQUE NOT NULL,
+	name VARCHAR(100) NOT NULL,
+	sort_order INTEGER NOT NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CHECK (parent_id <> id)
+);
+
+CREATE INDEX idx_categories_parent_id ON categories (parent_id, sort_order);
+
+-- существующие объявления попадают в категорию «Другое», после чего категория становится обязательной
+INSERT INTO categories (slug, name, sort_order) VALUES ('other', 'Другое', 1000);
+
+ALTER TABLE listings ADD COLUMN category_id INTEGER REFERENCES categories(id);
+UPDATE listings SET category_id = (SELECT id FROM categories WHERE slug = 'other');
+ALTER TABLE listings ALTER COLUMN category_id SET NOT NULL;
+
+CREATE INDEX idx_listings_category_id ON listings (category_id);
@@ -0,0 +1,8 @@
+-- конфигурация russian стеммит кириллические слова русским стеммером Snowball, а латинские — английским,
+-- поэтому одного вектора достаточно для поиска на обоих языках. Заголовок весит больше описания
+ALTER TABLE listings ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+	setweight(to_tsvector('russian', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_listings_search_vector ON listings USING GIN (search_vector);
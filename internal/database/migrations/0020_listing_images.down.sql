@@ -0,0 +1,9 @@
+-- при откате у объявления остается только обложка, и только если она задана URL
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS image_url VARCHAR(500);
+
+UPDATE listings l
+SET image_url = li.url
+FROM listing_images li
+WHERE li.listing_id = l.id AND li.position = 0;
+
+DROP TABLE IF EXISTS listing_images;
@@ -0,0 +1,22 @@
+-- изображение задается внешним URL либо ключом загруженного файла; позиции объявления идут подряд с 0,
+-- изображение на позиции 0 — обложка. Уникальность позиции проверяется в конце транзакции, чтобы
+-- изображения можно было переставлять по одному
+CREATE TABLE listing_images (
+	id SERIAL PRIMARY KEY,
+	listing_id INTEGER NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+	url VARCHAR(500),
+	file_key VARCHAR(255),
+	position INTEGER NOT NULL CHECK (position >= 0),
+	alt_text VARCHAR(255) NOT NULL DEFAULT '',
+	width INTEGER CHECK (width > 0),
+	height INTEGER CHECK (height > 0),
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CHECK ((url IS NULL) <> (file_key IS NULL)),
+	CONSTRAINT listing_images_listing_position_key UNIQUE (listing_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+
+-- единственная картинка объявления становится его обложкой
+INSERT INTO listing_images (listing_id, url, position)
+SELECT id, image_url, 0 FROM listings WHERE image_url IS NOT NULL AND image_url <> '';
+
+ALTER TABLE listings DROP COLUMN image_url;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_listing_images_file_key;
+DROP TABLE IF EXISTS uploads;
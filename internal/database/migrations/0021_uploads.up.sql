@@ -0,0 +1,20 @@
+-- загруженные пользователями файлы. Ключ файла в хранилище получается из SHA-256 содержимого,
+-- поэтому одинаковые файлы разных пользователей хранятся один раз. Загрузки удаленного аккаунта
+-- вместе с файлами удаляет очистка аккаунта; строка users при этом остается, так что ON DELETE SET NULL
+-- срабатывает, только если пользователя удалить из базы вручную
+CREATE TABLE uploads (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+	content_hash CHAR(64) NOT NULL,
+	blob_key VARCHAR(255) NOT NULL,
+	content_type VARCHAR(100) NOT NULL,
+	size BIGINT NOT NULL CHECK (size > 0),
+	width INTEGER CHECK (width > 0),
+	height INTEGER CHECK (height > 0),
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (user_id, content_hash)
+);
+
+CREATE INDEX idx_uploads_blob_key ON uploads(blob_key);
+CREATE INDEX idx_uploads_created_at ON uploads(created_at);
+CREATE INDEX idx_listing_images_file_key ON listing_images(file_key) WHERE file_key IS NOT NULL;
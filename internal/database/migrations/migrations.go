@@ -0,0 +1,11 @@
+// Package migrations содержит SQL-миграции схемы, встроенные в бинарник.
+//
+// Каждая миграция — пара файлов NNNN_name.up.sql и NNNN_name.down.sql.
+// Номера версий должны возрастать; уже примененные файлы менять нельзя,
+// так как мигратор сверяет их контрольные суммы.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
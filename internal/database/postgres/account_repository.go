@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// purgedUserTables таблицы с персональными данными пользователя, которые удаляются при очистке аккаунта.
+// Загрузки удаляются отдельно: их файлы нужно убрать из хранилища
+var purgedUserTables = []string{
+	"listings",
+	"user_profiles",
+	"user_identities",
+	"api_keys",
+	"sessions",
+	"refresh_tokens",
+	"login_challenges",
+	"recovery_codes",
+	"user_totp",
+	"email_verification_tokens",
+	"password_reset_tokens",
+	"password_history",
+}
+
+type AccountRepository struct {
+	db *sql.DB
+}
+
+func NewAccountRepository(db *sql.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+// ListDueForPurge возвращает ID пользователей, срок удаления которых наступил к моменту now
+func (r *AccountRepository) ListDueForPurge(now time.Time, limit int) ([]int, error) {
+	query := `
+		SELECT id
+		FROM users
+		WHERE deletion_scheduled_at <= $1 AND deleted_at IS NULL
+		ORDER BY deletion_scheduled_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts due for purge: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan user id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return ids, nil
+}
+
+// PurgeUser удаляет персональные данные пользователя, срок удаления которого наступил.
+// Строка users остается, чтобы ID не переиспользовались и ссылки на пользователя не удалялись каскадно,
+// но логин, email и пароль обезличиваются, как и записи журнала безопасности. Логин вида deleted-<id> не пройдет проверку при регистрации,
+// поэтому занять его заранее нельзя. Загрузки пользователя удаляются, а ключи файлов, на которые больше
+// не ссылается ни одна загрузка, передаются в deleteFile для удаления из хранилища.
+// Возвращает false, если удаление отменено или уже выполнено
+func (r *AccountRepository) PurgeUser(userID int, now time.Time, deleteFile func(key string)) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var login string
+	err = tx.QueryRow(`
+		SELECT login
+		FROM users
+		WHERE id = $1 AND deletion_scheduled_at <= $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`, userID, now).Scan(&login)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	for _, table := range purgedUserTables {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE user_id = $1", userID); err != nil {
+			return false, fmt.Errorf("failed to delete %s: %w", table, err)
+		}
+	}
+
+	deletedKeys, err := queryStrings(tx, "DELETE FROM uploads WHERE user_id = $1 RETURNING blob_key", userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete uploads: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM login_attempts WHERE scope = 'login' AND key = $1", login); err != nil {
+		return false, fmt.Errorf("failed to delete login attempts: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM login_known_ips WHERE login = $1", login); err != nil {
+		return false, fmt.Errorf("failed to delete known login addresses: %w", err)
+	}
+
+	if _, err := anonymizeSecurityEvents(tx, "user_id = $1", userID); err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE users
+		SET login = 'deleted-' || id,
+		    email = NULL,
+		    email_verified_at = NULL,
+		    password_hash = '',
+		    role = 'user',
+		    two_factor_enabled = FALSE,
+		    token_version = token_version + 1,
+		    deleted_at = $2,
+		    updated_at = $2
+		WHERE id = $1
+	`, userID, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to anonymize user: %w", err)
+	}
+
+	// файлы удаляются последними, чтобы ошибка в остальных шагах не оставила загрузки без файлов
+	if _, err := deleteUnusedFiles(tx, deletedKeys, deleteFile); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"marketplace-api/internal/models"
+)
+
+// apiKeyLastUsedPrecision как часто обновляется last_used_at одного ключа.
+// Ключи скриптов используются на каждом запросе, запись при каждом из них не нужна
+const apiKeyLastUsedPrecision = time.Minute
+
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// CreateAPIKey сохраняет новый API-ключ
+func (r *APIKeyRepository) CreateAPIKey(userID int, name, prefix, keyHash string, scopes []string, expiresAt *time.Time, now time.Time) (*models.APIKey, error) {
+	query := `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, user_id, name, prefix, scopes, expires_at, last_used_at, created_at
+	`
+
+	key, err := scanAPIKey(r.db.QueryRow(query, userID, name, prefix, keyHash, pq.Array(scopes), expiresAt, now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return key, nil
+}
+
+// ListAPIKeys получает API-ключи пользователя, новые первыми
+func (r *APIKeyRepository) ListAPIKeys(userID int) ([]models.APIKey, error) {
+	query := `
+		SELECT id, user_id, name, prefix, scopes, expires_at, last_used_at, created_at
+		FROM api_keys
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return keys, nil
+}
+
+// DeleteAPIKey удаляет API-ключ пользователя
+func (r *APIKeyRepository) DeleteAPIKey(userID, keyID int) error {
+	result, err := r.db.Exec("DELETE FROM api_keys WHERE id = $1 AND user_id = $2", keyID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete api key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("api key not found")
+	}
+
+	return nil
+}
+
+// GetActiveAPIKey находит действующий на момент now ключ по хешу вместе с логином и ролью владельца.
+// Ключи пользователя, удаляющего аккаунт, не действуют
+func (r *APIKeyRepository) GetActiveAPIKey(keyHash string, now time.Time) (*models.APIKeyOwner, error) {
+	query := `
+		SELECT k.id, k.user_id, k.name, k.prefix, k.scopes, k.expires_at, k.last_used_at, k.created_at,
+			u.login, u.role
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id
+		WHERE k.key_hash = $1 AND (k.expires_at IS NULL OR k.expires_at > $2)
+			AND u.deletion_scheduled_at IS NULL
+	`
+
+	var owner models.APIKeyOwner
+	err := r.db.QueryRow(query, keyHash, now).Scan(
+		&owner.ID,
+		&owner.UserID,
+		&owner.Name,
+		&owner.Prefix,
+		pq.Array(&owner.Scopes),
+		&owner.ExpiresAt,
+		&owner.LastUsedAt,
+		&owner.CreatedAt,
+		&owner.Login,
+		&owner.Role,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("api key not found")
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return &owner, nil
+}
+
+// TouchAPIKey отмечает использование ключа. Время обновляется не чаще раза в apiKeyLastUsedPrecision
+func (r *APIKeyRepository) TouchAPIKey(keyID int, now time.Time) error {
+	query := `
+		UPDATE api_keys
+		SET last_used_at = $1
+		WHERE id = $2 AND (last_used_at IS NULL OR last_used_at < $3)
+	`
+
+	if _, err := r.db.Exec(query, now, keyID, now.Add(-apiKeyLastUsedPrecision)); err != nil {
+		return fmt.Errorf("failed to update api key usage: %w", err)
+	}
+
+	return nil
+}
+
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	var key models.APIKey
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		pq.Array(&key.Scopes),
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
@@ -27,7 +27,7 @@ func (r *AuthRepository) RegisterUser(login, passwordHash string) (*models.User,
 		return nil, fmt.Errorf(models.ErrUserExists)
 	}
 
-	user, err := r.userRepo.CreateUser(login, passwordHash)
+	user, err := r.userRepo.CreateUser(login, "", passwordHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to register user: %w", err)
 	}
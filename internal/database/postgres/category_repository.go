@@ -0,0 +1,268 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"marketplace-api/internal/models"
+)
+
+// categoryColumns поля категории в порядке сканирования scanCategory
+const categoryColumns = `id, parent_id, slug, name, sort_order, created_at, updated_at`
+
+// categorySubtreeQuery выбирает ID категории с заданным slug и всех ее потомков.
+// UNION вместо UNION ALL завершает рекурсию, даже если в дереве окажется цикл
+const categorySubtreeQuery = `
+	WITH RECURSIVE subtree AS (
+		SELECT id FROM categories WHERE slug = $%[1]d
+		UNION
+		SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+	)
+	SELECT id FROM subtree
+`
+
+type CategoryRepository struct {
+	db *sql.DB
+}
+
+func NewCategoryRepository(db *sql.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// GetAllCategories получает все категории в порядке отображения
+func (r *CategoryRepository) GetAllCategories() ([]models.Category, error) {
+	query := `
+		SELECT ` + categoryColumns + `
+		FROM categories
+		ORDER BY sort_order, name, id
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	defer rows.Close()
+
+	categories := []models.Category{}
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, *category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return categories, nil
+}
+
+// GetCategoryByID получает категорию по ID
+func (r *CategoryRepository) GetCategoryByID(id int) (*models.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE id = $1`
+
+	category, err := scanCategory(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("category not found")
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	return category, nil
+}
+
+// GetCategoryBySlug получает категорию по slug
+func (r *CategoryRepository) GetCategoryBySlug(slug string) (*models.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE slug = $1`
+
+	category, err := scanCategory(r.db.QueryRow(query, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("category not found")
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	return category, nil
+}
+
+// CreateCategory создает категорию
+func (r *CategoryRepository) CreateCategory(req models.CreateCategoryRequest, now time.Time) (*models.Category, error) {
+	query := `
+		INSERT INTO categories (parent_id, slug, name, sort_order, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		RETURNING ` + categoryColumns
+
+	category, err := scanCategory(r.db.QueryRow(query, req.ParentID, req.Slug, req.Name, req.SortOrder, now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+
+	return category, nil
+}
+
+// UpdateCategory обновляет категорию. ParentID со значением 0 переносит категорию на верхний уровень.
+// Перенос выполняется под блокировкой таблицы, чтобы встречные переносы не создали цикл
+// между проверкой поддерева и обновлением
+func (r *CategoryRepository) UpdateCategory(id int, req models.UpdateCategoryRequest, now time.Time) (*models.Category, error) {
+	var setParts []string
+	var args []interface{}
+	argIndex := 1
+
+	if req.ParentID != nil {
+		setParts = append(setParts, fmt.Sprintf("parent_id = NULLIF($%d, 0)", argIndex))
+		args = append(args, *req.ParentID)
+		argIndex++
+	}
+
+	if req.Slug != nil {
+		setParts = append(setParts, fmt.Sprintf("slug = $%d", argIndex))
+		args = append(args, *req.Slug)
+		argIndex++
+	}
+
+	if req.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, *req.Name)
+		argIndex++
+	}
+
+	if req.SortOrder != nil {
+		setParts = append(setParts, fmt.Sprintf("sort_order = $%d", argIndex))
+		args = append(args, *req.SortOrder)
+		argIndex++
+	}
+
+	if len(setParts) == 0 {
+		return nil, fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex))
+	args = append(args, now)
+	argIndex++
+
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE categories
+		SET %s
+		WHERE id = $%d
+		RETURNING %s
+	`, strings.Join(setParts, ", "), argIndex, categoryColumns)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if req.ParentID != nil && *req.ParentID != 0 {
+		if _, err := tx.Exec("LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+			return nil, fmt.Errorf("failed to lock categories: %w", err)
+		}
+
+		inSubtree, err := isInSubtree(tx, id, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if inSubtree {
+			return nil, fmt.Errorf("category cannot be moved into itself or its subcategory")
+		}
+	}
+
+	category, err := scanCategory(tx.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("category not found")
+		}
+		return nil, fmt.Errorf("failed to update category: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return category, nil
+}
+
+// DeleteCategory удаляет категорию. Наличие подкатегорий и объявлений проверяет сервис
+func (r *CategoryRepository) DeleteCategory(id int) error {
+	result, err := r.db.Exec("DELETE FROM categories WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("category not found")
+	}
+
+	return nil
+}
+
+// isInSubtree проверяет, что категория candidateID совпадает с categoryID или является ее потомком
+func isInSubtree(tx *sql.Tx, categoryID, candidateID int) (bool, error) {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = $1
+			UNION
+			SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+		)
+		SELECT EXISTS (SELECT 1 FROM subtree WHERE id = $2)
+	`
+
+	var exists bool
+	if err := tx.QueryRow(query, categoryID, candidateID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check category subtree: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetCategoryUsage проверяет, есть ли у категории подкатегории и объявления
+func (r *CategoryRepository) GetCategoryUsage(id int) (hasChildren bool, hasListings bool, err error) {
+	query := `
+		SELECT EXISTS (SELECT 1 FROM categories WHERE parent_id = $1),
+		       EXISTS (SELECT 1 FROM listings WHERE category_id = $1)
+	`
+
+	if err := r.db.QueryRow(query, id).Scan(&hasChildren, &hasListings); err != nil {
+		return false, false, fmt.Errorf("failed to check category usage: %w", err)
+	}
+
+	return hasChildren, hasListings, nil
+}
+
+func scanCategory(row rowScanner) (*models.Category, error) {
+	var category models.Category
+	var parentID sql.NullInt64
+
+	err := row.Scan(
+		&category.ID,
+		&parentID,
+		&category.Slug,
+		&category.Name,
+		&category.SortOrder,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		category.ParentID = &id
+	}
+
+	return &category, nil
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type EmailVerificationRepository struct {
+	db *sql.DB
+}
+
+func NewEmailVerificationRepository(db *sql.DB) *EmailVerificationRepository {
+	return &EmailVerificationRepository{db: db}
+}
+
+// CreateVerificationToken сохраняет хеш нового токена подтверждения email.
+// Ранее выданные и еще не использованные токены пользователя аннулируются
+func (r *EmailVerificationRepository) CreateVerificationToken(userID int, email, tokenHash string, expiresAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	invalidateQuery := `
+		UPDATE email_verification_tokens
+		SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND used_at IS NULL
+	`
+	if _, err := tx.Exec(invalidateQuery, userID); err != nil {
+		return fmt.Errorf("failed to invalidate verification tokens: %w", err)
+	}
+
+	insertQuery := `
+		INSERT INTO email_verification_tokens (user_id, email, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := tx.Exec(insertQuery, userID, email, tokenHash, expiresAt); err != nil {
+		return fmt.Errorf("failed to create verification token: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetLastSentAge возвращает, сколько времени прошло с выдачи последнего токена пользователя,
+// или nil, если токенов не было. Время считается по часам базы данных
+func (r *EmailVerificationRepository) GetLastSentAge(userID int) (*time.Duration, error) {
+	query := `
+		SELECT EXTRACT(EPOCH FROM (LOCALTIMESTAMP - MAX(created_at)))
+		FROM email_verification_tokens
+		WHERE user_id = $1
+	`
+
+	var seconds sql.NullFloat64
+	if err := r.db.QueryRow(query, userID).Scan(&seconds); err != nil {
+		return nil, fmt.Errorf("failed to get last verification time: %w", err)
+	}
+
+	if !seconds.Valid {
+		return nil, nil
+	}
+
+	age := time.Duration(seconds.Float64 * float64(time.Second))
+	return &age, nil
+}
+
+// VerifyEmail атомарно погашает токен и отмечает email пользователя подтвержденным.
+// Токен действует, только если email пользователя не менялся после его выдачи.
+// Использованный, истекший или неизвестный токен дает ошибку "verification token not found"
+func (r *EmailVerificationRepository) VerifyEmail(tokenHash string, now time.Time) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	consumeQuery := `
+		UPDATE email_verification_tokens
+		SET used_at = $2
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING user_id, email
+	`
+
+	var userID int
+	var email string
+	err = tx.QueryRow(consumeQuery, tokenHash, now).Scan(&userID, &email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("verification token not found")
+		}
+		return 0, fmt.Errorf("failed to consume verification token: %w", err)
+	}
+
+	verifyQuery := `
+		UPDATE users
+		SET email_verified_at = $1, updated_at = $1
+		WHERE id = $2 AND email = $3
+	`
+	result, err := tx.Exec(verifyQuery, now, userID, email)
+	if err != nil {
+		return 0, fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return 0, fmt.Errorf("verification token not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit email verification: %w", err)
+	}
+
+	return userID, nil
+}
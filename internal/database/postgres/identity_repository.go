@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"marketplace-api/internal/models"
+)
+
+// identityColumns поля внешней учетной записи в порядке сканирования scanIdentity
+const identityColumns = `id, user_id, issuer, subject, COALESCE(email, ''), last_login_at, created_at`
+
+type IdentityRepository struct {
+	db *sql.DB
+}
+
+func NewIdentityRepository(db *sql.DB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// TouchIdentity отмечает вход через внешнюю учетную запись и возвращает ID ее владельца
+func (r *IdentityRepository) TouchIdentity(issuer, subject, email string, now time.Time) (int, error) {
+	query := `
+		UPDATE user_identities
+		SET last_login_at = $3, email = NULLIF($4, '')
+		WHERE issuer = $1 AND subject = $2
+		RETURNING user_id
+	`
+
+	var userID int
+	err := r.db.QueryRow(query, issuer, subject, now, email).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("identity not found")
+		}
+		return 0, fmt.Errorf("failed to touch identity: %w", err)
+	}
+
+	return userID, nil
+}
+
+// LinkIdentity привязывает внешнюю учетную запись к существующему пользователю
+func (r *IdentityRepository) LinkIdentity(userID int, issuer, subject, email string, now time.Time) error {
+	if err := insertIdentity(r.db, userID, issuer, subject, email, now); err != nil {
+		return fmt.Errorf("failed to link identity: %w", err)
+	}
+
+	return nil
+}
+
+// CreateUserWithIdentity создает пользователя без пароля вместе с внешней учетной записью.
+// Email, подтвержденный провайдером, сразу считается подтвержденным
+func (r *IdentityRepository) CreateUserWithIdentity(login, email string, emailVerified bool, issuer, subject string, now time.Time) (*models.User, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var verifiedAt *time.Time
+	if email != "" && emailVerified {
+		verifiedAt = &now
+	}
+
+	query := `
+		INSERT INTO users (login, email, email_verified_at, password_hash, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, '', $4, $4)
+		RETURNING ` + userColumns
+
+	user, err := scanUser(tx.QueryRow(query, login, email, verifiedAt, now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if err := insertIdentity(tx, user.ID, issuer, subject, email, now); err != nil {
+		return nil, fmt.Errorf("failed to link identity: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return user, nil
+}
+
+// ListIdentities получает внешние учетные записи пользователя
+func (r *IdentityRepository) ListIdentities(userID int) ([]models.UserIdentity, error) {
+	query := `
+		SELECT ` + identityColumns + `
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identities: %w", err)
+	}
+	defer rows.Close()
+
+	identities := []models.UserIdentity{}
+	for rows.Next() {
+		var identity models.UserIdentity
+		err := rows.Scan(
+			&identity.ID,
+			&identity.UserID,
+			&identity.Issuer,
+			&identity.Subject,
+			&identity.Email,
+			&identity.LastLoginAt,
+			&identity.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan identity: %w", err)
+		}
+		identities = append(identities, identity)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return identities, nil
+}
+
+// CreateLoginState сохраняет незавершенный вход через провайдера и удаляет просроченные
+func (r *IdentityRepository) CreateLoginState(stateHash string, state models.OIDCLoginState, now time.Time) error {
+	if _, err := r.db.Exec("DELETE FROM oidc_login_states WHERE expires_at < $1", now); err != nil {
+		return fmt.Errorf("failed to delete expired login states: %w", err)
+	}
+
+	query := `
+		INSERT INTO oidc_login_states (state_hash, code_verifier, nonce, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	if _, err := r.db.Exec(query, stateHash, state.CodeVerifier, state.Nonce, state.ExpiresAt, now); err != nil {
+		return fmt.Errorf("failed to create login state: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeLoginState атомарно удаляет и возвращает действующий незавершенный вход.
+// Повторно state использовать нельзя
+func (r *IdentityRepository) ConsumeLoginState(stateHash string, now time.Time) (*models.OIDCLoginState, error) {
+	query := `
+		DELETE FROM oidc_login_states
+		WHERE state_hash = $1 AND expires_at > $2
+		RETURNING code_verifier, nonce, expires_at
+	`
+
+	var state models.OIDCLoginState
+	err := r.db.QueryRow(query, stateHash, now).Scan(&state.CodeVerifier, &state.Nonce, &state.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("state not found")
+		}
+		return nil, fmt.Errorf("failed to consume login state: %w", err)
+	}
+
+	return &state, nil
+}
+
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertIdentity(db execer, userID int, issuer, subject, email string, now time.Time) error {
+	query := `
+		INSERT INTO user_identities (user_id, issuer, subject, email, last_login_at, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $5)
+	`
+
+	_, err := db.Exec(query, userID, issuer, subject, email, now)
+	return err
+}
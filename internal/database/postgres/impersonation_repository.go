@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"marketplace-api/internal/models"
+)
+
+type ImpersonationRepository struct {
+	db *sql.DB
+}
+
+func NewImpersonationRepository(db *sql.DB) *ImpersonationRepository {
+	return &ImpersonationRepository{db: db}
+}
+
+const impersonationColumns = `
+	i.id, i.admin_id, a.login, i.user_id, u.login, i.token_id, i.reason, i.allow_write,
+	i.ip_address, i.user_agent, i.expires_at, i.created_at,
+	(SELECT COUNT(*) FROM impersonation_requests r WHERE r.impersonation_id = i.id)
+`
+
+// CreateImpersonation сохраняет выданный токен входа от имени пользователя
+func (r *ImpersonationRepository) CreateImpersonation(imp *models.Impersonation) (*models.Impersonation, error) {
+	query := `
+		INSERT INTO impersonations (admin_id, user_id, token_id, reason, allow_write, ip_address, user_agent, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(
+		query,
+		imp.AdminID,
+		imp.UserID,
+		imp.TokenID,
+		imp.Reason,
+		imp.AllowWrite,
+		imp.IPAddress,
+		imp.UserAgent,
+		imp.ExpiresAt,
+		imp.CreatedAt,
+	).Scan(&imp.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create impersonation: %w", err)
+	}
+
+	return imp, nil
+}
+
+// GetImpersonationByID получает запись о входе вместе с выполненными запросами
+func (r *ImpersonationRepository) GetImpersonationByID(id int) (*models.Impersonation, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM impersonations i
+		JOIN users a ON a.id = i.admin_id
+		JOIN users u ON u.id = i.user_id
+		WHERE i.id = $1
+	`, impersonationColumns)
+
+	imp, err := scanImpersonation(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("impersonation not found")
+		}
+		return nil, fmt.Errorf("failed to get impersonation: %w", err)
+	}
+
+	requestsQuery := `
+		SELECT id, method, path, ip_address, blocked, status, created_at
+		FROM impersonation_requests
+		WHERE impersonation_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(requestsQuery, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get impersonated requests: %w", err)
+	}
+	defer rows.Close()
+
+	imp.Requests = []models.ImpersonatedRequest{}
+	for rows.Next() {
+		var req models.ImpersonatedRequest
+		var status sql.NullInt64
+		if err := rows.Scan(&req.ID, &req.Method, &req.Path, &req.IPAddress, &req.Blocked, &status, &req.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan impersonated request: %w", err)
+		}
+		if status.Valid {
+			code := int(status.Int64)
+			req.Status = &code
+		}
+		imp.Requests = append(imp.Requests, req)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return imp, nil
+}
+
+// ListImpersonations получает постраничный журнал входов от имени пользователей, начиная с последних
+func (r *ImpersonationRepository) ListImpersonations(filter models.ImpersonationsFilter) (*models.PaginatedImpersonations, error) {
+	whereClause := "WHERE 1=1"
+	var args []interface{}
+	if filter.AdminID > 0 {
+		args = append(args, filter.AdminID)
+		whereClause += fmt.Sprintf(" AND i.admin_id = $%d", len(args))
+	}
+	if filter.UserID > 0 {
+		args = append(args, filter.UserID)
+		whereClause += fmt.Sprintf(" AND i.user_id = $%d", len(args))
+	}
+
+	var total int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM impersonations i "+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count impersonations: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM impersonations i
+		JOIN users a ON a.id = i.admin_id
+		JOIN users u ON u.id = i.user_id
+		%s
+		ORDER BY i.created_at DESC, i.id DESC
+		LIMIT $%d OFFSET $%d
+	`, impersonationColumns, whereClause, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.GetOffset())
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get impersonations: %w", err)
+	}
+	defer rows.Close()
+
+	impersonations := []models.Impersonation{}
+	for rows.Next() {
+		imp, err := scanImpersonation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan impersonation: %w", err)
+		}
+		impersonations = append(impersonations, *imp)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return &models.PaginatedImpersonations{
+		Data:       impersonations,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: (total + filter.Limit - 1) / filter.Limit,
+	}, nil
+}
+
+// StartRequest записывает запрос, выполненный с токеном tokenID, до его обработки.
+// Статус ответа пишет FinishRequest
+func (r *ImpersonationRepository) StartRequest(tokenID, method, path, ipAddress string, blocked bool, now time.Time) (int64, error) {
+	query := `
+		INSERT INTO impersonation_requests (impersonation_id, method, path, ip_address, blocked, created_at)
+		SELECT id, $2, $3, $4, $5, $6
+		FROM impersonations
+		WHERE token_id = $1
+		RETURNING id
+	`
+
+	var id int64
+	err := r.db.QueryRow(query, tokenID, method, path, ipAddress, blocked, now).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("impersonation not found")
+		}
+		return 0, fmt.Errorf("failed to record impersonated request: %w", err)
+	}
+
+	return id, nil
+}
+
+// FinishRequest сохраняет код ответа на записанный запрос
+func (r *ImpersonationRepository) FinishRequest(requestID int64, status int) error {
+	_, err := r.db.Exec("UPDATE impersonation_requests SET status = $1 WHERE id = $2", status, requestID)
+	if err != nil {
+		return fmt.Errorf("failed to update impersonated request: %w", err)
+	}
+	return nil
+}
+
+func scanImpersonation(row rowScanner) (*models.Impersonation, error) {
+	var imp models.Impersonation
+	err := row.Scan(
+		&imp.ID,
+		&imp.AdminID,
+		&imp.AdminLogin,
+		&imp.UserID,
+		&imp.UserLogin,
+		&imp.TokenID,
+		&imp.Reason,
+		&imp.AllowWrite,
+		&imp.IPAddress,
+		&imp.UserAgent,
+		&imp.ExpiresAt,
+		&imp.CreatedAt,
+		&imp.RequestsCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &imp, nil
+}
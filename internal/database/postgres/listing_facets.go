@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"fmt"
+	"math"
+
+	"marketplace-api/internal/models"
+)
+
+const (
+	// listingPriceFacetBuckets количество интервалов гистограммы цен
+	listingPriceFacetBuckets = 10
+	// listingSellerFacetLimit сколько продавцов с наибольшим числом объявлений попадает в фасет
+	listingSellerFacetLimit = 20
+)
+
+// listingDateRangeFacets интервалы фасета по дате создания, отсчитываются от текущего момента.
+// Граница считается в БД через LOCALTIMESTAMP, как и значение created_at по умолчанию
+var listingDateRangeFacets = []struct {
+	key      string
+	interval string
+}{
+	{key: "last_24h", interval: "1 day"},
+	{key: "last_7d", interval: "7 days"},
+	{key: "last_30d", interval: "30 days"},
+}
+
+// fillListingFacets заполняет запрошенные фасеты по выборке baseQuery + whereClause с аргументами args
+func (r *ListingRepository) fillListingFacets(facets *models.ListingFacets, names []string, baseQuery, whereClause string, args []interface{}) error {
+	var err error
+	for _, name := range names {
+		switch name {
+		case models.FacetCategory:
+			facets.Categories, err = r.getCategoryFacet(baseQuery, whereClause, args)
+		case models.FacetPrice:
+			facets.Price, err = r.getPriceFacet(baseQuery, whereClause, args)
+		case models.FacetSeller:
+			facets.Sellers, err = r.getSellerFacet(baseQuery, whereClause, args)
+		case models.FacetCreatedAt:
+			facets.CreatedAt, err = r.getCreatedAtFacet(baseQuery, whereClause, args)
+		default:
+			err = fmt.Errorf("unknown facet %q", name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get %s facet: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// getCategoryFacet считает объявления по категориям, в которых они размещены.
+// Суммы по родительским категориям клиент может собрать по дереву категорий
+func (r *ListingRepository) getCategoryFacet(baseQuery, whereClause string, args []interface{}) ([]models.CategoryFacet, error) {
+	query := `
+		SELECT c.id, c.slug, c.name, COUNT(*)
+		` + baseQuery + `
+		JOIN categories c ON c.id = l.category_id
+		` + whereClause + `
+		GROUP BY c.id, c.slug, c.name
+		ORDER BY COUNT(*) DESC, c.name
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var buckets []models.CategoryFacet
+	for rows.Next() {
+		var bucket models.CategoryFacet
+		if err := rows.Scan(&bucket.CategoryID, &bucket.Slug, &bucket.Name, &bucket.Count); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets, rows.Err()
+}
+
+// getPriceFacet строит гистограмму цен: диапазон от минимальной до максимальной цены выборки
+// делится на listingPriceFacetBuckets равных интервалов, пустые интервалы тоже возвращаются
+func (r *ListingRepository) getPriceFacet(baseQuery, whereClause string, args []interface{}) ([]models.PriceFacet, error) {
+	// width_bucket относит максимальную цену к интервалу n+1, поэтому номер ограничивается сверху.
+	// Если все цены равны, width_bucket неприменим и вся выборка попадает в один интервал
+	query := fmt.Sprintf(`
+		WITH filtered AS (
+			SELECT l.price %[1]s %[2]s
+		), bounds AS (
+			SELECT MIN(price) AS lo, MAX(price) AS hi FROM filtered
+		)
+		SELECT b.lo, b.hi,
+		       CASE WHEN b.lo = b.hi THEN 1
+		            ELSE LEAST(width_bucket(f.price, b.lo, b.hi, %[3]d), %[3]d)
+		       END AS bucket,
+		       COUNT(*)
+		FROM filtered f CROSS JOIN bounds b
+		GROUP BY b.lo, b.hi, bucket
+		ORDER BY bucket
+	`, baseQuery, whereClause, listingPriceFacetBuckets)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lo, hi float64
+	counts := make(map[int]int)
+	for rows.Next() {
+		var bucket, count int
+		if err := rows.Scan(&lo, &hi, &bucket, &count); err != nil {
+			return nil, err
+		}
+		counts[bucket] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(counts) == 0 {
+		return nil, nil
+	}
+	if lo == hi {
+		return []models.PriceFacet{{From: lo, To: hi, Count: counts[1]}}, nil
+	}
+
+	width := (hi - lo) / listingPriceFacetBuckets
+	buckets := make([]models.PriceFacet, 0, listingPriceFacetBuckets)
+	for i := 0; i < listingPriceFacetBuckets; i++ {
+		to := roundPrice(lo + width*float64(i+1))
+		if i == listingPriceFacetBuckets-1 {
+			to = hi
+		}
+		buckets = append(buckets, models.PriceFacet{
+			From:  roundPrice(lo + width*float64(i)),
+			To:    to,
+			Count: counts[i+1],
+		})
+	}
+
+	return buckets, nil
+}
+
+// getSellerFacet считает объявления продавцов, возвращает listingSellerFacetLimit самых активных
+func (r *ListingRepository) getSellerFacet(baseQuery, whereClause string, args []interface{}) ([]models.SellerFacet, error) {
+	query := fmt.Sprintf(`
+		SELECT u.id, u.login, COUNT(*)
+		%s %s
+		GROUP BY u.id, u.login
+		ORDER BY COUNT(*) DESC, u.login
+		LIMIT %d
+	`, baseQuery, whereClause, listingSellerFacetLimit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var buckets []models.SellerFacet
+	for rows.Next() {
+		var bucket models.SellerFacet
+		if err := rows.Scan(&bucket.UserID, &bucket.Login, &bucket.Count); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets, rows.Err()
+}
+
+// getCreatedAtFacet считает объявления, созданные за каждый из интервалов listingDateRangeFacets
+func (r *ListingRepository) getCreatedAtFacet(baseQuery, whereClause string, args []interface{}) ([]models.DateRangeFacet, error) {
+	selectParts := ""
+	for i, rng := range listingDateRangeFacets {
+		if i > 0 {
+			selectParts += ", "
+		}
+		selectParts += fmt.Sprintf(
+			"LOCALTIMESTAMP - INTERVAL '%[1]s', COUNT(*) FILTER (WHERE l.created_at >= LOCALTIMESTAMP - INTERVAL '%[1]s')",
+			rng.interval,
+		)
+	}
+
+	query := "SELECT " + selectParts + " " + baseQuery + " " + whereClause
+
+	buckets := make([]models.DateRangeFacet, len(listingDateRangeFacets))
+	dest := make([]interface{}, 0, 2*len(buckets))
+	for i, rng := range listingDateRangeFacets {
+		buckets[i].Key = rng.key
+		dest = append(dest, &buckets[i].From, &buckets[i].Count)
+	}
+
+	if err := r.db.QueryRow(query, args...).Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// roundPrice округляет границу интервала до копеек
+func roundPrice(value float64) float64 {
+	return math.Round(value*100) / 100
+}
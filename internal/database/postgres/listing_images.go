@@ -0,0 +1,288 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+
+	"marketplace-api/internal/models"
+)
+
+// listingImageColumns поля изображения объявления в порядке сканирования scanListingImage
+const listingImageColumns = `id, listing_id, url, file_key, position, alt_text, width, height, created_at`
+
+// getListingImages получает изображения объявлений, сгруппированные по ID объявления и упорядоченные по позиции
+func (r *ListingRepository) getListingImages(listingIDs []int) (map[int][]models.ListingImage, error) {
+	images := make(map[int][]models.ListingImage)
+	if len(listingIDs) == 0 {
+		return images, nil
+	}
+
+	query := `
+		SELECT ` + listingImageColumns + `
+		FROM listing_images
+		WHERE listing_id = ANY($1)
+		ORDER BY listing_id, position
+	`
+
+	rows, err := r.db.Query(query, pq.Array(listingIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listing images: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		image, err := scanListingImage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan listing image: %w", err)
+		}
+		images[image.ListingID] = append(images[image.ListingID], *image)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return images, nil
+}
+
+// AddListingImage добавляет изображение в конец списка изображений объявления.
+// Если у объявления уже maxImages изображений, возвращает ошибку "too many images",
+// если файл изображения не загружен владельцем — "image file not found"
+func (r *ListingRepository) AddListingImage(listingID int, req models.ListingImageRequest, maxImages int, now time.Time) (*models.ListingImage, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := touchListing(tx, listingID, now); err != nil {
+		return nil, err
+	}
+
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM listing_images WHERE listing_id = $1", listingID).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count listing images: %w", err)
+	}
+	if count >= maxImages {
+		return nil, fmt.Errorf("too many images")
+	}
+
+	if err := lockImageUploads(tx, listingID, []models.ListingImageRequest{req}); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO listing_images (listing_id, url, file_key, position, alt_text, width, height, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + listingImageColumns
+
+	image, err := scanListingImage(tx.QueryRow(query, listingID, req.URL, req.FileKey, count, req.AltText, req.Width, req.Height, now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to add listing image: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return image, nil
+}
+
+// DeleteListingImage удаляет изображение объявления и сдвигает следующие за ним, чтобы позиции шли подряд
+func (r *ListingRepository) DeleteListingImage(listingID, imageID int, now time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := touchListing(tx, listingID, now); err != nil {
+		return err
+	}
+
+	var position int
+	err = tx.QueryRow(
+		"DELETE FROM listing_images WHERE id = $1 AND listing_id = $2 RETURNING position",
+		imageID, listingID,
+	).Scan(&position)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("image not found")
+		}
+		return fmt.Errorf("failed to delete listing image: %w", err)
+	}
+
+	shiftQuery := "UPDATE listing_images SET position = position - 1 WHERE listing_id = $1 AND position > $2"
+	if _, err := tx.Exec(shiftQuery, listingID, position); err != nil {
+		return fmt.Errorf("failed to shift listing images: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// ReorderListingImages расставляет изображения объявления в порядке imageIDs.
+// imageIDs должен перечислять каждое изображение объявления ровно один раз
+func (r *ListingRepository) ReorderListingImages(listingID int, imageIDs []int, now time.Time) ([]models.ListingImage, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := touchListing(tx, listingID, now); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query("SELECT id FROM listing_images WHERE listing_id = $1", listingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listing images: %w", err)
+	}
+	existing := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan listing image: %w", err)
+		}
+		existing[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	if len(imageIDs) != len(existing) {
+		return nil, fmt.Errorf("image_ids must list every image of the listing exactly once")
+	}
+	for _, id := range imageIDs {
+		if !existing[id] {
+			return nil, fmt.Errorf("image_ids must list every image of the listing exactly once")
+		}
+		delete(existing, id)
+	}
+
+	// уникальность позиций проверяется при фиксации транзакции, поэтому промежуточные совпадения не мешают
+	reorderQuery := `
+		UPDATE listing_images li
+		SET position = o.ordinality - 1
+		FROM unnest($2::int[]) WITH ORDINALITY AS o(id, ordinality)
+		WHERE li.id = o.id AND li.listing_id = $1
+	`
+	if _, err := tx.Exec(reorderQuery, listingID, pq.Array(imageIDs)); err != nil {
+		return nil, fmt.Errorf("failed to reorder listing images: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	images, err := r.getListingImages([]int{listingID})
+	if err != nil {
+		return nil, err
+	}
+
+	return images[listingID], nil
+}
+
+// replaceListingImages заменяет все изображения объявления; порядок в images задает позиции
+func replaceListingImages(tx *sql.Tx, listingID int, images []models.ListingImageRequest) error {
+	if err := lockImageUploads(tx, listingID, images); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM listing_images WHERE listing_id = $1", listingID); err != nil {
+		return fmt.Errorf("failed to delete listing images: %w", err)
+	}
+
+	query := `
+		INSERT INTO listing_images (listing_id, url, file_key, position, alt_text, width, height)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	for position, image := range images {
+		if _, err := tx.Exec(query, listingID, image.URL, image.FileKey, position, image.AltText, image.Width, image.Height); err != nil {
+			return fmt.Errorf("failed to save listing image: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// lockImageUploads проверяет, что файлы изображений загружены владельцем объявления, и блокирует
+// эти загрузки FOR SHARE до конца транзакции, чтобы очистка не удалила их до сохранения ссылок.
+// Если загрузки нет, возвращает ошибку "image file not found"
+func lockImageUploads(tx *sql.Tx, listingID int, images []models.ListingImageRequest) error {
+	var fileKeys []string
+	for _, image := range images {
+		if image.FileKey != nil {
+			fileKeys = append(fileKeys, *image.FileKey)
+		}
+	}
+	if len(fileKeys) == 0 {
+		return nil
+	}
+
+	unique := slices.Compact(slices.Sorted(slices.Values(fileKeys)))
+
+	// у пользователя не больше одной загрузки с данным ключом: ключ получается из хеша содержимого
+	query := `
+		SELECT blob_key
+		FROM uploads
+		WHERE user_id = (SELECT user_id FROM listings WHERE id = $1) AND blob_key = ANY($2)
+		FOR SHARE
+	`
+
+	locked, err := queryStrings(tx, query, listingID, pq.Array(unique))
+	if err != nil {
+		return fmt.Errorf("failed to lock uploads: %w", err)
+	}
+	if len(locked) != len(unique) {
+		return fmt.Errorf("image file not found")
+	}
+
+	return nil
+}
+
+// touchListing обновляет updated_at объявления и блокирует его строку до конца транзакции,
+// чтобы параллельные изменения изображений одного объявления выполнялись по очереди
+func touchListing(tx *sql.Tx, listingID int, now time.Time) error {
+	result, err := tx.Exec("UPDATE listings SET updated_at = $2 WHERE id = $1", listingID, now)
+	if err != nil {
+		return fmt.Errorf("failed to update listing: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("listing not found")
+	}
+
+	return nil
+}
+
+func scanListingImage(row rowScanner) (*models.ListingImage, error) {
+	var image models.ListingImage
+
+	err := row.Scan(
+		&image.ID,
+		&image.ListingID,
+		&image.URL,
+		&image.FileKey,
+		&image.Position,
+		&image.AltText,
+		&image.Width,
+		&image.Height,
+		&image.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &image, nil
+}
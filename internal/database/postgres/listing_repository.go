@@ -3,12 +3,41 @@ package postgres
 import (
 	"database/sql"
 	"fmt"
+	"html"
+	"slices"
 	"strings"
 	"time"
 
 	"marketplace-api/internal/models"
 )
 
+// Совпадения в сниппетах ts_headline отмечаются управляющими символами \x01 и \x02, которые после экранирования HTML
+// заменяются на <mark>: разметка, которую мог написать сам продавец, не должна смешаться с выделением
+const (
+	// listingTitleHeadlineOptions заголовок выделяется целиком
+	listingTitleHeadlineOptions = `E'StartSel=\x01, StopSel=\x02, HighlightAll=true'`
+	// listingDescriptionHeadlineOptions из описания берутся до двух фрагментов с совпадениями
+	listingDescriptionHeadlineOptions = `E'StartSel=\x01, StopSel=\x02, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" … "'`
+)
+
+var headlineReplacer = strings.NewReplacer("\x01", "<mark>", "\x02", "</mark>")
+
+// listingSortKeyTypes типы ключей сортировки, к которым приводится значение из курсора
+var listingSortKeyTypes = map[string]string{
+	"created_at":           "timestamp",
+	"price":                "numeric",
+	models.SortByRelevance: "real",
+}
+
+// listingColumns поля объявления вместе с обложкой в порядке сканирования scanListing.
+// Запрос должен соединить автора как u, а обложку — через listingCoverJoin
+const listingColumns = `
+	l.id, l.title, l.description, l.price, l.category_id, l.user_id, u.login, l.created_at, l.updated_at,
+	cover.id, cover.url, cover.file_key, cover.alt_text, cover.width, cover.height, cover.created_at`
+
+// listingCoverJoin присоединяет обложку — изображение объявления на позиции 0
+const listingCoverJoin = `LEFT JOIN listing_images cover ON cover.listing_id = l.id AND cover.position = 0`
+
 type ListingRepository struct {
 	db *sql.DB
 }
@@ -17,31 +46,41 @@ func NewListingRepository(db *sql.DB) *ListingRepository {
 	return &ListingRepository{db: db}
 }
 
-// CreateListing создает новое объявление
+// CreateListing создает новое объявление вместе с изображениями
 func (r *ListingRepository) CreateListing(userID int, req models.CreateListingRequest) (*models.Listing, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO listings (title, description, image_url, price, user_id) 
+		INSERT INTO listings (title, description, price, category_id, user_id) 
 		VALUES ($1, $2, $3, $4, $5) 
-		RETURNING id, title, description, image_url, price, user_id, created_at, updated_at
+		RETURNING id
 	`
 
-	var listing models.Listing
-	err := r.db.QueryRow(query, req.Title, req.Description, req.ImageURL, req.Price, userID).Scan(
-		&listing.ID,
-		&listing.Title,
-		&listing.Description,
-		&listing.ImageURL,
-		&listing.Price,
-		&listing.UserID,
-		&listing.CreatedAt,
-		&listing.UpdatedAt,
-	)
+	var id int
+	if err := tx.QueryRow(query, req.Title, req.Description, req.Price, req.CategoryID, userID).Scan(&id); err != nil {
+		return nil, fmt.Errorf("failed to create listing: %w", err)
+	}
+
+	if err := replaceListingImages(tx, id, req.Images); err != nil {
+		return nil, err
+	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	listing, err := r.getListingWithImages(id, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create listing: %w", err)
+		return nil, fmt.Errorf("failed to get created listing: %w", err)
 	}
 
-	return &listing, nil
+	listing.IsOwner = true
+
+	return listing, nil
 }
 
 // GetListings получает список объявлений с фильтрацией и пагинацией
@@ -51,10 +90,32 @@ func (r *ListingRepository) GetListings(filter models.ListingsFilter, currentUse
 		JOIN users u ON l.user_id = u.id
 	`
 
-	var conditions []string
+	// объявления пользователей, удаляющих аккаунт, скрыты
+	conditions := []string{"u.deletion_scheduled_at IS NULL"}
 	var args []interface{}
 	argIndex := 1
 
+	if filter.SellerID > 0 {
+		conditions = append(conditions, fmt.Sprintf("l.user_id = $%d", argIndex))
+		args = append(args, filter.SellerID)
+		argIndex++
+	}
+
+	if filter.Category != "" {
+		conditions = append(conditions, "l.category_id IN ("+fmt.Sprintf(categorySubtreeQuery, argIndex)+")")
+		args = append(args, filter.Category)
+		argIndex++
+	}
+
+	// websearch_to_tsquery понимает синтаксис поисковиков: "фраза", OR и -исключение
+	tsQuery := ""
+	if filter.Query != "" {
+		tsQuery = fmt.Sprintf("websearch_to_tsquery('russian', $%d)", argIndex)
+		conditions = append(conditions, "l.search_vector @@ "+tsQuery)
+		args = append(args, filter.Query)
+		argIndex++
+	}
+
 	if filter.MinPrice != nil {
 		conditions = append(conditions, fmt.Sprintf("l.price >= $%d", argIndex))
 		args = append(args, *filter.MinPrice)
@@ -67,29 +128,89 @@ func (r *ListingRepository) GetListings(filter models.ListingsFilter, currentUse
 		argIndex++
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	if filter.CreatedAfter != nil {
+		conditions = append(conditions, fmt.Sprintf("l.created_at >= $%d", argIndex))
+		args = append(args, *filter.CreatedAfter)
 	}
 
-	countQuery := "SELECT COUNT(*) " + baseQuery + " " + whereClause
-	var total int
-	err := r.db.QueryRow(countQuery, args...).Scan(&total)
+	result, err := r.getListingsPage(baseQuery, conditions, args, tsQuery, filter, currentUserID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to count listings: %w", err)
+		return nil, err
+	}
+
+	// фасеты считаются по той же выборке, что и страница, но без курсора и LIMIT/OFFSET
+	if facetNames := filter.FacetNames(); len(facetNames) > 0 {
+		result.Facets = &models.ListingFacets{}
+		if result.Total == nil || *result.Total > 0 {
+			whereClause := "WHERE " + strings.Join(conditions, " AND ")
+			if err := r.fillListingFacets(result.Facets, facetNames, baseQuery, whereClause, args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// getListingsPage выбирает страницу объявлений, подходящих под conditions: по номеру страницы
+// или после (перед) позицией filter.Position. Курсоры следующей и предыдущей страниц возвращаются
+// позициями NextPosition и PrevPosition
+func (r *ListingRepository) getListingsPage(baseQuery string, conditions []string, args []interface{}, tsQuery string, filter models.ListingsFilter, currentUserID *int) (*models.PaginatedListings, error) {
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total *int
+	if filter.CountTotal() {
+		countQuery := "SELECT COUNT(*) " + baseQuery + " " + whereClause
+		var count int
+		if err := r.db.QueryRow(countQuery, args...).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count listings: %w", err)
+		}
+		total = &count
+	}
+
+	// ключ сортировки вместе с l.id однозначно задает порядок, поэтому страницу можно начать с любой позиции
+	sortKey, sortKeyType := "l."+filter.SortBy, listingSortKeyTypes[filter.SortBy]
+	if filter.SortBy == models.SortByRelevance {
+		sortKey = fmt.Sprintf("ts_rank(l.search_vector, %s)", tsQuery)
+	}
+
+	// prev_cursor читает ленту в обратном порядке, а найденные объявления потом переворачиваются
+	backward := filter.Position != nil && filter.Position.Before
+	sortDir := filter.SortDir
+	if backward {
+		sortDir = reverseSortDir(sortDir)
+	}
+
+	if filter.Position != nil {
+		comparison := "<"
+		if sortDir == "asc" {
+			comparison = ">"
+		}
+		// условие добавляется к копиям, чтобы не задеть выборку фасетов
+		conditions = append(conditions[:len(conditions):len(conditions)], fmt.Sprintf(
+			"(%s, l.id) %s ($%d::%s, $%d)", sortKey, comparison, len(args)+1, sortKeyType, len(args)+2,
+		))
+		args = append(args[:len(args):len(args)], filter.Position.Value, filter.Position.ID)
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
 
 	selectFields := `
-		SELECT l.id, l.title, l.description, l.image_url, l.price, 
-		       l.user_id, u.login as user_login, l.created_at, l.updated_at
-	`
+		SELECT ` + listingColumns + `,
+		       ` + sortKey + `::text`
+	if tsQuery != "" {
+		selectFields += fmt.Sprintf(`,
+		       ts_headline('russian', l.title, %[1]s, %[2]s),
+		       ts_headline('russian', l.description, %[1]s, %[3]s)
+		`, tsQuery, listingTitleHeadlineOptions, listingDescriptionHeadlineOptions)
+	}
 
-	orderBy := fmt.Sprintf("ORDER BY l.%s %s", filter.SortBy, filter.SortDir)
+	orderBy := fmt.Sprintf("ORDER BY %[1]s %[2]s, l.id %[2]s", sortKey, sortDir)
 
-	limitOffset := fmt.Sprintf("LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
-	args = append(args, filter.Limit, filter.GetOffset())
+	// лишняя строка показывает, есть ли объявления за пределами страницы
+	limitOffset := fmt.Sprintf("LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit+1, filter.GetOffset())
 
-	finalQuery := selectFields + " " + baseQuery + " " + whereClause + " " + orderBy + " " + limitOffset
+	finalQuery := selectFields + " " + baseQuery + " " + listingCoverJoin + " " + whereClause + " " + orderBy + " " + limitOffset
 
 	rows, err := r.db.Query(finalQuery, args...)
 	if err != nil {
@@ -98,68 +219,119 @@ func (r *ListingRepository) GetListings(filter models.ListingsFilter, currentUse
 	defer rows.Close()
 
 	var listings []models.Listing
+	var sortValues []string
 	for rows.Next() {
-		var listing models.Listing
-		err := rows.Scan(
-			&listing.ID,
-			&listing.Title,
-			&listing.Description,
-			&listing.ImageURL,
-			&listing.Price,
-			&listing.UserID,
-			&listing.UserLogin,
-			&listing.CreatedAt,
-			&listing.UpdatedAt,
-		)
+		var sortValue, titleHeadline, descriptionHeadline string
+		extra := []interface{}{&sortValue}
+		if tsQuery != "" {
+			extra = append(extra, &titleHeadline, &descriptionHeadline)
+		}
+
+		listing, err := scanListing(rows, extra...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan listing: %w", err)
 		}
 
+		if tsQuery != "" {
+			listing.Highlight = &models.ListingHighlight{
+				Title:       highlightHeadline(titleHeadline),
+				Description: highlightHeadline(descriptionHeadline),
+			}
+		}
+
 		if currentUserID != nil && *currentUserID == listing.UserID {
 			listing.IsOwner = true
 		}
 
-		listings = append(listings, listing)
+		listings = append(listings, *listing)
+		sortValues = append(sortValues, sortValue)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("rows iteration error: %w", err)
 	}
 
-	totalPages := (total + filter.Limit - 1) / filter.Limit
+	hasMore := len(listings) > filter.Limit
+	if hasMore {
+		listings = listings[:filter.Limit]
+		sortValues = sortValues[:filter.Limit]
+	}
+	if backward {
+		slices.Reverse(listings)
+		slices.Reverse(sortValues)
+	}
 
-	return &models.PaginatedListings{
-		Data:       listings,
-		Total:      total,
-		Page:       filter.Page,
-		Limit:      filter.Limit,
-		TotalPages: totalPages,
-	}, nil
+	result := &models.PaginatedListings{
+		Data:  listings,
+		Total: total,
+		Limit: filter.Limit,
+	}
+	if filter.Position == nil {
+		result.Page = filter.Page
+	}
+	if total != nil {
+		totalPages := (*total + filter.Limit - 1) / filter.Limit
+		result.TotalPages = &totalPages
+	}
+
+	if len(listings) == 0 {
+		return result, nil
+	}
+
+	position := func(i int, before bool) *models.ListingCursor {
+		return &models.ListingCursor{
+			SortBy:  filter.SortBy,
+			SortDir: filter.SortDir,
+			Value:   sortValues[i],
+			ID:      listings[i].ID,
+			Before:  before,
+		}
+	}
+
+	// при движении назад лишняя строка означает, что есть еще более ранние страницы,
+	// а следующая страница существует всегда: с нее пришел курсор
+	hasNext, hasPrev := hasMore, filter.Position != nil || filter.GetOffset() > 0
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+	if hasNext {
+		result.NextPosition = position(len(listings)-1, false)
+	}
+	if hasPrev {
+		result.PrevPosition = position(0, true)
+	}
+
+	return result, nil
 }
 
-// GetListingByID получает объявление по ID
+// GetListingByID получает объявление по ID вместе с изображениями
 func (r *ListingRepository) GetListingByID(id int, currentUserID *int) (*models.Listing, error) {
+	listing, err := r.getListingWithImages(id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if currentUserID != nil && *currentUserID == listing.UserID {
+		listing.IsOwner = true
+	}
+
+	return listing, nil
+}
+
+// getListingWithImages получает объявление с изображениями. activeOnly скрывает объявления пользователей, удаляющих аккаунт
+func (r *ListingRepository) getListingWithImages(id int, activeOnly bool) (*models.Listing, error) {
 	query := `
-		SELECT l.id, l.title, l.description, l.image_url, l.price, 
-		       l.user_id, u.login as user_login, l.created_at, l.updated_at
+		SELECT ` + listingColumns + `
 		FROM listings l 
 		JOIN users u ON l.user_id = u.id
+		` + listingCoverJoin + `
 		WHERE l.id = $1
 	`
+	if activeOnly {
+		query += " AND u.deletion_scheduled_at IS NULL"
+	}
 
-	var listing models.Listing
-	err := r.db.QueryRow(query, id).Scan(
-		&listing.ID,
-		&listing.Title,
-		&listing.Description,
-		&listing.ImageURL,
-		&listing.Price,
-		&listing.UserID,
-		&listing.UserLogin,
-		&listing.CreatedAt,
-		&listing.UpdatedAt,
-	)
-
+	listing, err := scanListing(r.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("listing not found")
@@ -167,29 +339,32 @@ func (r *ListingRepository) GetListingByID(id int, currentUserID *int) (*models.
 		return nil, fmt.Errorf("failed to get listing: %w", err)
 	}
 
-	if currentUserID != nil && *currentUserID == listing.UserID {
-		listing.IsOwner = true
+	images, err := r.getListingImages([]int{id})
+	if err != nil {
+		return nil, err
 	}
+	listing.Images = images[id]
 
-	return &listing, nil
+	return listing, nil
 }
 
-// UpdateListing обновляет объявление
-func (r *ListingRepository) UpdateListing(id, userID int, req models.UpdateListingRequest) (*models.Listing, error) {
+// GetListingOwnerID получает ID владельца объявления
+func (r *ListingRepository) GetListingOwnerID(id int) (int, error) {
 	checkQuery := "SELECT user_id FROM listings WHERE id = $1"
 	var ownerID int
 	err := r.db.QueryRow(checkQuery, id).Scan(&ownerID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("listing not found")
+			return 0, fmt.Errorf("listing not found")
 		}
-		return nil, fmt.Errorf("failed to check listing ownership: %w", err)
+		return 0, fmt.Errorf("failed to check listing ownership: %w", err)
 	}
 
-	if ownerID != userID {
-		return nil, fmt.Errorf("access denied: not owner")
-	}
+	return ownerID, nil
+}
 
+// UpdateListing обновляет объявление. Images, если задан, заменяет все изображения. Права на изменение проверяет сервис
+func (r *ListingRepository) UpdateListing(id int, req models.UpdateListingRequest) (*models.Listing, error) {
 	var setParts []string
 	var args []interface{}
 	argIndex := 1
@@ -206,19 +381,19 @@ func (r *ListingRepository) UpdateListing(id, userID int, req models.UpdateListi
 		argIndex++
 	}
 
-	if req.ImageURL != nil {
-		setParts = append(setParts, fmt.Sprintf("image_url = $%d", argIndex))
-		args = append(args, *req.ImageURL)
-		argIndex++
-	}
-
 	if req.Price != nil {
 		setParts = append(setParts, fmt.Sprintf("price = $%d", argIndex))
 		args = append(args, *req.Price)
 		argIndex++
 	}
 
-	if len(setParts) == 0 {
+	if req.CategoryID != nil {
+		setParts = append(setParts, fmt.Sprintf("category_id = $%d", argIndex))
+		args = append(args, *req.CategoryID)
+		argIndex++
+	}
+
+	if len(setParts) == 0 && req.Images == nil {
 		return nil, fmt.Errorf("no fields to update")
 	}
 
@@ -232,46 +407,43 @@ func (r *ListingRepository) UpdateListing(id, userID int, req models.UpdateListi
 		UPDATE listings 
 		SET %s
 		WHERE id = $%d
-		RETURNING id, title, description, image_url, price, user_id, created_at, updated_at
 	`, strings.Join(setParts, ", "), argIndex)
 
-	var listing models.Listing
-	err = r.db.QueryRow(query, args...).Scan(
-		&listing.ID,
-		&listing.Title,
-		&listing.Description,
-		&listing.ImageURL,
-		&listing.Price,
-		&listing.UserID,
-		&listing.CreatedAt,
-		&listing.UpdatedAt,
-	)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
+	result, err := tx.Exec(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update listing: %w", err)
 	}
 
-	listing.IsOwner = true
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
 
-	return &listing, nil
-}
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("listing not found")
+	}
 
-// DeleteListing удаляет объявление
-func (r *ListingRepository) DeleteListing(id, userID int) error {
-	checkQuery := "SELECT user_id FROM listings WHERE id = $1"
-	var ownerID int
-	err := r.db.QueryRow(checkQuery, id).Scan(&ownerID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return fmt.Errorf("listing not found")
+	if req.Images != nil {
+		if err := replaceListingImages(tx, id, *req.Images); err != nil {
+			return nil, err
 		}
-		return fmt.Errorf("failed to check listing ownership: %w", err)
 	}
 
-	if ownerID != userID {
-		return fmt.Errorf("access denied: not owner")
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	return r.getListingWithImages(id, false)
+}
+
+// DeleteListing удаляет объявление. Права на удаление проверяет сервис
+func (r *ListingRepository) DeleteListing(id int) error {
 	deleteQuery := "DELETE FROM listings WHERE id = $1"
 	result, err := r.db.Exec(deleteQuery, id)
 	if err != nil {
@@ -292,63 +464,112 @@ func (r *ListingRepository) DeleteListing(id, userID int) error {
 
 // GetUserListings получает объявления конкретного пользователя
 func (r *ListingRepository) GetUserListings(userID int, filter models.ListingsFilter) (*models.PaginatedListings, error) {
-	countQuery := "SELECT COUNT(*) FROM listings WHERE user_id = $1"
-	var total int
-	err := r.db.QueryRow(countQuery, userID).Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count user listings: %w", err)
-	}
-
-	query := fmt.Sprintf(`
-		SELECT l.id, l.title, l.description, l.image_url, l.price, 
-		       l.user_id, u.login as user_login, l.created_at, l.updated_at
+	baseQuery := `
 		FROM listings l 
 		JOIN users u ON l.user_id = u.id
+	`
+
+	return r.getListingsPage(baseQuery, []string{"l.user_id = $1"}, []interface{}{userID}, "", filter, &userID)
+}
+
+// GetAllUserListings получает все объявления пользователя с изображениями без пагинации. Используется для выгрузки данных
+func (r *ListingRepository) GetAllUserListings(userID int) ([]models.Listing, error) {
+	query := `
+		SELECT ` + listingColumns + `
+		FROM listings l
+		JOIN users u ON l.user_id = u.id
+		` + listingCoverJoin + `
 		WHERE l.user_id = $1
-		ORDER BY l.%s %s
-		LIMIT $2 OFFSET $3
-	`, filter.SortBy, filter.SortDir)
+		ORDER BY l.id
+	`
 
-	rows, err := r.db.Query(query, userID, filter.Limit, filter.GetOffset())
+	rows, err := r.db.Query(query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user listings: %w", err)
 	}
 	defer rows.Close()
 
-	var listings []models.Listing
+	listings := []models.Listing{}
+	var ids []int
 	for rows.Next() {
-		var listing models.Listing
-		err := rows.Scan(
-			&listing.ID,
-			&listing.Title,
-			&listing.Description,
-			&listing.ImageURL,
-			&listing.Price,
-			&listing.UserID,
-			&listing.UserLogin,
-			&listing.CreatedAt,
-			&listing.UpdatedAt,
-		)
+		listing, err := scanListing(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user listing: %w", err)
 		}
 
 		listing.IsOwner = true
 
-		listings = append(listings, listing)
+		listings = append(listings, *listing)
+		ids = append(ids, listing.ID)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("rows iteration error: %w", err)
 	}
 
-	totalPages := (total + filter.Limit - 1) / filter.Limit
+	images, err := r.getListingImages(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range listings {
+		listings[i].Images = images[listings[i].ID]
+	}
+
+	return listings, nil
+}
+
+// scanListing сканирует поля listingColumns и следующие за ними колонки extra
+func scanListing(row rowScanner, extra ...interface{}) (*models.Listing, error) {
+	var listing models.Listing
+	var cover models.ListingImage
+	var coverID sql.NullInt64
+	var coverAltText sql.NullString
+	var coverCreatedAt sql.NullTime
 
-	return &models.PaginatedListings{
-		Data:       listings,
-		Total:      total,
-		Page:       filter.Page,
-		Limit:      filter.Limit,
-		TotalPages: totalPages,
-	}, nil
+	dest := append([]interface{}{
+		&listing.ID,
+		&listing.Title,
+		&listing.Description,
+		&listing.Price,
+		&listing.CategoryID,
+		&listing.UserID,
+		&listing.UserLogin,
+		&listing.CreatedAt,
+		&listing.UpdatedAt,
+		&coverID,
+		&cover.URL,
+		&cover.FileKey,
+		&coverAltText,
+		&cover.Width,
+		&cover.Height,
+		&coverCreatedAt,
+	}, extra...)
+
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	if coverID.Valid {
+		cover.ID = int(coverID.Int64)
+		cover.ListingID = listing.ID
+		cover.AltText = coverAltText.String
+		cover.CreatedAt = coverCreatedAt.Time
+		listing.Cover = &cover
+		listing.ImageURL = cover.URL
+	}
+
+	return &listing, nil
+}
+
+// highlightHeadline экранирует HTML в сниппете ts_headline и отмечает совпадения тегом <mark>
+func highlightHeadline(headline string) string {
+	return headlineReplacer.Replace(html.EscapeString(headline))
+}
+
+// reverseSortDir возвращает противоположное направление сортировки
+func reverseSortDir(sortDir string) string {
+	if sortDir == "asc" {
+		return "desc"
+	}
+	return "asc"
 }
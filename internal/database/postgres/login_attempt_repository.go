@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"marketplace-api/internal/models"
+)
+
+type LoginAttemptRepository struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptRepository(db *sql.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// GetLoginAttempt получает счетчик неудачных попыток входа
+func (r *LoginAttemptRepository) GetLoginAttempt(scope, key string) (*models.LoginAttempt, error) {
+	query := `
+		SELECT scope, key, failures, last_failure_at, locked_until
+		FROM login_attempts
+		WHERE scope = $1 AND key = $2
+	`
+
+	var attempt models.LoginAttempt
+	err := r.db.QueryRow(query, scope, key).Scan(
+		&attempt.Scope,
+		&attempt.Key,
+		&attempt.Failures,
+		&attempt.LastFailureAt,
+		&attempt.LockedUntil,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("login attempt not found")
+		}
+		return nil, fmt.Errorf("failed to get login attempt: %w", err)
+	}
+
+	return &attempt, nil
+}
+
+// RecordLoginFailure атомарно увеличивает счетчик неудач. Если последняя неудача была раньше windowStart,
+// счет начинается заново
+func (r *LoginAttemptRepository) RecordLoginFailure(scope, key string, now, windowStart time.Time) (*models.LoginAttempt, error) {
+	query := `
+		INSERT INTO login_attempts (scope, key, failures, last_failure_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (scope, key) DO UPDATE
+		SET failures = CASE
+				WHEN login_attempts.last_failure_at < $4 THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			last_failure_at = $3
+		RETURNING scope, key, failures, last_failure_at, locked_until
+	`
+
+	var attempt models.LoginAttempt
+	err := r.db.QueryRow(query, scope, key, now, windowStart).Scan(
+		&attempt.Scope,
+		&attempt.Key,
+		&attempt.Failures,
+		&attempt.LastFailureAt,
+		&attempt.LockedUntil,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	return &attempt, nil
+}
+
+// LockLoginAttempt блокирует вход до until
+func (r *LoginAttemptRepository) LockLoginAttempt(scope, key string, until time.Time) error {
+	query := `UPDATE login_attempts SET locked_until = $3 WHERE scope = $1 AND key = $2`
+
+	if _, err := r.db.Exec(query, scope, key, until); err != nil {
+		return fmt.Errorf("failed to lock login: %w", err)
+	}
+
+	return nil
+}
+
+// ResetLoginAttempt сбрасывает счетчик неудач и снимает блокировку
+func (r *LoginAttemptRepository) ResetLoginAttempt(scope, key string) error {
+	query := `DELETE FROM login_attempts WHERE scope = $1 AND key = $2`
+
+	if _, err := r.db.Exec(query, scope, key); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStaleLoginAttempts удаляет счетчики без неудач после before и без действующей блокировки
+func (r *LoginAttemptRepository) DeleteStaleLoginAttempts(before, now time.Time) error {
+	query := `
+		DELETE FROM login_attempts
+		WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $2)
+	`
+
+	if _, err := r.db.Exec(query, before, now); err != nil {
+		return fmt.Errorf("failed to delete stale login attempts: %w", err)
+	}
+
+	return nil
+}
+
+// RememberLoginIP отмечает адрес, с которого выполнен успешный вход под логином
+func (r *LoginAttemptRepository) RememberLoginIP(login, clientIP string, now time.Time) error {
+	query := `
+		INSERT INTO login_known_ips (login, ip_address, last_success_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (login, ip_address) DO UPDATE SET last_success_at = $3
+	`
+
+	if _, err := r.db.Exec(query, login, clientIP, now); err != nil {
+		return fmt.Errorf("failed to remember login address: %w", err)
+	}
+
+	return nil
+}
+
+// IsKnownLoginIP проверяет, был ли с адреса успешный вход под логином не раньше since
+func (r *LoginAttemptRepository) IsKnownLoginIP(login, clientIP string, since time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM login_known_ips
+			WHERE login = $1 AND ip_address = $2 AND last_success_at >= $3
+		)
+	`
+
+	var known bool
+	if err := r.db.QueryRow(query, login, clientIP, since).Scan(&known); err != nil {
+		return false, fmt.Errorf("failed to check login address: %w", err)
+	}
+
+	return known, nil
+}
+
+// DeleteStaleLoginIPs удаляет адреса без успешных входов после before
+func (r *LoginAttemptRepository) DeleteStaleLoginIPs(before time.Time) error {
+	if _, err := r.db.Exec("DELETE FROM login_known_ips WHERE last_success_at < $1", before); err != nil {
+		return fmt.Errorf("failed to delete stale login addresses: %w", err)
+	}
+
+	return nil
+}
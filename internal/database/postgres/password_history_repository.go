@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type PasswordHistoryRepository struct {
+	db *sql.DB
+}
+
+func NewPasswordHistoryRepository(db *sql.DB) *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{db: db}
+}
+
+// ListRecent возвращает хеши последних limit прежних паролей пользователя, начиная с самого нового
+func (r *PasswordHistoryRepository) ListRecent(userID, limit int) ([]string, error) {
+	query := `
+		SELECT password_hash
+		FROM password_history
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get password history: %w", err)
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("failed to scan password hash: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return hashes, nil
+}
+
+// ChangePassword заменяет хеш пароля, перенося прежний хеш в историю.
+// В истории остаются только keep последних хешей; при keep = 0 история не ведется
+func (r *PasswordHistoryRepository) ChangePassword(userID int, passwordHash string, keep int, now time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previousHash string
+	err = tx.QueryRow("SELECT password_hash FROM users WHERE id = $1 FOR UPDATE", userID).Scan(&previousHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("user not found")
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if _, err := tx.Exec("UPDATE users SET password_hash = $1, updated_at = $2 WHERE id = $3", passwordHash, now, userID); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	// у пользователей, созданных через внешнего провайдера, прежнего пароля нет
+	if keep > 0 && previousHash != "" {
+		insertQuery := "INSERT INTO password_history (user_id, password_hash, created_at) VALUES ($1, $2, $3)"
+		if _, err := tx.Exec(insertQuery, userID, previousHash, now); err != nil {
+			return fmt.Errorf("failed to save password history: %w", err)
+		}
+	}
+
+	trimQuery := `
+		DELETE FROM password_history
+		WHERE user_id = $1 AND id NOT IN (
+			SELECT id
+			FROM password_history
+			WHERE user_id = $1
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2
+		)
+	`
+	if _, err := tx.Exec(trimQuery, userID, keep); err != nil {
+		return fmt.Errorf("failed to trim password history: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
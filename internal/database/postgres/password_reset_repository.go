@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"marketplace-api/internal/models"
+)
+
+type PasswordResetRepository struct {
+	db *sql.DB
+}
+
+func NewPasswordResetRepository(db *sql.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+// CreateResetToken сохраняет хеш нового токена сброса пароля.
+// Ранее выданные и еще не использованные токены пользователя аннулируются
+func (r *PasswordResetRepository) CreateResetToken(userID int, tokenHash string, expiresAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	invalidateQuery := `
+		UPDATE password_reset_tokens
+		SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND used_at IS NULL
+	`
+	if _, err := tx.Exec(invalidateQuery, userID); err != nil {
+		return fmt.Errorf("failed to invalidate reset tokens: %w", err)
+	}
+
+	insertQuery := `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+	`
+	if _, err := tx.Exec(insertQuery, userID, tokenHash, expiresAt); err != nil {
+		return fmt.Errorf("failed to create reset token: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetLastSentAge возвращает, сколько времени прошло с выдачи последнего токена сброса пользователю,
+// или nil, если токенов не было. Время считается по часам базы данных
+func (r *PasswordResetRepository) GetLastSentAge(userID int) (*time.Duration, error) {
+	query := `
+		SELECT EXTRACT(EPOCH FROM (LOCALTIMESTAMP - MAX(created_at)))
+		FROM password_reset_tokens
+		WHERE user_id = $1
+	`
+
+	var seconds sql.NullFloat64
+	if err := r.db.QueryRow(query, userID).Scan(&seconds); err != nil {
+		return nil, fmt.Errorf("failed to get last reset time: %w", err)
+	}
+
+	if !seconds.Valid {
+		return nil, nil
+	}
+
+	age := time.Duration(seconds.Float64 * float64(time.Second))
+	return &age, nil
+}
+
+// GetResetToken возвращает действующий токен сброса, не помечая его использованным.
+// Использованный, истекший или неизвестный токен дает ошибку "reset token not found"
+func (r *PasswordResetRepository) GetResetToken(tokenHash string, now time.Time) (*models.PasswordResetToken, error) {
+	query := `
+		SELECT id, user_id, expires_at, used_at, created_at
+		FROM password_reset_tokens
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+	`
+
+	token, err := scanResetToken(r.db.QueryRow(query, tokenHash, now))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("reset token not found")
+		}
+		return nil, fmt.Errorf("failed to get reset token: %w", err)
+	}
+
+	return token, nil
+}
+
+// ConsumeResetToken атомарно помечает действующий токен использованным и возвращает его.
+// Использованный, истекший или неизвестный токен дает ошибку "reset token not found"
+func (r *PasswordResetRepository) ConsumeResetToken(tokenHash string, now time.Time) (*models.PasswordResetToken, error) {
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = CURRENT_TIMESTAMP
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING id, user_id, expires_at, used_at, created_at
+	`
+
+	token, err := scanResetToken(r.db.QueryRow(query, tokenHash, now))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("reset token not found")
+		}
+		return nil, fmt.Errorf("failed to consume reset token: %w", err)
+	}
+
+	return token, nil
+}
+
+func scanResetToken(row rowScanner) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
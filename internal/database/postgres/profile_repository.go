@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"marketplace-api/internal/models"
+)
+
+// profileColumns поля профиля в порядке сканирования scanProfile
+const profileColumns = `display_name, bio, avatar_url, location, phone, show_email, show_phone, preferred_contact, updated_at`
+
+type ProfileRepository struct {
+	db *sql.DB
+}
+
+func NewProfileRepository(db *sql.DB) *ProfileRepository {
+	return &ProfileRepository{db: db}
+}
+
+// GetProfile получает профиль пользователя. Если профиль еще не заполнялся, возвращается пустой
+func (r *ProfileRepository) GetProfile(userID int) (*models.UserProfile, error) {
+	query := `
+		SELECT ` + profileColumns + `
+		FROM user_profiles
+		WHERE user_id = $1
+	`
+
+	profile, err := scanProfile(r.db.QueryRow(query, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &models.UserProfile{}, nil
+		}
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// SaveProfile создает или полностью перезаписывает профиль пользователя
+func (r *ProfileRepository) SaveProfile(userID int, profile models.UserProfile, now time.Time) (*models.UserProfile, error) {
+	query := `
+		INSERT INTO user_profiles (user_id, display_name, bio, avatar_url, location, phone,
+			show_email, show_phone, preferred_contact, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (user_id) DO UPDATE
+		SET display_name = EXCLUDED.display_name,
+		    bio = EXCLUDED.bio,
+		    avatar_url = EXCLUDED.avatar_url,
+		    location = EXCLUDED.location,
+		    phone = EXCLUDED.phone,
+		    show_email = EXCLUDED.show_email,
+		    show_phone = EXCLUDED.show_phone,
+		    preferred_contact = EXCLUDED.preferred_contact,
+		    updated_at = EXCLUDED.updated_at
+		RETURNING ` + profileColumns
+
+	saved, err := scanProfile(r.db.QueryRow(query,
+		userID,
+		profile.DisplayName,
+		profile.Bio,
+		profile.AvatarURL,
+		profile.Location,
+		profile.Phone,
+		profile.ShowEmail,
+		profile.ShowPhone,
+		profile.PreferredContact,
+		now,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save profile: %w", err)
+	}
+
+	return saved, nil
+}
+
+// GetPublicProfile получает публичный профиль по логину.
+// Email показывается, только если он подтвержден и пользователь разрешил его показ, телефон — только с разрешения.
+// Пользователи, удаляющие аккаунт, не находятся
+func (r *ProfileRepository) GetPublicProfile(login string) (*models.PublicProfile, error) {
+	query := `
+		SELECT u.id, u.login,
+		       COALESCE(p.display_name, ''), COALESCE(p.bio, ''), p.avatar_url, COALESCE(p.location, ''),
+		       CASE WHEN p.show_email AND u.email_verified_at IS NOT NULL THEN COALESCE(u.email, '') ELSE '' END,
+		       CASE WHEN p.show_phone THEN p.phone ELSE '' END,
+		       COALESCE(p.preferred_contact, ''),
+		       (SELECT COUNT(*) FROM listings l WHERE l.user_id = u.id),
+		       u.created_at
+		FROM users u
+		LEFT JOIN user_profiles p ON p.user_id = u.id
+		WHERE u.login = $1 AND u.deletion_scheduled_at IS NULL
+	`
+
+	var profile models.PublicProfile
+	err := r.db.QueryRow(query, login).Scan(
+		&profile.ID,
+		&profile.Login,
+		&profile.DisplayName,
+		&profile.Bio,
+		&profile.AvatarURL,
+		&profile.Location,
+		&profile.Email,
+		&profile.Phone,
+		&profile.PreferredContact,
+		&profile.ListingsCount,
+		&profile.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get public profile: %w", err)
+	}
+
+	return &profile, nil
+}
+
+func scanProfile(row rowScanner) (*models.UserProfile, error) {
+	var profile models.UserProfile
+	err := row.Scan(
+		&profile.DisplayName,
+		&profile.Bio,
+		&profile.AvatarURL,
+		&profile.Location,
+		&profile.Phone,
+		&profile.ShowEmail,
+		&profile.ShowPhone,
+		&profile.PreferredContact,
+		&profile.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
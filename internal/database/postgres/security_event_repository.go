@@ -0,0 +1,237 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"marketplace-api/internal/models"
+)
+
+type SecurityEventRepository struct {
+	db *sql.DB
+}
+
+func NewSecurityEventRepository(db *sql.DB) *SecurityEventRepository {
+	return &SecurityEventRepository{db: db}
+}
+
+const securityEventColumns = `
+	id, event_type, user_id, actor_id, login, ip_address, user_agent, request_id, details, created_at
+`
+
+// CreateEvent добавляет запись в журнал безопасности
+func (r *SecurityEventRepository) CreateEvent(event *models.SecurityEvent) error {
+	details := []byte("{}")
+	if len(event.Details) > 0 {
+		var err error
+		details, err = json.Marshal(event.Details)
+		if err != nil {
+			return fmt.Errorf("failed to encode event details: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO security_events (event_type, user_id, actor_id, login, ip_address, user_agent, request_id, details, created_at)
+		VALUES ($1, NULLIF($2, 0), NULLIF($3, 0), $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(
+		query,
+		event.Type,
+		event.UserID,
+		event.ActorID,
+		event.Login,
+		event.IPAddress,
+		event.UserAgent,
+		event.RequestID,
+		details,
+		event.CreatedAt,
+	).Scan(&event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create security event: %w", err)
+	}
+
+	return nil
+}
+
+// ListEvents получает постраничный журнал безопасности, начиная с последних событий
+func (r *SecurityEventRepository) ListEvents(filter models.AdminSecurityEventsFilter) (*models.PaginatedSecurityEvents, error) {
+	whereClause := "WHERE 1=1"
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		whereClause += fmt.Sprintf(" AND "+condition, len(args))
+	}
+
+	if filter.UserID > 0 {
+		addCondition("user_id = $%d", filter.UserID)
+	}
+	if filter.ActorID > 0 {
+		addCondition("actor_id = $%d", filter.ActorID)
+	}
+	if filter.Login != "" {
+		addCondition("(login = $%[1]d OR user_id = (SELECT id FROM users WHERE login = $%[1]d))", filter.Login)
+	}
+	if filter.IPAddress != "" {
+		addCondition("ip_address = $%d", filter.IPAddress)
+	}
+	if filter.RequestID != "" {
+		addCondition("request_id = $%d", filter.RequestID)
+	}
+	if filter.Type != "" {
+		addCondition("event_type = $%d", filter.Type)
+	}
+	if filter.From != nil {
+		addCondition("created_at >= $%d", filter.From.UTC())
+	}
+	if filter.To != nil {
+		addCondition("created_at < $%d", filter.To.UTC())
+	}
+
+	var total int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM security_events "+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count security events: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM security_events
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, securityEventColumns, whereClause, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.GetOffset())
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get security events: %w", err)
+	}
+	defer rows.Close()
+
+	events, err := scanSecurityEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PaginatedSecurityEvents{
+		Data:       events,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: (total + filter.Limit - 1) / filter.Limit,
+	}, nil
+}
+
+// GetAllUserEvents получает все события аккаунта пользователя для выгрузки персональных данных
+func (r *SecurityEventRepository) GetAllUserEvents(userID int) ([]models.SecurityEvent, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM security_events
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+	`, securityEventColumns)
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get security events: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSecurityEvents(rows)
+}
+
+// AnonymizeEventsBefore стирает логин, IP-адрес и user agent в записях, созданных раньше before.
+// Возвращает число измененных записей
+func (r *SecurityEventRepository) AnonymizeEventsBefore(before time.Time) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	count, err := anonymizeSecurityEvents(tx, "created_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return count, nil
+}
+
+// anonymizeSecurityEvents стирает логин, IP-адрес и user agent в записях журнала безопасности,
+// отобранных условием condition. Триггер журнала разрешает такое изменение только при включенном в транзакции флаге
+func anonymizeSecurityEvents(tx *sql.Tx, condition string, args ...interface{}) (int64, error) {
+	if _, err := tx.Exec("SELECT set_config('marketplace.anonymize_security_events', 'on', true)"); err != nil {
+		return 0, fmt.Errorf("failed to allow security events anonymization: %w", err)
+	}
+
+	query := `
+		UPDATE security_events
+		SET login = '', ip_address = '', user_agent = ''
+		WHERE ` + condition + ` AND (login <> '' OR ip_address <> '' OR user_agent <> '')
+	`
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to anonymize security events: %w", err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if _, err := tx.Exec("SELECT set_config('marketplace.anonymize_security_events', 'off', true)"); err != nil {
+		return 0, fmt.Errorf("failed to reset security events anonymization: %w", err)
+	}
+
+	return count, nil
+}
+
+func scanSecurityEvents(rows *sql.Rows) ([]models.SecurityEvent, error) {
+	events := []models.SecurityEvent{}
+	for rows.Next() {
+		var event models.SecurityEvent
+		var userID, actorID sql.NullInt64
+		var details []byte
+
+		err := rows.Scan(
+			&event.ID,
+			&event.Type,
+			&userID,
+			&actorID,
+			&event.Login,
+			&event.IPAddress,
+			&event.UserAgent,
+			&event.RequestID,
+			&details,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan security event: %w", err)
+		}
+
+		event.UserID = int(userID.Int64)
+		event.ActorID = int(actorID.Int64)
+		if err := json.Unmarshal(details, &event.Details); err != nil {
+			return nil, fmt.Errorf("failed to decode event details: %w", err)
+		}
+		if len(event.Details) == 0 {
+			event.Details = nil
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"marketplace-api/internal/models"
+)
+
+// sessionColumns поля сеанса в порядке сканирования scanSession
+const sessionColumns = `id, user_id, family_id, user_agent, ip_address, expires_at, last_seen_at, revoked_at, created_at`
+
+type SessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// CreateSession сохраняет новый сеанс и удаляет завершенные сеансы пользователя.
+// Токены удаленного сеанса не принимаются так же, как токены отозванного
+func (r *SessionRepository) CreateSession(userID int, familyID string, client models.ClientInfo, expiresAt, now time.Time) (*models.Session, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		"DELETE FROM sessions WHERE user_id = $1 AND (revoked_at IS NOT NULL OR expires_at < $2)",
+		userID, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete finished sessions: %w", err)
+	}
+
+	query := `
+		INSERT INTO sessions (user_id, family_id, user_agent, ip_address, expires_at, last_seen_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		RETURNING ` + sessionColumns
+
+	session, err := scanSession(tx.QueryRow(query, userID, familyID, client.UserAgent, client.IP, expiresAt, now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return session, nil
+}
+
+// GetSessionByFamily получает сеанс по семейству refresh-токенов
+func (r *SessionRepository) GetSessionByFamily(familyID string) (*models.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE family_id = $1
+	`
+
+	session, err := scanSession(r.db.QueryRow(query, familyID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("session not found")
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return session, nil
+}
+
+// TouchSession отмечает активность сеанса: время, адрес и user agent клиента и новый срок действия
+func (r *SessionRepository) TouchSession(id int, client models.ClientInfo, expiresAt, now time.Time) error {
+	query := `
+		UPDATE sessions
+		SET user_agent = $1, ip_address = $2, expires_at = $3, last_seen_at = $4
+		WHERE id = $5
+	`
+
+	if _, err := r.db.Exec(query, client.UserAgent, client.IP, expiresAt, now, id); err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	return nil
+}
+
+// MarkSessionSeen обновляет время активности действующего сеанса, если оно не обновлялось с staleBefore
+func (r *SessionRepository) MarkSessionSeen(id int, now, staleBefore time.Time) error {
+	query := `
+		UPDATE sessions
+		SET last_seen_at = $2
+		WHERE id = $1 AND revoked_at IS NULL AND last_seen_at < $3
+	`
+
+	if _, err := r.db.Exec(query, id, now, staleBefore); err != nil {
+		return fmt.Errorf("failed to mark session seen: %w", err)
+	}
+
+	return nil
+}
+
+// ListActiveSessions получает действующие сеансы пользователя, недавно активные первыми
+func (r *SessionRepository) ListActiveSessions(userID int, now time.Time) ([]models.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_seen_at DESC, id DESC
+	`
+
+	rows, err := r.db.Query(query, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []models.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, *session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession отзывает сеанс пользователя вместе с его refresh-токенами
+func (r *SessionRepository) RevokeSession(userID, id int, now time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var familyID string
+	err = tx.QueryRow(`
+		UPDATE sessions
+		SET revoked_at = $1
+		WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL
+		RETURNING family_id
+	`, now, id, userID).Scan(&familyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("session not found")
+		}
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	_, err = tx.Exec(
+		"UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL",
+		now, familyID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session refresh tokens: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeUserSessions отзывает все сеансы пользователя
+func (r *SessionRepository) RevokeUserSessions(userID int, now time.Time) error {
+	query := "UPDATE sessions SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL"
+
+	if _, err := r.db.Exec(query, now, userID); err != nil {
+		return fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+
+	return nil
+}
+
+func scanSession(row rowScanner) (*models.Session, error) {
+	var session models.Session
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.FamilyID,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.ExpiresAt,
+		&session.LastSeenAt,
+		&session.RevokedAt,
+		&session.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"marketplace-api/internal/models"
+)
+
+// signingKeyLockKey ключ advisory-блокировки ротации ключей подписи
+const signingKeyLockKey int64 = 7_346_100_002
+
+type SigningKeyRepository struct {
+	db *sql.DB
+}
+
+func NewSigningKeyRepository(db *sql.DB) *SigningKeyRepository {
+	return &SigningKeyRepository{db: db}
+}
+
+// GetSigningKeys получает все ключи, которые еще принимаются при проверке токенов
+func (r *SigningKeyRepository) GetSigningKeys(now time.Time) ([]models.SigningKey, error) {
+	query := `
+		SELECT kid, algorithm, private_key, activates_at, retires_at, expires_at, created_at
+		FROM signing_keys
+		WHERE expires_at IS NULL OR expires_at > $1
+		ORDER BY activates_at
+	`
+
+	rows, err := r.db.Query(query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signing keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []models.SigningKey
+	for rows.Next() {
+		var key models.SigningKey
+		err := rows.Scan(
+			&key.ID,
+			&key.Algorithm,
+			&key.PrivateKey,
+			&key.ActivatesAt,
+			&key.RetiresAt,
+			&key.ExpiresAt,
+			&key.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan signing key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return keys, nil
+}
+
+// GetCurrentSigningKey получает последний не выведенный из оборота ключ
+func (r *SigningKeyRepository) GetCurrentSigningKey() (*models.SigningKey, error) {
+	return r.getCurrentSigningKey(r.db)
+}
+
+// RotateSigningKey под advisory-блокировкой выводит из оборота текущие ключи и добавляет newKey.
+// Выведенные ключи перестают подписывать токены с newKey.ActivatesAt и принимаются до retiredExpiresAt.
+// Ротация выполняется, только если due для текущего ключа (возможно, nil) возвращает true
+func (r *SigningKeyRepository) RotateSigningKey(newKey models.SigningKey, retiredExpiresAt time.Time, due func(current *models.SigningKey) bool) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", signingKeyLockKey); err != nil {
+		return false, fmt.Errorf("failed to acquire signing key lock: %w", err)
+	}
+
+	current, err := r.getCurrentSigningKey(tx)
+	if err != nil && err.Error() != "signing key not found" {
+		return false, err
+	}
+
+	if !due(current) {
+		return false, nil
+	}
+
+	retireQuery := `
+		UPDATE signing_keys
+		SET retires_at = $1, expires_at = $2
+		WHERE retires_at IS NULL
+	`
+	if _, err := tx.Exec(retireQuery, newKey.ActivatesAt, retiredExpiresAt); err != nil {
+		return false, fmt.Errorf("failed to retire signing keys: %w", err)
+	}
+
+	insertQuery := `
+		INSERT INTO signing_keys (kid, algorithm, private_key, activates_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := tx.Exec(insertQuery, newKey.ID, newKey.Algorithm, newKey.PrivateKey, newKey.ActivatesAt); err != nil {
+		return false, fmt.Errorf("failed to insert signing key: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM signing_keys WHERE expires_at < $1", newKey.ActivatesAt); err != nil {
+		return false, fmt.Errorf("failed to delete expired signing keys: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit signing key rotation: %w", err)
+	}
+
+	return true, nil
+}
+
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func (r *SigningKeyRepository) getCurrentSigningKey(q queryRower) (*models.SigningKey, error) {
+	query := `
+		SELECT kid, algorithm, private_key, activates_at, retires_at, expires_at, created_at
+		FROM signing_keys
+		WHERE retires_at IS NULL
+		ORDER BY activates_at DESC
+		LIMIT 1
+	`
+
+	var key models.SigningKey
+	err := q.QueryRow(query).Scan(
+		&key.ID,
+		&key.Algorithm,
+		&key.PrivateKey,
+		&key.ActivatesAt,
+		&key.RetiresAt,
+		&key.ExpiresAt,
+		&key.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("signing key not found")
+		}
+		return nil, fmt.Errorf("failed to get current signing key: %w", err)
+	}
+
+	return &key, nil
+}
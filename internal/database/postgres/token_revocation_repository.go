@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type TokenRevocationRepository struct {
+	db *sql.DB
+}
+
+func NewTokenRevocationRepository(db *sql.DB) *TokenRevocationRepository {
+	return &TokenRevocationRepository{db: db}
+}
+
+// RevokeToken отзывает access-токен по его jti до момента истечения
+func (r *TokenRevocationRepository) RevokeToken(jti string, userID int, expiresAt time.Time) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	if _, err := r.db.Exec(query, jti, userID, expiresAt); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	// записи об истекших токенах больше не нужны
+	if _, err := r.db.Exec("DELETE FROM revoked_tokens WHERE expires_at < $1", time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to delete expired revocations: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeUserTokens отзывает все выпущенные токены пользователя, увеличивая версию его токенов
+func (r *TokenRevocationRepository) RevokeUserTokens(userID int) error {
+	query := "UPDATE users SET token_version = token_version + 1 WHERE id = $1"
+
+	if _, err := r.db.Exec(query, userID); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+
+	return nil
+}
+
+// GetRevocationState возвращает, отозван ли токен jti или его сеанс, и текущую версию токенов пользователя.
+// Сеанс, которого уже нет в базе, считается отозванным. Нулевой sessionID — токен выпущен до появления сеансов
+func (r *TokenRevocationRepository) GetRevocationState(jti string, userID, sessionID int) (bool, int, error) {
+	query := `
+		SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)
+		       OR ($3 > 0 AND NOT EXISTS(SELECT 1 FROM sessions WHERE id = $3 AND revoked_at IS NULL)),
+		       COALESCE((SELECT token_version FROM users WHERE id = $2), 0)
+	`
+
+	var revoked bool
+	var tokenVersion int
+	if err := r.db.QueryRow(query, jti, userID, sessionID).Scan(&revoked, &tokenVersion); err != nil {
+		return false, 0, fmt.Errorf("failed to get token revocation state: %w", err)
+	}
+
+	return revoked, tokenVersion, nil
+}
@@ -0,0 +1,256 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"marketplace-api/internal/models"
+)
+
+type TwoFactorRepository struct {
+	db *sql.DB
+}
+
+func NewTwoFactorRepository(db *sql.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// GetTOTP получает секрет TOTP пользователя
+func (r *TwoFactorRepository) GetTOTP(userID int) (*models.UserTOTP, error) {
+	query := `
+		SELECT user_id, secret, enabled_at, last_used_step, created_at
+		FROM user_totp
+		WHERE user_id = $1
+	`
+
+	var totp models.UserTOTP
+	err := r.db.QueryRow(query, userID).Scan(
+		&totp.UserID,
+		&totp.Secret,
+		&totp.EnabledAt,
+		&totp.LastUsedStep,
+		&totp.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("totp not found")
+		}
+		return nil, fmt.Errorf("failed to get totp: %w", err)
+	}
+
+	return &totp, nil
+}
+
+// SavePendingTOTP сохраняет новый неподтвержденный секрет, заменяя прежний неподтвержденный.
+// Подтвержденный секрет не перезаписывается: возвращается ошибка "totp already enabled"
+func (r *TwoFactorRepository) SavePendingTOTP(userID int, secret string) error {
+	query := `
+		INSERT INTO user_totp (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = NULL, created_at = CURRENT_TIMESTAMP
+		WHERE user_totp.enabled_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, userID, secret)
+	if err != nil {
+		return fmt.Errorf("failed to save totp: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("totp already enabled")
+	}
+
+	return nil
+}
+
+// EnableTOTP подтверждает секрет, отмечает использованный интервал и заменяет коды восстановления
+func (r *TwoFactorRepository) EnableTOTP(userID int, step int64, recoveryCodeHashes []string, now time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		"UPDATE user_totp SET enabled_at = $2, last_used_step = $3 WHERE user_id = $1 AND enabled_at IS NULL",
+		userID, now, step,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enable totp: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("totp not found")
+	}
+
+	if _, err := tx.Exec("UPDATE users SET two_factor_enabled = TRUE, updated_at = $2 WHERE id = $1", userID, now); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if err := replaceRecoveryCodes(tx, userID, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DisableTOTP удаляет секрет и коды восстановления пользователя
+func (r *TwoFactorRepository) DisableTOTP(userID int, now time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM user_totp WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("failed to delete totp: %w", err)
+	}
+
+	if err := replaceRecoveryCodes(tx, userID, nil); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE users SET two_factor_enabled = FALSE, updated_at = $2 WHERE id = $1", userID, now); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// UseTOTPStep атомарно отмечает интервал использованным.
+// Возвращает false, если этот или более поздний интервал уже использовался (повтор кода)
+func (r *TwoFactorRepository) UseTOTPStep(userID int, step int64) (bool, error) {
+	query := `
+		UPDATE user_totp
+		SET last_used_step = $2
+		WHERE user_id = $1 AND enabled_at IS NOT NULL AND (last_used_step IS NULL OR last_used_step < $2)
+	`
+
+	result, err := r.db.Exec(query, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to use totp step: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// UseRecoveryCode атомарно погашает код восстановления. Возвращает false, если кода нет или он использован
+func (r *TwoFactorRepository) UseRecoveryCode(userID int, codeHash string, now time.Time) (bool, error) {
+	query := `
+		UPDATE recovery_codes
+		SET used_at = $3
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, userID, codeHash, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// CreateChallenge сохраняет хеш токена незавершенного входа.
+// Истекшие незавершенные входы пользователя при этом удаляются
+func (r *TwoFactorRepository) CreateChallenge(userID int, tokenHash string, expiresAt, now time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM login_challenges WHERE user_id = $1 AND expires_at < $2", userID, now); err != nil {
+		return fmt.Errorf("failed to delete expired login challenges: %w", err)
+	}
+
+	insertQuery := `
+		INSERT INTO login_challenges (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+	`
+	if _, err := tx.Exec(insertQuery, userID, tokenHash, expiresAt); err != nil {
+		return fmt.Errorf("failed to create login challenge: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// AttemptChallenge учитывает попытку ввода кода для действующего незавершенного входа и возвращает его.
+// Использованный, истекший, исчерпавший maxAttempts или неизвестный токен дает ошибку "challenge not found"
+func (r *TwoFactorRepository) AttemptChallenge(tokenHash string, maxAttempts int, now time.Time) (*models.LoginChallenge, error) {
+	query := `
+		UPDATE login_challenges
+		SET attempts = attempts + 1
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2 AND attempts < $3
+		RETURNING id, user_id, attempts, expires_at, used_at, created_at
+	`
+
+	var challenge models.LoginChallenge
+	err := r.db.QueryRow(query, tokenHash, now, maxAttempts).Scan(
+		&challenge.ID,
+		&challenge.UserID,
+		&challenge.Attempts,
+		&challenge.ExpiresAt,
+		&challenge.UsedAt,
+		&challenge.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("challenge not found")
+		}
+		return nil, fmt.Errorf("failed to get login challenge: %w", err)
+	}
+
+	return &challenge, nil
+}
+
+// ConsumeChallenge помечает незавершенный вход завершенным. Возвращает false, если он уже был завершен
+func (r *TwoFactorRepository) ConsumeChallenge(id int, now time.Time) (bool, error) {
+	result, err := r.db.Exec("UPDATE login_challenges SET used_at = $2 WHERE id = $1 AND used_at IS NULL", id, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume login challenge: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func replaceRecoveryCodes(tx *sql.Tx, userID int, codeHashes []string) error {
+	if _, err := tx.Exec("DELETE FROM recovery_codes WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	for _, hash := range codeHashes {
+		if _, err := tx.Exec("INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)", userID, hash); err != nil {
+			return fmt.Errorf("failed to insert recovery code: %w", err)
+		}
+	}
+
+	return nil
+}
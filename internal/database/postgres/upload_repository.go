@@ -0,0 +1,257 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+	"marketplace-api/internal/models"
+)
+
+// uploadColumns поля загрузки в порядке сканирования scanUpload
+const uploadColumns = `id, COALESCE(user_id, 0), content_hash, blob_key, content_type, size, width, height, created_at`
+
+type UploadRepository struct {
+	db *sql.DB
+}
+
+func NewUploadRepository(db *sql.DB) *UploadRepository {
+	return &UploadRepository{db: db}
+}
+
+// CreateUpload сохраняет загрузку и вызывает store для записи файла в хранилище до фиксации транзакции.
+// Ключ файла блокируется до конца транзакции, поэтому очистка не удалит файл, пока загрузка не сохранена.
+// Если пользователь уже загружал файл с тем же содержимым, возвращает ошибку "upload already exists"
+func (r *UploadRepository) CreateUpload(upload models.Upload, now time.Time, store func() error) (*models.Upload, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockFileKey(tx, upload.FileKey); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO uploads (user_id, content_hash, blob_key, content_type, size, width, height, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (user_id, content_hash) DO NOTHING
+		RETURNING ` + uploadColumns
+
+	created, err := scanUpload(tx.QueryRow(query,
+		upload.UserID,
+		upload.ContentHash,
+		upload.FileKey,
+		upload.ContentType,
+		upload.Size,
+		upload.Width,
+		upload.Height,
+		now,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("upload already exists")
+		}
+		return nil, fmt.Errorf("failed to create upload: %w", err)
+	}
+
+	if err := store(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetUserUploadByHash получает загрузку пользователя по SHA-256 содержимого
+func (r *UploadRepository) GetUserUploadByHash(userID int, contentHash string) (*models.Upload, error) {
+	query := `SELECT ` + uploadColumns + ` FROM uploads WHERE user_id = $1 AND content_hash = $2`
+
+	upload, err := scanUpload(r.db.QueryRow(query, userID, contentHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("upload not found")
+		}
+		return nil, fmt.Errorf("failed to get upload: %w", err)
+	}
+
+	return upload, nil
+}
+
+// ListUserUploads возвращает все загрузки пользователя, начиная с самой старой
+func (r *UploadRepository) ListUserUploads(userID int) ([]models.Upload, error) {
+	query := `SELECT ` + uploadColumns + ` FROM uploads WHERE user_id = $1 ORDER BY created_at, id`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get uploads: %w", err)
+	}
+	defer rows.Close()
+
+	uploads := []models.Upload{}
+	for rows.Next() {
+		upload, err := scanUpload(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan upload: %w", err)
+		}
+		uploads = append(uploads, *upload)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return uploads, nil
+}
+
+// CountUserFileKeys считает, сколько из переданных ключей файлов принадлежат загрузкам пользователя
+func (r *UploadRepository) CountUserFileKeys(userID int, fileKeys []string) (int, error) {
+	query := `SELECT COUNT(DISTINCT blob_key) FROM uploads WHERE user_id = $1 AND blob_key = ANY($2)`
+
+	var count int
+	if err := r.db.QueryRow(query, userID, pq.Array(fileKeys)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count uploads: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteOrphanedUploads удаляет загрузки, созданные раньше before и не используемые ни в одном
+// объявлении их владельца, а также загрузки без владельца. Загрузки, которые прямо сейчас
+// добавляются в объявление, пропускаются. Для файлов, на которые больше не ссылается ни одна загрузка,
+// вызывается deleteFile. Возвращает число таких файлов
+func (r *UploadRepository) DeleteOrphanedUploads(before time.Time, deleteFile func(key string)) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// объявление блокирует свои загрузки FOR SHARE, поэтому SKIP LOCKED не трогает загрузки,
+	// ссылка на которые еще не зафиксирована
+	query := `
+		DELETE FROM uploads
+		WHERE id IN (
+			SELECT u.id
+			FROM uploads u
+			WHERE u.created_at < $1
+			  AND NOT EXISTS (
+				SELECT 1
+				FROM listing_images li
+				JOIN listings l ON l.id = li.listing_id
+				WHERE li.file_key = u.blob_key AND l.user_id = u.user_id
+			  )
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING blob_key
+	`
+
+	deletedKeys, err := queryStrings(tx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete orphaned uploads: %w", err)
+	}
+	if len(deletedKeys) == 0 {
+		return 0, nil
+	}
+
+	deleted, err := deleteUnusedFiles(tx, deletedKeys, deleteFile)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// deleteUnusedFiles вызывает deleteFile для ключей удаленных загрузок, на которые не ссылается ни одна загрузка.
+// Одинаковые файлы разных пользователей хранятся под одним ключом, поэтому каждый ключ проверяется
+// под блокировкой: параллельная загрузка того же файла дождется удаления и запишет файл заново
+func deleteUnusedFiles(tx *sql.Tx, deletedKeys []string, deleteFile func(key string)) (int, error) {
+	deleted := 0
+	for _, key := range slices.Compact(slices.Sorted(slices.Values(deletedKeys))) {
+		if err := lockFileKey(tx, key); err != nil {
+			return deleted, err
+		}
+
+		var used bool
+		if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM uploads WHERE blob_key = $1)", key).Scan(&used); err != nil {
+			return deleted, fmt.Errorf("failed to check file usage: %w", err)
+		}
+		if used {
+			continue
+		}
+
+		deleteFile(key)
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// lockFileKey блокирует ключ файла до конца транзакции
+func lockFileKey(tx *sql.Tx, key string) error {
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('uploads'), hashtext($1))", key); err != nil {
+		return fmt.Errorf("failed to lock file key: %w", err)
+	}
+	return nil
+}
+
+// queryStrings выполняет запрос, возвращающий одну текстовую колонку
+func queryStrings(tx *sql.Tx, query string, args ...interface{}) ([]string, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return values, rows.Err()
+}
+
+func scanUpload(row rowScanner) (*models.Upload, error) {
+	var upload models.Upload
+	var width, height sql.NullInt64
+
+	err := row.Scan(
+		&upload.ID,
+		&upload.UserID,
+		&upload.ContentHash,
+		&upload.FileKey,
+		&upload.ContentType,
+		&upload.Size,
+		&width,
+		&height,
+		&upload.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if width.Valid {
+		value := int(width.Int64)
+		upload.Width = &value
+	}
+	if height.Valid {
+		value := int(height.Int64)
+		upload.Height = &value
+	}
+
+	return &upload, nil
+}
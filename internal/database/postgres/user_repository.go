@@ -3,10 +3,20 @@ package postgres
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"marketplace-api/internal/models"
+	"marketplace-api/pkg/rbac"
 )
 
+// userColumns поля пользователя в порядке сканирования scanUser
+const userColumns = `id, login, COALESCE(email, ''), email_verified_at, password_hash, role, two_factor_enabled,
+	deletion_scheduled_at, deleted_at, token_version, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
 type UserRepository struct {
 	db *sql.DB
 }
@@ -15,47 +25,30 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-// CreateUser создает нового пользователя
-func (r *UserRepository) CreateUser(login, passwordHash string) (*models.User, error) {
+// CreateUser создает нового пользователя. Пустой email сохраняется как NULL
+func (r *UserRepository) CreateUser(login, email, passwordHash string) (*models.User, error) {
 	query := `
-		INSERT INTO users (login, password_hash) 
-		VALUES ($1, $2) 
-		RETURNING id, login, password_hash, created_at, updated_at
-	`
-
-	var user models.User
-	err := r.db.QueryRow(query, login, passwordHash).Scan(
-		&user.ID,
-		&user.Login,
-		&user.PasswordHash,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+		INSERT INTO users (login, email, password_hash) 
+		VALUES ($1, NULLIF($2, ''), $3) 
+		RETURNING ` + userColumns
 
+	user, err := scanUser(r.db.QueryRow(query, login, email, passwordHash))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 // GetUserByLogin получает пользователя по логину
 func (r *UserRepository) GetUserByLogin(login string) (*models.User, error) {
 	query := `
-		SELECT id, login, password_hash, created_at, updated_at 
+		SELECT ` + userColumns + `
 		FROM users 
 		WHERE login = $1
 	`
 
-	var user models.User
-	err := r.db.QueryRow(query, login).Scan(
-		&user.ID,
-		&user.Login,
-		&user.PasswordHash,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
+	user, err := scanUser(r.db.QueryRow(query, login))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("user not found")
@@ -63,26 +56,37 @@ func (r *UserRepository) GetUserByLogin(login string) (*models.User, error) {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 // GetUserByID получает пользователя по ID
 func (r *UserRepository) GetUserByID(id int) (*models.User, error) {
 	query := `
-		SELECT id, login, password_hash, created_at, updated_at 
+		SELECT ` + userColumns + `
 		FROM users 
 		WHERE id = $1
 	`
 
-	var user models.User
-	err := r.db.QueryRow(query, id).Scan(
-		&user.ID,
-		&user.Login,
-		&user.PasswordHash,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	user, err := scanUser(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetUserByEmail получает пользователя по email. Сравнение без учета регистра
+func (r *UserRepository) GetUserByEmail(email string) (*models.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE LOWER(email) = LOWER($1)
+	`
 
+	user, err := scanUser(r.db.QueryRow(query, email))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("user not found")
@@ -90,7 +94,7 @@ func (r *UserRepository) GetUserByID(id int) (*models.User, error) {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 // UserExists проверяет, существует ли пользователь с таким логином
@@ -105,3 +109,184 @@ func (r *UserRepository) UserExists(login string) (bool, error) {
 
 	return exists, nil
 }
+
+// EmailExists проверяет, занят ли email другим пользователем. Сравнение без учета регистра
+func (r *UserRepository) EmailExists(email string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(email) = LOWER($1))`
+
+	var exists bool
+	err := r.db.QueryRow(query, email).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check email existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+// UpdateUserRole изменяет роль пользователя
+func (r *UserRepository) UpdateUserRole(id int, role rbac.Role) (*models.User, error) {
+	query := `
+		UPDATE users
+		SET role = $1, updated_at = $2
+		WHERE id = $3
+		RETURNING ` + userColumns
+
+	user, err := scanUser(r.db.QueryRow(query, role, time.Now(), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to update user role: %w", err)
+	}
+
+	return user, nil
+}
+
+// UpdateEmail изменяет email пользователя и сбрасывает его подтверждение
+func (r *UserRepository) UpdateEmail(id int, email string) (*models.User, error) {
+	query := `
+		UPDATE users
+		SET email = $1, email_verified_at = NULL, updated_at = $2
+		WHERE id = $3
+		RETURNING ` + userColumns
+
+	user, err := scanUser(r.db.QueryRow(query, email, time.Now(), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to update email: %w", err)
+	}
+
+	return user, nil
+}
+
+// ListUsers получает постраничный список пользователей
+func (r *UserRepository) ListUsers(filter models.UsersFilter) (*models.PaginatedUsers, error) {
+	whereClause := ""
+	var args []interface{}
+	if filter.Role != "" {
+		whereClause = "WHERE role = $1"
+		args = append(args, filter.Role)
+	}
+
+	var total int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM users "+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM users
+		%s
+		ORDER BY id
+		LIMIT $%d OFFSET $%d
+	`, userColumns, whereClause, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.GetOffset())
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, *user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return &models.PaginatedUsers{
+		Data:       users,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: (total + filter.Limit - 1) / filter.Limit,
+	}, nil
+}
+
+// UpdatePassword изменяет хеш пароля пользователя
+func (r *UserRepository) UpdatePassword(id int, passwordHash string) error {
+	query := "UPDATE users SET password_hash = $1, updated_at = $2 WHERE id = $3"
+
+	result, err := r.db.Exec(query, passwordHash, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+// ScheduleDeletion назначает удаление аккаунта на момент scheduledAt
+func (r *UserRepository) ScheduleDeletion(id int, scheduledAt time.Time) (*models.User, error) {
+	query := `
+		UPDATE users
+		SET deletion_scheduled_at = $1, updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL
+		RETURNING ` + userColumns
+
+	user, err := scanUser(r.db.QueryRow(query, scheduledAt, time.Now().UTC(), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to schedule account deletion: %w", err)
+	}
+
+	return user, nil
+}
+
+// CancelDeletion отменяет назначенное удаление аккаунта, если оно еще не выполнено
+func (r *UserRepository) CancelDeletion(id int) error {
+	query := `
+		UPDATE users
+		SET deletion_scheduled_at = NULL, updated_at = $1
+		WHERE id = $2 AND deletion_scheduled_at IS NOT NULL AND deleted_at IS NULL
+	`
+
+	if _, err := r.db.Exec(query, time.Now().UTC(), id); err != nil {
+		return fmt.Errorf("failed to cancel account deletion: %w", err)
+	}
+
+	return nil
+}
+
+func scanUser(row rowScanner) (*models.User, error) {
+	var user models.User
+	err := row.Scan(
+		&user.ID,
+		&user.Login,
+		&user.Email,
+		&user.EmailVerifiedAt,
+		&user.PasswordHash,
+		&user.Role,
+		&user.TwoFactorEnabled,
+		&user.DeletionScheduledAt,
+		&user.DeletedAt,
+		&user.TokenVersion,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
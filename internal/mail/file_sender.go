@@ -0,0 +1,33 @@
+package mail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileSender сохраняет каждое письмо в отдельный .eml файл. Для локальной разработки и тестов
+type FileSender struct {
+	dir  string
+	from string
+}
+
+func NewFileSender(dir, from string) (*FileSender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	return &FileSender{dir: dir, from: from}, nil
+}
+
+// Send записывает письмо в файл
+func (s *FileSender) Send(msg Message) error {
+	name := fmt.Sprintf("%s.eml", time.Now().UTC().Format("20060102T150405.000000000"))
+
+	if err := os.WriteFile(filepath.Join(s.dir, name), formatMessage(s.from, msg), 0o644); err != nil {
+		return fmt.Errorf("failed to write mail message: %w", err)
+	}
+
+	return nil
+}
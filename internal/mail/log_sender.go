@@ -0,0 +1,19 @@
+package mail
+
+import "log/slog"
+
+// LogSender пишет в лог адресата и тему писем вместо отправки. Тело не пишется:
+// в нем ссылки с токенами подтверждения и сброса пароля. Для локальной разработки
+type LogSender struct {
+	log *slog.Logger
+}
+
+func NewLogSender(log *slog.Logger) *LogSender {
+	return &LogSender{log: log}
+}
+
+// Send выводит адресата и тему письма в лог
+func (s *LogSender) Send(msg Message) error {
+	s.log.Info("Mail message", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
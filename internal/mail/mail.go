@@ -0,0 +1,51 @@
+// Package mail отправляет служебные письма пользователям.
+package mail
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"marketplace-api/internal/config"
+)
+
+// Message письмо в виде простого текста
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender отправляет письма
+type Sender interface {
+	Send(msg Message) error
+}
+
+// NewSender создает отправителя по настройкам: log, file или smtp
+func NewSender(cfg config.MailConfig, log *slog.Logger) (Sender, error) {
+	switch cfg.Driver {
+	case "log":
+		return NewLogSender(log), nil
+	case "file":
+		return NewFileSender(cfg.FileDir, cfg.From)
+	case "smtp":
+		return NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword, cfg.From), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver: %s", cfg.Driver)
+	}
+}
+
+// formatMessage собирает письмо в формате RFC 5322
+func formatMessage(from string, msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
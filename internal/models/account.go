@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// DeleteAccountRequest структура запроса на удаление аккаунта.
+// Пароль обязателен, если он задан; у пользователей, созданных через внешнего провайдера, пароля может не быть
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// AccountDeletion назначенное удаление аккаунта
+type AccountDeletion struct {
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
+
+// UserDataExport выгрузка персональных данных пользователя
+type UserDataExport struct {
+	ExportedAt     time.Time       `json:"exported_at"`
+	Profile        User            `json:"profile"`
+	PublicProfile  UserProfile     `json:"public_profile"`
+	Listings       []Listing       `json:"listings"`
+	Sessions       []Session       `json:"sessions"`
+	APIKeys        []APIKey        `json:"api_keys"`
+	Identities     []UserIdentity  `json:"identities"`
+	Uploads        []Upload        `json:"uploads"`
+	SecurityEvents []SecurityEvent `json:"security_events"`
+}
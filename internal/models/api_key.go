@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// APIKey персональный API-ключ. Сам ключ не хранится, только его хеш и начало для отображения
+type APIKey struct {
+	ID         int        `json:"id" db:"id"`
+	UserID     int        `json:"-" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"prefix"`
+	Scopes     []string   `json:"scopes" db:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CreateAPIKeyRequest структура запроса на создание API-ключа. Без expires_at ключ бессрочный
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreatedAPIKey созданный API-ключ. Key показывается только в этом ответе
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyOwner API-ключ вместе с данными владельца, от имени которого он действует
+type APIKeyOwner struct {
+	APIKey
+	Login string
+	Role  string
+}
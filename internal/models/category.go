@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Category категория объявлений. Категории образуют дерево произвольной вложенности
+type Category struct {
+	ID int `json:"id" db:"id"`
+	// ParentID родительская категория; nil у категорий верхнего уровня
+	ParentID  *int      `json:"parent_id" db:"parent_id"`
+	Slug      string    `json:"slug" db:"slug"`
+	Name      string    `json:"name" db:"name"`
+	SortOrder int       `json:"sort_order" db:"sort_order"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// Children подкатегории, заполняются только в дереве категорий
+	Children []Category `json:"children,omitempty"`
+}
+
+// CreateCategoryRequest структура для создания категории
+type CreateCategoryRequest struct {
+	ParentID  *int   `json:"parent_id,omitempty" binding:"omitempty,min=1"`
+	Slug      string `json:"slug" binding:"required,max=100"`
+	Name      string `json:"name" binding:"required,min=1,max=100"`
+	SortOrder int    `json:"sort_order"`
+}
+
+// UpdateCategoryRequest структура для обновления категории.
+// ParentID со значением 0 переносит категорию на верхний уровень
+type UpdateCategoryRequest struct {
+	ParentID  *int    `json:"parent_id,omitempty" binding:"omitempty,min=0"`
+	Slug      *string `json:"slug,omitempty" binding:"omitempty,max=100"`
+	Name      *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	SortOrder *int    `json:"sort_order,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// EmailVerificationToken запись токена подтверждения email. Сам токен не хранится, только его хеш
+type EmailVerificationToken struct {
+	ID        int        `db:"id"`
+	UserID    int        `db:"user_id"`
+	Email     string     `db:"email"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// ChangeEmailRequest структура для установки или смены email
+type ChangeEmailRequest struct {
+	Email    string `json:"email" binding:"required,max=254"`
+	Password string `json:"password" binding:"required"`
+}
+
+// VerifyEmailRequest структура для подтверждения email по токену из письма
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// UserIdentity учетная запись пользователя у внешнего провайдера OpenID Connect.
+// Провайдер однозначно определяет пользователя парой issuer + subject
+type UserIdentity struct {
+	ID          int       `json:"id" db:"id"`
+	UserID      int       `json:"-" db:"user_id"`
+	Issuer      string    `json:"issuer" db:"issuer"`
+	Subject     string    `json:"subject" db:"subject"`
+	Email       string    `json:"email,omitempty" db:"email"`
+	LastLoginAt time.Time `json:"last_login_at" db:"last_login_at"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// OIDCLoginState незавершенный вход через провайдера. Сам state не хранится, только его хеш
+type OIDCLoginState struct {
+	CodeVerifier string    `db:"code_verifier"`
+	Nonce        string    `db:"nonce"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
+
+// OIDCAuthorization адрес страницы входа у провайдера
+type OIDCAuthorization struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+// OIDCCallbackRequest параметры, с которыми провайдер возвращает пользователя в приложение
+type OIDCCallbackRequest struct {
+	Code             string `form:"code"`
+	State            string `form:"state" binding:"required"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
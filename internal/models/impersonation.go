@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// ImpersonateRequest структура запроса на вход от имени пользователя
+type ImpersonateRequest struct {
+	// Reason причина, например номер обращения в поддержку. Сохраняется в журнале
+	Reason string `json:"reason" binding:"required,max=500"`
+	// AllowWrite разрешает изменяющие запросы от имени пользователя; по умолчанию доступен только просмотр
+	AllowWrite bool `json:"allow_write"`
+}
+
+// ImpersonationResponse токен для работы от имени пользователя. Refresh-токен не выдается
+type ImpersonationResponse struct {
+	ImpersonationID int       `json:"impersonation_id"`
+	User            User      `json:"user"`
+	Token           string    `json:"token"`
+	TokenExpiresAt  time.Time `json:"token_expires_at"`
+	AllowWrite      bool      `json:"allow_write"`
+}
+
+// Impersonation запись журнала о входе администратора от имени пользователя
+type Impersonation struct {
+	ID         int       `json:"id" db:"id"`
+	AdminID    int       `json:"admin_id" db:"admin_id"`
+	AdminLogin string    `json:"admin_login" db:"admin_login"`
+	UserID     int       `json:"user_id" db:"user_id"`
+	UserLogin  string    `json:"user_login" db:"user_login"`
+	TokenID    string    `json:"-" db:"token_id"`
+	Reason     string    `json:"reason" db:"reason"`
+	AllowWrite bool      `json:"allow_write" db:"allow_write"`
+	IPAddress  string    `json:"ip_address" db:"ip_address"`
+	UserAgent  string    `json:"user_agent" db:"user_agent"`
+	ExpiresAt  time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	// RequestsCount количество запросов, выполненных с токеном
+	RequestsCount int `json:"requests_count" db:"requests_count"`
+	// Requests запросы, выполненные с токеном; заполняется только при получении одной записи
+	Requests []ImpersonatedRequest `json:"requests,omitempty"`
+}
+
+// ImpersonatedRequest запрос, выполненный администратором от имени пользователя
+type ImpersonatedRequest struct {
+	ID        int64  `json:"id" db:"id"`
+	Method    string `json:"method" db:"method"`
+	Path      string `json:"path" db:"path"`
+	IPAddress string `json:"ip_address" db:"ip_address"`
+	// Blocked запрос отклонен, потому что изменяющие запросы не разрешены
+	Blocked bool `json:"blocked" db:"blocked"`
+	// Status код ответа; пусто, если запрос не завершился
+	Status    *int      `json:"status" db:"status"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// ImpersonationsFilter фильтры журнала входов от имени пользователей
+type ImpersonationsFilter struct {
+	AdminID int `form:"admin_id" binding:"omitempty,min=1"`
+	UserID  int `form:"user_id" binding:"omitempty,min=1"`
+	Page    int `form:"page" binding:"omitempty,min=1"`
+	Limit   int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// SetDefaults устанавливает значения по умолчанию для фильтра
+func (f *ImpersonationsFilter) SetDefaults() {
+	if f.Page == 0 {
+		f.Page = 1
+	}
+	if f.Limit == 0 {
+		f.Limit = 20
+	}
+}
+
+// GetOffset возвращает offset для пагинации
+func (f *ImpersonationsFilter) GetOffset() int {
+	return (f.Page - 1) * f.Limit
+}
+
+// PaginatedImpersonations результат с пагинацией
+type PaginatedImpersonations struct {
+	Data       []Impersonation `json:"data"`
+	Total      int             `json:"total"`
+	Page       int             `json:"page"`
+	Limit      int             `json:"limit"`
+	TotalPages int             `json:"total_pages"`
+}
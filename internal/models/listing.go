@@ -1,49 +1,136 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Listing модель объявления
 type Listing struct {
 	ID          int       `json:"id" db:"id"`
 	Title       string    `json:"title" db:"title"`
 	Description string    `json:"description" db:"description"`
-	ImageURL    *string   `json:"image_url" db:"image_url"` // pointer для nullable поля
+	ImageURL    *string   `json:"image_url" db:"image_url"` // URL обложки, nil если у объявления нет изображений
 	Price       float64   `json:"price" db:"price"`
+	CategoryID  int       `json:"category_id" db:"category_id"`
 	UserID      int       `json:"user_id" db:"user_id"`
 	UserLogin   string    `json:"user_login,omitempty" db:"user_login"` // для joined запросов
 	IsOwner     bool      `json:"is_owner,omitempty"`                   // признак принадлежности текущему пользователю
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	// Highlight фрагменты с выделенными совпадениями, заполняются только при поиске
+	Highlight *ListingHighlight `json:"highlight,omitempty"`
+	// Cover обложка объявления (первое изображение), заполняется в списках
+	Cover *ListingImage `json:"cover,omitempty"`
+	// Images все изображения объявления по порядку, заполняются в карточке объявления
+	Images []ListingImage `json:"images,omitempty"`
 }
 
+// ListingImage изображение объявления. Задается либо внешним URL, либо ключом загруженного файла
+type ListingImage struct {
+	ID        int `json:"id"`
+	ListingID int `json:"listing_id"`
+	// URL внешний адрес изображения, а для загруженного файла — подписанная ссылка с ограниченным сроком действия
+	URL     *string `json:"url"`
+	FileKey *string `json:"file_key,omitempty"`
+	// Position порядковый номер изображения с 0; изображение на позиции 0 — обложка
+	Position  int       `json:"position"`
+	AltText   string    `json:"alt_text"`
+	Width     *int      `json:"width,omitempty"`
+	Height    *int      `json:"height,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListingImageRequest изображение в запросах создания и изменения объявления.
+// Нужно указать ровно одно из полей url и file_key; file_key — ключ файла, загруженного владельцем объявления
+type ListingImageRequest struct {
+	URL     *string `json:"url,omitempty" binding:"omitempty,url,max=500"`
+	FileKey *string `json:"file_key,omitempty" binding:"omitempty,min=1,max=255"`
+	AltText string  `json:"alt_text" binding:"max=255"`
+	Width   *int    `json:"width,omitempty" binding:"omitempty,min=1"`
+	Height  *int    `json:"height,omitempty" binding:"omitempty,min=1"`
+}
+
+// ReorderListingImagesRequest новый порядок изображений: ID всех изображений объявления, первым идет обложка
+type ReorderListingImagesRequest struct {
+	ImageIDs []int `json:"image_ids" binding:"required,min=1,dive,min=1"`
+}
+
+// ListingHighlight заголовок и фрагменты описания объявления, в которых совпадения с поисковым запросом
+// обернуты в <mark>. Остальной текст экранирован, поэтому сниппеты можно вставлять в HTML как есть
+type ListingHighlight struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// SortByRelevance сортировка результатов поиска по релевантности
+const SortByRelevance = "relevance"
+
 // CreateListingRequest структура для создания объявления
 type CreateListingRequest struct {
-	Title       string  `json:"title" binding:"required,min=1,max=255"`
-	Description string  `json:"description" binding:"required,min=1"`
-	ImageURL    *string `json:"image_url,omitempty" binding:"omitempty,url,max=500"`
-	Price       float64 `json:"price" binding:"required,gt=0"`
+	Title       string `json:"title" binding:"required,min=1,max=255"`
+	Description string `json:"description" binding:"required,min=1"`
+	// ImageURL устаревший способ задать единственное изображение; не сочетается с images
+	ImageURL   *string               `json:"image_url,omitempty" binding:"omitempty,url,max=500"`
+	Images     []ListingImageRequest `json:"images,omitempty" binding:"omitempty,dive"`
+	Price      float64               `json:"price" binding:"required,gt=0"`
+	CategoryID int                   `json:"category_id" binding:"required,min=1"`
 }
 
 // UpdateListingRequest структура для обновления объявления
 type UpdateListingRequest struct {
-	Title       *string  `json:"title,omitempty" binding:"omitempty,min=1,max=255"`
-	Description *string  `json:"description,omitempty" binding:"omitempty,min=1"`
-	ImageURL    *string  `json:"image_url,omitempty" binding:"omitempty,url,max=500"`
-	Price       *float64 `json:"price,omitempty" binding:"omitempty,gt=0"`
+	Title       *string `json:"title,omitempty" binding:"omitempty,min=1,max=255"`
+	Description *string `json:"description,omitempty" binding:"omitempty,min=1"`
+	// ImageURL устаревший способ задать единственное изображение: заменяет все изображения одним, пустая строка удаляет их
+	ImageURL *string `json:"image_url,omitempty" binding:"omitempty,url,max=500"`
+	// Images заменяет все изображения объявления; пустой массив удаляет их
+	Images     *[]ListingImageRequest `json:"images,omitempty" binding:"omitempty,dive"`
+	Price      *float64               `json:"price,omitempty" binding:"omitempty,gt=0"`
+	CategoryID *int                   `json:"category_id,omitempty" binding:"omitempty,min=1"`
 }
 
 // ListingsFilter параметры фильтрации объявлений
 type ListingsFilter struct {
 	MinPrice *float64 `form:"min_price" binding:"omitempty,gte=0"`
 	MaxPrice *float64 `form:"max_price" binding:"omitempty,gte=0"`
-	SortBy   string   `form:"sort_by" binding:"omitempty,oneof=created_at price"`
-	SortDir  string   `form:"sort_dir" binding:"omitempty,oneof=asc desc"`
-	Page     int      `form:"page" binding:"omitempty,min=1"`
-	Limit    int      `form:"limit" binding:"omitempty,min=1,max=100"`
+	// Category slug категории; в выборку попадают объявления этой категории и всех ее подкатегорий
+	Category string `form:"category" binding:"omitempty,max=100"`
+	// Query поисковый запрос по заголовку и описанию в синтаксисе websearch_to_tsquery
+	Query string `form:"q" binding:"omitempty,max=200"`
+	// CreatedAfter оставляет объявления, созданные не раньше указанного момента (RFC 3339)
+	CreatedAfter *time.Time `form:"created_after"`
+	// Facets список фасетов через запятую: category, price, seller, created_at
+	Facets  string `form:"facets" binding:"omitempty,max=100"`
+	SortBy  string `form:"sort_by" binding:"omitempty,oneof=created_at price relevance"`
+	SortDir string `form:"sort_dir" binding:"omitempty,oneof=asc desc"`
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	// Cursor курсор next_cursor или prev_cursor из предыдущего ответа; заменяет page
+	Cursor string `form:"cursor" binding:"omitempty,max=500"`
+	// WithTotal считать ли общее количество объявлений. По умолчанию считается только при пагинации по номеру страницы
+	WithTotal *bool `form:"with_total"`
+	// SellerID ограничивает выборку объявлениями одного продавца. Задается маршрутом, а не параметром запроса
+	SellerID int `form:"-"`
+	// Position позиция в ленте, расшифрованная сервисом из Cursor
+	Position *ListingCursor `form:"-"`
+}
+
+// ListingCursor позиция в ленте объявлений для keyset-пагинации: ключ сортировки и ID объявления на границе страницы
+type ListingCursor struct {
+	SortBy  string `json:"s"`
+	SortDir string `json:"d"`
+	// Value значение ключа сортировки в текстовом представлении PostgreSQL
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+	// Before true для prev_cursor: страница заканчивается перед позицией, а не начинается после нее
+	Before bool `json:"b,omitempty"`
 }
 
 // SetDefaults устанавливает значения по умолчанию для фильтра
 func (f *ListingsFilter) SetDefaults() {
+	if f.SortBy == "" && f.Query != "" {
+		f.SortBy = SortByRelevance
+	}
 	if f.SortBy == "" {
 		f.SortBy = "created_at"
 	}
@@ -58,16 +145,94 @@ func (f *ListingsFilter) SetDefaults() {
 	}
 }
 
-// GetOffset возвращает offset для пагинации
+// FacetNames возвращает запрошенные фасеты без пробелов и пустых элементов
+func (f *ListingsFilter) FacetNames() []string {
+	var names []string
+	for _, name := range strings.Split(f.Facets, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// GetOffset возвращает offset для пагинации. При пагинации по курсору offset не используется
 func (f *ListingsFilter) GetOffset() int {
+	if f.Position != nil {
+		return 0
+	}
 	return (f.Page - 1) * f.Limit
 }
 
-// PaginatedListings результат с пагинацией
+// CountTotal сообщает, нужно ли считать общее количество объявлений
+func (f *ListingsFilter) CountTotal() bool {
+	if f.WithTotal != nil {
+		return *f.WithTotal
+	}
+	return f.Position == nil
+}
+
+// PaginatedListings результат с пагинацией. Total и TotalPages заполняются, только если количество считалось,
+// а Page — только при пагинации по номеру страницы
 type PaginatedListings struct {
 	Data       []Listing `json:"data"`
-	Total      int       `json:"total"`
-	Page       int       `json:"page"`
+	Total      *int      `json:"total,omitempty"`
+	Page       int       `json:"page,omitempty"`
 	Limit      int       `json:"limit"`
-	TotalPages int       `json:"total_pages"`
+	TotalPages *int      `json:"total_pages,omitempty"`
+	// NextCursor и PrevCursor ведут на следующую и предыдущую страницы; пусто, если страницы нет
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	// NextPosition и PrevPosition позиции для курсоров, которые сервис подписывает в NextCursor и PrevCursor
+	NextPosition *ListingCursor `json:"-"`
+	PrevPosition *ListingCursor `json:"-"`
+	// Facets агрегаты по всей выборке, а не только по текущей странице. Заполняется, если запрошен параметр facets
+	Facets *ListingFacets `json:"facets,omitempty"`
+}
+
+// Фасеты списка объявлений
+const (
+	FacetCategory  = "category"
+	FacetPrice     = "price"
+	FacetSeller    = "seller"
+	FacetCreatedAt = "created_at"
+)
+
+// ListingFacets агрегаты по объявлениям, подходящим под фильтр.
+// Заполнены только запрошенные фасеты; фасет без объявлений тоже опускается
+type ListingFacets struct {
+	Categories []CategoryFacet  `json:"categories,omitempty"`
+	Price      []PriceFacet     `json:"price,omitempty"`
+	Sellers    []SellerFacet    `json:"sellers,omitempty"`
+	CreatedAt  []DateRangeFacet `json:"created_at,omitempty"`
+}
+
+// CategoryFacet количество объявлений, размещенных непосредственно в категории
+type CategoryFacet struct {
+	CategoryID int    `json:"category_id"`
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	Count      int    `json:"count"`
+}
+
+// PriceFacet интервал гистограммы цен [from, to); последний интервал включает верхнюю границу
+type PriceFacet struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
+}
+
+// SellerFacet количество объявлений продавца
+type SellerFacet struct {
+	UserID int    `json:"user_id"`
+	Login  string `json:"login"`
+	Count  int    `json:"count"`
+}
+
+// DateRangeFacet количество объявлений, созданных начиная с from.
+// Интервалы вложены друг в друга: last_7d включает last_24h. From можно передать в created_after
+type DateRangeFacet struct {
+	Key   string    `json:"key"`
+	From  time.Time `json:"from"`
+	Count int       `json:"count"`
 }
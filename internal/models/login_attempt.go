@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Области учета неудачных попыток входа. В области password_reset по IP-адресу считаются запросы сброса пароля
+const (
+	LoginAttemptScopeLogin         = "login"
+	LoginAttemptScopeIP            = "ip"
+	LoginAttemptScopePasswordReset = "password_reset"
+)
+
+// LoginAttempt счетчик неудачных попыток входа для логина или IP-адреса клиента
+type LoginAttempt struct {
+	Scope         string     `db:"scope"`
+	Key           string     `db:"key"`
+	Failures      int        `db:"failures"`
+	LastFailureAt time.Time  `db:"last_failure_at"`
+	LockedUntil   *time.Time `db:"locked_until"`
+}
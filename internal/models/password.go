@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// PasswordResetToken запись токена сброса пароля. Сам токен не хранится, только его хеш
+type PasswordResetToken struct {
+	ID        int        `db:"id"`
+	UserID    int        `db:"user_id"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// ChangePasswordRequest структура для смены пароля
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// ForgotPasswordRequest структура для запроса сброса пароля
+type ForgotPasswordRequest struct {
+	Login string `json:"login" binding:"required"`
+}
+
+// ResetPasswordRequest структура для установки нового пароля по токену сброса
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
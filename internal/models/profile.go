@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Способы связи с продавцом
+const (
+	ContactEmail = "email"
+	ContactPhone = "phone"
+)
+
+// UserProfile профиль пользователя в том виде, в котором его видит владелец
+type UserProfile struct {
+	DisplayName string  `json:"display_name" db:"display_name"`
+	Bio         string  `json:"bio" db:"bio"`
+	AvatarURL   *string `json:"avatar_url" db:"avatar_url"`
+	Location    string  `json:"location" db:"location"`
+	// Phone телефон для связи; показывается другим пользователям только при ShowPhone
+	Phone string `json:"phone" db:"phone"`
+	// ShowEmail показывать подтвержденный email в публичном профиле
+	ShowEmail bool `json:"show_email" db:"show_email"`
+	ShowPhone bool `json:"show_phone" db:"show_phone"`
+	// PreferredContact предпочтительный способ связи: email, phone или пусто
+	PreferredContact string     `json:"preferred_contact" db:"preferred_contact"`
+	UpdatedAt        *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// UpdateProfileRequest структура для изменения профиля. Незаданные поля не меняются, пустая строка очищает поле
+type UpdateProfileRequest struct {
+	DisplayName      *string `json:"display_name,omitempty" binding:"omitempty,max=100"`
+	Bio              *string `json:"bio,omitempty" binding:"omitempty,max=1000"`
+	AvatarURL        *string `json:"avatar_url,omitempty" binding:"omitempty,max=500"`
+	Location         *string `json:"location,omitempty" binding:"omitempty,max=100"`
+	Phone            *string `json:"phone,omitempty" binding:"omitempty,max=32"`
+	ShowEmail        *bool   `json:"show_email,omitempty"`
+	ShowPhone        *bool   `json:"show_phone,omitempty"`
+	PreferredContact *string `json:"preferred_contact,omitempty" binding:"omitempty,oneof=email phone"`
+}
+
+// PublicProfile публичная страница продавца. Контакты заполняются, только если пользователь разрешил их показывать
+type PublicProfile struct {
+	ID               int       `json:"id" db:"id"`
+	Login            string    `json:"login" db:"login"`
+	DisplayName      string    `json:"display_name" db:"display_name"`
+	Bio              string    `json:"bio" db:"bio"`
+	AvatarURL        *string   `json:"avatar_url" db:"avatar_url"`
+	Location         string    `json:"location" db:"location"`
+	Email            string    `json:"email,omitempty" db:"email"`
+	Phone            string    `json:"phone,omitempty" db:"phone"`
+	PreferredContact string    `json:"preferred_contact,omitempty" db:"preferred_contact"`
+	ListingsCount    int       `json:"listings_count" db:"listings_count"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,129 @@
+package models
+
+import "time"
+
+// SecurityEventType тип события журнала безопасности
+type SecurityEventType string
+
+const (
+	SecurityEventLoginSucceeded         SecurityEventType = "login_succeeded"
+	SecurityEventLoginFailed            SecurityEventType = "login_failed"
+	SecurityEventRegistered             SecurityEventType = "registered"
+	SecurityEventPasswordChanged        SecurityEventType = "password_changed"
+	SecurityEventPasswordResetRequested SecurityEventType = "password_reset_requested"
+	SecurityEventPasswordReset          SecurityEventType = "password_reset"
+	SecurityEventLogout                 SecurityEventType = "logout"
+	SecurityEventLogoutAll              SecurityEventType = "logout_all"
+	SecurityEventSessionRevoked         SecurityEventType = "session_revoked"
+	SecurityEventRefreshTokenReused     SecurityEventType = "refresh_token_reused"
+	SecurityEventAPIKeyCreated          SecurityEventType = "api_key_created"
+	SecurityEventAPIKeyRevoked          SecurityEventType = "api_key_revoked"
+	SecurityEventRoleChanged            SecurityEventType = "role_changed"
+	SecurityEventUserUnlocked           SecurityEventType = "user_unlocked"
+	SecurityEventImpersonationStarted   SecurityEventType = "impersonation_started"
+	SecurityEventTwoFactorEnabled       SecurityEventType = "two_factor_enabled"
+	SecurityEventTwoFactorDisabled      SecurityEventType = "two_factor_disabled"
+	SecurityEventRecoveryCodeUsed       SecurityEventType = "recovery_code_used"
+	SecurityEventEmailChanged           SecurityEventType = "email_changed"
+	SecurityEventDeletionRequested      SecurityEventType = "account_deletion_requested"
+	SecurityEventDeletionCancelled      SecurityEventType = "account_deletion_cancelled"
+	SecurityEventAccountPurged          SecurityEventType = "account_purged"
+	SecurityEventCategoryCreated        SecurityEventType = "category_created"
+	SecurityEventCategoryUpdated        SecurityEventType = "category_updated"
+	SecurityEventCategoryDeleted        SecurityEventType = "category_deleted"
+)
+
+var securityEventTypes = map[SecurityEventType]struct{}{
+	SecurityEventLoginSucceeded:         {},
+	SecurityEventLoginFailed:            {},
+	SecurityEventRegistered:             {},
+	SecurityEventPasswordChanged:        {},
+	SecurityEventPasswordResetRequested: {},
+	SecurityEventPasswordReset:          {},
+	SecurityEventLogout:                 {},
+	SecurityEventLogoutAll:              {},
+	SecurityEventSessionRevoked:         {},
+	SecurityEventRefreshTokenReused:     {},
+	SecurityEventAPIKeyCreated:          {},
+	SecurityEventAPIKeyRevoked:          {},
+	SecurityEventRoleChanged:            {},
+	SecurityEventUserUnlocked:           {},
+	SecurityEventImpersonationStarted:   {},
+	SecurityEventTwoFactorEnabled:       {},
+	SecurityEventTwoFactorDisabled:      {},
+	SecurityEventRecoveryCodeUsed:       {},
+	SecurityEventEmailChanged:           {},
+	SecurityEventDeletionRequested:      {},
+	SecurityEventDeletionCancelled:      {},
+	SecurityEventAccountPurged:          {},
+	SecurityEventCategoryCreated:        {},
+	SecurityEventCategoryUpdated:        {},
+	SecurityEventCategoryDeleted:        {},
+}
+
+// Valid проверяет, что тип события известен
+func (t SecurityEventType) Valid() bool {
+	_, ok := securityEventTypes[t]
+	return ok
+}
+
+// SecurityEvent запись журнала безопасности
+type SecurityEvent struct {
+	ID   int64             `json:"id" db:"id"`
+	Type SecurityEventType `json:"type" db:"event_type"`
+	// UserID пользователь, к аккаунту которого относится событие; ноль при входе с неизвестным логином
+	UserID int `json:"user_id,omitempty" db:"user_id"`
+	// ActorID кто выполнил действие, если это не сам пользователь: администратор или вход от имени пользователя
+	ActorID int `json:"actor_id,omitempty" db:"actor_id"`
+	// Login логин из неудачной попытки входа, если пользователь не определен
+	Login     string `json:"login,omitempty" db:"login"`
+	IPAddress string `json:"ip_address" db:"ip_address"`
+	UserAgent string `json:"user_agent" db:"user_agent"`
+	RequestID string `json:"request_id,omitempty" db:"request_id"`
+	// Details подробности события, например причина неудачного входа или новая роль
+	Details   map[string]string `json:"details,omitempty" db:"details"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+}
+
+// SecurityEventsFilter фильтры журнала безопасности пользователя
+type SecurityEventsFilter struct {
+	Type  SecurityEventType `form:"type"`
+	From  *time.Time        `form:"from"`
+	To    *time.Time        `form:"to"`
+	Page  int               `form:"page" binding:"omitempty,min=1"`
+	Limit int               `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// SetDefaults устанавливает значения по умолчанию для фильтра
+func (f *SecurityEventsFilter) SetDefaults() {
+	if f.Page == 0 {
+		f.Page = 1
+	}
+	if f.Limit == 0 {
+		f.Limit = 20
+	}
+}
+
+// GetOffset возвращает offset для пагинации
+func (f *SecurityEventsFilter) GetOffset() int {
+	return (f.Page - 1) * f.Limit
+}
+
+// AdminSecurityEventsFilter фильтры поиска по журналу безопасности для администраторов
+type AdminSecurityEventsFilter struct {
+	SecurityEventsFilter
+	UserID    int    `form:"user_id" binding:"omitempty,min=1"`
+	ActorID   int    `form:"actor_id" binding:"omitempty,min=1"`
+	Login     string `form:"login" binding:"omitempty,max=255"`
+	IPAddress string `form:"ip_address" binding:"omitempty,ip"`
+	RequestID string `form:"request_id" binding:"omitempty,max=64"`
+}
+
+// PaginatedSecurityEvents результат с пагинацией
+type PaginatedSecurityEvents struct {
+	Data       []SecurityEvent `json:"data"`
+	Total      int             `json:"total"`
+	Page       int             `json:"page"`
+	Limit      int             `json:"limit"`
+	TotalPages int             `json:"total_pages"`
+}
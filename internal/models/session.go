@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Session сеанс входа на одном устройстве. Соответствует одному семейству ротаций refresh-токенов
+type Session struct {
+	ID         int        `json:"id" db:"id"`
+	UserID     int        `json:"-" db:"user_id"`
+	FamilyID   string     `json:"-" db:"family_id"`
+	UserAgent  string     `json:"user_agent" db:"user_agent"`
+	IPAddress  string     `json:"ip_address" db:"ip_address"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	LastSeenAt time.Time  `json:"last_seen_at" db:"last_seen_at"`
+	RevokedAt  *time.Time `json:"-" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	Current    bool       `json:"current"`
+}
+
+// ClientInfo данные клиента, с которого выполняется запрос
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	// RequestID идентификатор запроса для сопоставления журнала безопасности с логами
+	RequestID string
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// SigningKey ключ подписи JWT в том виде, в котором он хранится в базе данных
+type SigningKey struct {
+	ID          string     `db:"kid"`
+	Algorithm   string     `db:"algorithm"`
+	PrivateKey  string     `db:"private_key"` // PEM (PKCS #8), зашифрованный AES-256-GCM, в base64
+	ActivatesAt time.Time  `db:"activates_at"`
+	RetiresAt   *time.Time `db:"retires_at"`
+	ExpiresAt   *time.Time `db:"expires_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
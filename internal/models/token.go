@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// RefreshToken запись refresh-токена. Сам токен не хранится, только его хеш
+type RefreshToken struct {
+	ID        int        `db:"id"`
+	UserID    int        `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	FamilyID  string     `db:"family_id"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// RefreshRequest структура для запроса обновления токенов
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest структура для запроса выхода. Refresh-токен необязателен:
+// если он передан, отзывается и он
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
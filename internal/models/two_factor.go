@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// UserTOTP секрет TOTP пользователя. Пока EnabledAt пуст, регистрация не подтверждена и вход без второго фактора
+type UserTOTP struct {
+	UserID       int        `db:"user_id"`
+	Secret       string     `db:"secret"`
+	EnabledAt    *time.Time `db:"enabled_at"`
+	LastUsedStep *int64     `db:"last_used_step"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+// LoginChallenge незавершенный вход, ожидающий второго фактора. Сам токен не хранится, только его хеш
+type LoginChallenge struct {
+	ID        int        `db:"id"`
+	UserID    int        `db:"user_id"`
+	Attempts  int        `db:"attempts"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// TwoFactorEnrollment данные для добавления аккаунта в приложение-аутентификатор
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	QRCodePNG  []byte `json:"qr_code_png" swaggertype:"string" format:"base64"`
+}
+
+// TwoFactorCodeRequest структура с кодом из приложения-аутентификатора
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableTwoFactorRequest структура для отключения двухфакторной аутентификации
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// RecoveryCodesResponse одноразовые коды восстановления. Показываются один раз
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorChallenge токен незавершенного входа, который нужно подтвердить кодом
+type TwoFactorChallenge struct {
+	ChallengeToken string    `json:"challenge_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// LoginResponse ответ на вход по паролю: либо пара токенов, либо запрос второго фактора
+type LoginResponse struct {
+	*AuthResponse
+	TwoFactor *TwoFactorChallenge `json:"two_factor,omitempty"`
+}
+
+// LoginTwoFactorRequest структура для завершения входа кодом TOTP или кодом восстановления
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
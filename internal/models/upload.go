@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Upload загруженный пользователем файл. FileKey указывается в file_key изображений объявлений
+type Upload struct {
+	ID     int `json:"id" db:"id"`
+	UserID int `json:"-" db:"user_id"`
+	// ContentHash SHA-256 содержимого файла в hex
+	ContentHash string `json:"content_hash" db:"content_hash"`
+	FileKey     string `json:"file_key" db:"blob_key"`
+	ContentType string `json:"content_type" db:"content_type"`
+	// Size размер файла в байтах
+	Size      int64     `json:"size" db:"size"`
+	Width     *int      `json:"width,omitempty" db:"width"`
+	Height    *int      `json:"height,omitempty" db:"height"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	// URL подписанная ссылка на скачивание файла с ограниченным сроком действия
+	URL string `json:"url"`
+}
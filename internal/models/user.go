@@ -1,19 +1,81 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"marketplace-api/pkg/rbac"
+)
 
 type User struct {
-	ID           int       `json:"id" db:"id"`
-	Login        string    `json:"login" db:"login"`
-	PasswordHash string    `json:"-" db:"password_hash"` // "-" скрывает поле в JSON
+	ID               int        `json:"id" db:"id"`
+	Login            string     `json:"login" db:"login"`
+	Email            string     `json:"email,omitempty" db:"email"`
+	EmailVerifiedAt  *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
+	PasswordHash     string     `json:"-" db:"password_hash"` // "-" скрывает поле в JSON
+	Role             rbac.Role  `json:"role,omitempty" db:"role"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled,omitempty" db:"two_factor_enabled"`
+	// DeletionScheduledAt момент, после которого аккаунт будет удален; вход до этого момента отменяет удаление
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty" db:"deletion_scheduled_at"`
+	// DeletedAt момент удаления аккаунта. Строка удаленного пользователя остается обезличенной
+	DeletedAt *time.Time `json:"-" db:"deleted_at"`
+	// TokenVersion версия токенов пользователя; увеличивается при отзыве всех его токенов
+	TokenVersion int       `json:"-" db:"token_version"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// EmailVerified проверяет, подтвержден ли текущий email пользователя
+func (u *User) EmailVerified() bool {
+	return u.Email != "" && u.EmailVerifiedAt != nil
+}
+
+// Actor пользователь, от имени которого выполняется действие
+type Actor struct {
+	UserID int
+	Role   rbac.Role
+}
+
+// UpdateUserRoleRequest структура для изменения роли пользователя
+type UpdateUserRoleRequest struct {
+	Role rbac.Role `json:"role" binding:"required,oneof=user moderator admin"`
+}
+
+// UsersFilter параметры постраничного списка пользователей
+type UsersFilter struct {
+	Role  rbac.Role `form:"role" binding:"omitempty,oneof=user moderator admin"`
+	Page  int       `form:"page" binding:"omitempty,min=1"`
+	Limit int       `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// SetDefaults устанавливает значения по умолчанию для фильтра
+func (f *UsersFilter) SetDefaults() {
+	if f.Page == 0 {
+		f.Page = 1
+	}
+	if f.Limit == 0 {
+		f.Limit = 20
+	}
+}
+
+// GetOffset возвращает offset для пагинации
+func (f *UsersFilter) GetOffset() int {
+	return (f.Page - 1) * f.Limit
+}
+
+// PaginatedUsers результат с пагинацией
+type PaginatedUsers struct {
+	Data       []User `json:"data"`
+	Total      int    `json:"total"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	TotalPages int    `json:"total_pages"`
+}
+
 // RegisterRequest структура для запроса регистрации
 type RegisterRequest struct {
-	Login    string `json:"login" binding:"required,min=3,max=50"`
-	Password string `json:"password" binding:"required,min=6"`
+	Login    string `json:"login" binding:"required"`
+	Email    string `json:"email" binding:"omitempty,max=254"`
+	Password string `json:"password" binding:"required"`
 }
 
 // LoginRequest структура для запроса авторизации
@@ -24,6 +86,9 @@ type LoginRequest struct {
 
 // AuthResponse структура ответа при успешной авторизации/регистрации
 type AuthResponse struct {
-	User  User   `json:"user"`
-	Token string `json:"token"`
+	User                  User      `json:"user"`
+	Token                 string    `json:"token"`
+	TokenExpiresAt        time.Time `json:"token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
 }
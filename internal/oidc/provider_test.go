@@ -0,0 +1,150 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"marketplace-api/internal/config"
+	"marketplace-api/internal/oidc/oidctest"
+)
+
+const (
+	testClientID     = "marketplace"
+	testClientSecret = "secret"
+	testRedirectURL  = "http://localhost:8080/api/auth/oidc/callback"
+)
+
+func newTestProvider(t *testing.T) (*Provider, *oidctest.Issuer) {
+	t.Helper()
+
+	issuer, err := oidctest.NewIssuer(testClientID, testClientSecret)
+	require.NoError(t, err)
+	t.Cleanup(issuer.Close)
+
+	provider := NewProvider(config.OIDCConfig{
+		Issuer:       issuer.URL(),
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		Scopes:       []string{"email"},
+		RedirectURL:  testRedirectURL,
+	})
+
+	return provider, issuer
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	provider, issuer := newTestProvider(t)
+
+	verifier := oauth2.GenerateVerifier()
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", verifier)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+
+	assert.Equal(t, issuer.URL()+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, testClientID, query.Get("client_id"))
+	assert.Equal(t, testRedirectURL, query.Get("redirect_uri"))
+	assert.Equal(t, "state-1", query.Get("state"))
+	assert.Equal(t, "nonce-1", query.Get("nonce"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, oauth2.S256ChallengeFromVerifier(verifier), query.Get("code_challenge"))
+	// openid добавляется к настроенным правам
+	assert.Equal(t, "openid email", query.Get("scope"))
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	provider, issuer := newTestProvider(t)
+	issuer.SetUser(oidctest.User{
+		Subject:           "user-42",
+		Email:             "alice@example.com",
+		EmailVerified:     true,
+		PreferredUsername: "alice",
+		Name:              "Alice",
+	})
+
+	verifier := oauth2.GenerateVerifier()
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", verifier)
+	require.NoError(t, err)
+
+	code, state, err := issuer.Authorize(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "state-1", state)
+
+	identity, err := provider.Exchange(context.Background(), code, verifier, "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, &Identity{
+		Issuer:            issuer.URL(),
+		Subject:           "user-42",
+		Email:             "alice@example.com",
+		EmailVerified:     true,
+		PreferredUsername: "alice",
+		Name:              "Alice",
+	}, identity)
+}
+
+func TestProvider_Exchange_Rejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		verifier func(original string) string
+		nonce    string
+	}{
+		{
+			name:     "wrong code verifier",
+			verifier: func(string) string { return oauth2.GenerateVerifier() },
+			nonce:    "nonce-1",
+		},
+		{
+			name:     "nonce mismatch",
+			verifier: func(original string) string { return original },
+			nonce:    "other-nonce",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, issuer := newTestProvider(t)
+
+			verifier := oauth2.GenerateVerifier()
+			authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", verifier)
+			require.NoError(t, err)
+
+			code, _, err := issuer.Authorize(authURL)
+			require.NoError(t, err)
+
+			identity, err := provider.Exchange(context.Background(), code, tt.verifier(verifier), tt.nonce)
+			assert.Error(t, err)
+			assert.Nil(t, identity)
+		})
+	}
+}
+
+func TestProvider_Exchange_CodeIsSingleUse(t *testing.T) {
+	provider, issuer := newTestProvider(t)
+
+	verifier := oauth2.GenerateVerifier()
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", verifier)
+	require.NoError(t, err)
+
+	code, _, err := issuer.Authorize(authURL)
+	require.NoError(t, err)
+
+	_, err = provider.Exchange(context.Background(), code, verifier, "nonce-1")
+	require.NoError(t, err)
+
+	_, err = provider.Exchange(context.Background(), code, verifier, "nonce-1")
+	assert.Error(t, err)
+}
+
+func TestProvider_Unavailable(t *testing.T) {
+	provider, issuer := newTestProvider(t)
+	issuer.Close()
+
+	_, err := provider.AuthCodeURL(context.Background(), "state", "nonce", oauth2.GenerateVerifier())
+	assert.ErrorIs(t, err, ErrProviderUnavailable)
+}
@@ -0,0 +1,95 @@
+package policy
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// breachedPrefixLength длина префикса SHA-1, по которому группируются записи, как в k-anonymity API Pwned Passwords
+const breachedPrefixLength = 5
+
+//go:embed breached_passwords.txt
+var bundledBreachedPasswords []byte
+
+// BreachedList список утекших паролей в виде SHA-1 или их префиксов.
+// Записи сгруппированы по первым пяти символам хеша, поэтому проверка пароля просматривает только его группу
+type BreachedList struct {
+	buckets map[string][]string
+}
+
+// LoadBreachedList загружает список из файла path, а при пустом path — встроенный список распространенных паролей
+func LoadBreachedList(path string) (*BreachedList, error) {
+	if path == "" {
+		return ParseBreachedList(bytes.NewReader(bundledBreachedPasswords))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached passwords file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseBreachedList(file)
+}
+
+// ParseBreachedList читает список в формате выгрузки Pwned Passwords: по строке HASH[:COUNT].
+// HASH — SHA-1 в hex или его префикс не короче пяти символов; более короткий префикс совпадал бы со слишком
+// многими паролями. Пустые строки и строки, начинающиеся с #, пропускаются
+func ParseBreachedList(r io.Reader) (*BreachedList, error) {
+	list := &BreachedList{buckets: make(map[string][]string)}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(entry, ":")
+		hash = strings.ToUpper(hash)
+		if len(hash) < breachedPrefixLength || len(hash) > sha1.Size*2 {
+			return nil, fmt.Errorf("invalid breached password hash on line %d", line)
+		}
+		if _, err := hex.DecodeString(hash + strings.Repeat("0", len(hash)%2)); err != nil {
+			return nil, fmt.Errorf("invalid breached password hash on line %d", line)
+		}
+
+		prefix := hash[:breachedPrefixLength]
+		list.buckets[prefix] = append(list.buckets[prefix], hash[breachedPrefixLength:])
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read breached passwords: %w", err)
+	}
+
+	return list, nil
+}
+
+// Contains сообщает, что SHA-1 пароля есть в списке или начинается с одного из префиксов списка
+func (l *BreachedList) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	for _, suffix := range l.buckets[hash[:breachedPrefixLength]] {
+		if strings.HasPrefix(hash[breachedPrefixLength:], suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len возвращает количество записей в списке
+func (l *BreachedList) Len() int {
+	count := 0
+	for _, suffixes := range l.buckets {
+		count += len(suffixes)
+	}
+	return count
+}
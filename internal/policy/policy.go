@@ -0,0 +1,178 @@
+// Package policy проверяет логины и пароли новых пользователей на соответствие настраиваемым требованиям.
+//
+// Проверка возвращает все нарушенные правила сразу, чтобы клиент мог показать их вместе.
+// Правила, для которых нужна история пользователя (повтор прежних паролей), проверяет сервис аутентификации.
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"marketplace-api/internal/config"
+)
+
+// Проверяемые поля
+const (
+	FieldLogin    = "login"
+	FieldPassword = "password"
+)
+
+// Правила, которые может нарушить логин или пароль
+const (
+	RuleMinLength = "min_length"
+	RuleMaxLength = "max_length"
+	RulePattern   = "pattern"
+	RuleReserved  = "reserved"
+	RuleLower     = "lower"
+	RuleUpper     = "upper"
+	RuleLetter    = "letter"
+	RuleDigit     = "digit"
+	RuleSymbol    = "symbol"
+	RuleReused    = "reused"
+	RuleBreached  = "breached"
+)
+
+// deletedLoginPrefix префикс логинов удаленных пользователей (deleted-<id>), его нельзя занять при любом LOGIN_PATTERN
+const deletedLoginPrefix = "deleted-"
+
+// Violation нарушенное правило
+type Violation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// characterClasses проверки классов символов пароля и тексты нарушений
+var characterClasses = map[string]struct {
+	match   func(r rune) bool
+	message string
+}{
+	RuleLower:  {unicode.IsLower, "password must contain a lowercase letter"},
+	RuleUpper:  {unicode.IsUpper, "password must contain an uppercase letter"},
+	RuleLetter: {unicode.IsLetter, "password must contain a letter"},
+	RuleDigit:  {unicode.IsDigit, "password must contain a digit"},
+	RuleSymbol: {isSymbol, "password must contain a special character"},
+}
+
+// LoginPolicy требования к логину
+type LoginPolicy struct {
+	minLength int
+	maxLength int
+	pattern   *regexp.Regexp
+	reserved  map[string]struct{}
+}
+
+func NewLoginPolicy(cfg config.LoginPolicyConfig) (*LoginPolicy, error) {
+	pattern, err := regexp.Compile(cfg.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid login pattern: %w", err)
+	}
+
+	reserved := make(map[string]struct{}, len(cfg.Reserved))
+	for _, login := range cfg.Reserved {
+		reserved[strings.ToLower(login)] = struct{}{}
+	}
+
+	return &LoginPolicy{
+		minLength: cfg.MinLength,
+		maxLength: cfg.MaxLength,
+		pattern:   pattern,
+		reserved:  reserved,
+	}, nil
+}
+
+// Check возвращает нарушенные логином правила; пустой результат означает, что логин допустим
+func (p *LoginPolicy) Check(login string) []Violation {
+	var violations []Violation
+
+	length := utf8.RuneCountInString(login)
+	if length < p.minLength {
+		violations = append(violations, Violation{FieldLogin, RuleMinLength, fmt.Sprintf("login must be at least %d characters long", p.minLength)})
+	}
+	if length > p.maxLength {
+		violations = append(violations, Violation{FieldLogin, RuleMaxLength, fmt.Sprintf("login must be at most %d characters long", p.maxLength)})
+	}
+	if !p.pattern.MatchString(login) {
+		violations = append(violations, Violation{FieldLogin, RulePattern, "login contains characters that are not allowed"})
+	}
+	if p.IsReserved(login) {
+		violations = append(violations, Violation{FieldLogin, RuleReserved, "login is reserved"})
+	}
+
+	return violations
+}
+
+// IsReserved сообщает, что логин зарезервирован (без учета регистра)
+func (p *LoginPolicy) IsReserved(login string) bool {
+	login = strings.ToLower(login)
+	if strings.HasPrefix(login, deletedLoginPrefix) {
+		return true
+	}
+	_, ok := p.reserved[login]
+	return ok
+}
+
+// PasswordPolicy требования к паролю
+type PasswordPolicy struct {
+	minLength       int
+	maxLength       int
+	requiredClasses []string
+	history         int
+	breached        *BreachedList
+}
+
+// NewPasswordPolicy создает политику паролей. breached может быть nil, тогда проверка утечек отключена
+func NewPasswordPolicy(cfg config.PasswordPolicyConfig, breached *BreachedList) *PasswordPolicy {
+	return &PasswordPolicy{
+		minLength:       cfg.MinLength,
+		maxLength:       cfg.MaxLength,
+		requiredClasses: cfg.RequiredClasses,
+		history:         cfg.History,
+		breached:        breached,
+	}
+}
+
+// History возвращает, сколько последних паролей, включая текущий, нельзя использовать повторно
+func (p *PasswordPolicy) History() int {
+	return p.history
+}
+
+// Check возвращает нарушенные паролем правила; пустой результат означает, что пароль допустим
+func (p *PasswordPolicy) Check(password string) []Violation {
+	var violations []Violation
+
+	if utf8.RuneCountInString(password) < p.minLength {
+		violations = append(violations, Violation{FieldPassword, RuleMinLength, fmt.Sprintf("password must be at least %d characters long", p.minLength)})
+	}
+	if len(password) > p.maxLength {
+		violations = append(violations, Violation{FieldPassword, RuleMaxLength, fmt.Sprintf("password must be at most %d bytes long", p.maxLength)})
+	}
+
+	for _, rule := range p.requiredClasses {
+		class, ok := characterClasses[rule]
+		if ok && strings.IndexFunc(password, class.match) < 0 {
+			violations = append(violations, Violation{FieldPassword, rule, class.message})
+		}
+	}
+
+	if p.breached != nil && p.breached.Contains(password) {
+		violations = append(violations, Violation{FieldPassword, RuleBreached, "password has appeared in a data breach"})
+	}
+
+	return violations
+}
+
+// ReusedViolation нарушение правила о повторе одного из последних паролей
+func ReusedViolation(history int) Violation {
+	if history == 1 {
+		return Violation{FieldPassword, RuleReused, "password must differ from the current password"}
+	}
+	return Violation{FieldPassword, RuleReused, fmt.Sprintf("password must differ from the last %d passwords", history)}
+}
+
+func isSymbol(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r)
+}
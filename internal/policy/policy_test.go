@@ -0,0 +1,116 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"marketplace-api/internal/config"
+)
+
+func rules(violations []Violation) []string {
+	result := []string{}
+	for _, v := range violations {
+		result = append(result, v.Field+":"+v.Rule)
+	}
+	return result
+}
+
+func TestLoginPolicy_Check(t *testing.T) {
+	p, err := NewLoginPolicy(config.LoginPolicyConfig{
+		MinLength: 3,
+		MaxLength: 20,
+		Pattern:   "^[a-zA-Z0-9_]+$",
+		Reserved:  []string{"admin", "Support"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		login string
+		want  []string
+	}{
+		{login: "artificial00", want: []string{}},
+		{login: "ab", want: []string{"login:min_length"}},
+		{login: strings.Repeat("a", 21), want: []string{"login:max_length"}},
+		{login: "иван", want: []string{"login:pattern"}},
+		{login: "ADMIN", want: []string{"login:reserved"}},
+		{login: "support", want: []string{"login:reserved"}},
+		{login: "deleted-42", want: []string{"login:pattern", "login:reserved"}},
+		{login: "a b", want: []string{"login:pattern"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.login, func(t *testing.T) {
+			assert.Equal(t, tt.want, rules(p.Check(tt.login)))
+		})
+	}
+}
+
+func TestLoginPolicy_InvalidPattern(t *testing.T) {
+	_, err := NewLoginPolicy(config.LoginPolicyConfig{MinLength: 3, MaxLength: 50, Pattern: "^[a-z"})
+	assert.Error(t, err)
+}
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	breached, err := ParseBreachedList(strings.NewReader("# test\nB1B3773A05C0ED0176787A4F1574FF0075F7521E:3\n"))
+	require.NoError(t, err)
+
+	p := NewPasswordPolicy(config.PasswordPolicyConfig{
+		MinLength:       8,
+		MaxLength:       16,
+		RequiredClasses: []string{"lower", "upper", "digit", "symbol"},
+	}, breached)
+
+	tests := []struct {
+		name     string
+		password string
+		want     []string
+	}{
+		{name: "valid", password: "Sup3r-secret", want: []string{}},
+		{name: "too short", password: "Ab1!", want: []string{"password:min_length"}},
+		{name: "too long", password: "Sup3r-secret-long-one", want: []string{"password:max_length"}},
+		{name: "length in characters", password: "пароль-1й", want: []string{"password:upper"}},
+		{name: "missing classes", password: "lowercaseonly", want: []string{"password:upper", "password:digit", "password:symbol"}},
+		{name: "breached", password: "qwerty", want: []string{"password:min_length", "password:upper", "password:digit", "password:symbol", "password:breached"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rules(p.Check(tt.password)))
+		})
+	}
+}
+
+func TestBreachedList(t *testing.T) {
+	list, err := ParseBreachedList(strings.NewReader(strings.Join([]string{
+		"# SHA-1 of password1",
+		"e38ad214943daad1d64c102faec29de4afe9da3d",
+		"",
+		"7C4A8:13", // префикс SHA-1 от 123456
+	}, "\n")))
+	require.NoError(t, err)
+	assert.Equal(t, 2, list.Len())
+
+	assert.True(t, list.Contains("password1"))
+	assert.True(t, list.Contains("123456"))
+	assert.False(t, list.Contains("password2"))
+}
+
+func TestBreachedList_Invalid(t *testing.T) {
+	for _, content := range []string{"7C4A", "ZZZZZZ", strings.Repeat("A", 41)} {
+		_, err := ParseBreachedList(strings.NewReader(content))
+		assert.Error(t, err, content)
+	}
+}
+
+func TestLoadBreachedList_Bundled(t *testing.T) {
+	list, err := LoadBreachedList("")
+	require.NoError(t, err)
+
+	assert.Greater(t, list.Len(), 100)
+	assert.True(t, list.Contains("qwerty123"))
+	assert.True(t, list.Contains("password1"))
+	assert.False(t, list.Contains("correct-horse-battery-staple-42"))
+}
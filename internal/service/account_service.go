@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"marketplace-api/internal/config"
+	"marketplace-api/internal/database/postgres"
+	"marketplace-api/internal/mail"
+	"marketplace-api/internal/models"
+)
+
+// accountPurgeBatchSize количество аккаунтов, удаляемых за один запрос к базе
+const accountPurgeBatchSize = 100
+
+type AccountService struct {
+	userRepo     *postgres.UserRepository
+	accountRepo  *postgres.AccountRepository
+	listingRepo  *postgres.ListingRepository
+	sessionRepo  *postgres.SessionRepository
+	apiKeyRepo   *postgres.APIKeyRepository
+	identityRepo *postgres.IdentityRepository
+	profileRepo  *postgres.ProfileRepository
+	eventRepo    *postgres.SecurityEventRepository
+	uploads      *UploadService
+	authService  *AuthService
+	mailer       mail.Sender
+	authConfig   config.AuthConfig
+	log          *slog.Logger
+}
+
+func NewAccountService(
+	userRepo *postgres.UserRepository,
+	accountRepo *postgres.AccountRepository,
+	listingRepo *postgres.ListingRepository,
+	sessionRepo *postgres.SessionRepository,
+	apiKeyRepo *postgres.APIKeyRepository,
+	identityRepo *postgres.IdentityRepository,
+	profileRepo *postgres.ProfileRepository,
+	eventRepo *postgres.SecurityEventRepository,
+	uploads *UploadService,
+	authService *AuthService,
+	mailer mail.Sender,
+	authConfig config.AuthConfig,
+	log *slog.Logger,
+) *AccountService {
+	return &AccountService{
+		userRepo:     userRepo,
+		accountRepo:  accountRepo,
+		listingRepo:  listingRepo,
+		sessionRepo:  sessionRepo,
+		apiKeyRepo:   apiKeyRepo,
+		identityRepo: identityRepo,
+		profileRepo:  profileRepo,
+		eventRepo:    eventRepo,
+		uploads:      uploads,
+		authService:  authService,
+		mailer:       mailer,
+		authConfig:   authConfig,
+		log:          log,
+	}
+}
+
+type AccountServiceInterface interface {
+	ExportData(userID int) (*models.UserDataExport, error)
+	DeleteAccount(userID int, req models.DeleteAccountRequest, client models.ClientInfo) (*models.AccountDeletion, error)
+}
+
+// ExportData собирает персональные данные пользователя: учетную запись, профиль, объявления, сеансы,
+// API-ключи (без самих ключей), привязанные учетные записи провайдеров, загрузки и журнал безопасности
+func (s *AccountService) ExportData(userID int) (*models.UserDataExport, error) {
+	now := time.Now().UTC()
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	profile, err := s.profileRepo.GetProfile(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	listings, err := s.listingRepo.GetAllUserListings(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listings: %w", err)
+	}
+
+	sessions, err := s.sessionRepo.ListActiveSessions(userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	apiKeys, err := s.apiKeyRepo.ListAPIKeys(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+
+	identities, err := s.identityRepo.ListIdentities(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identities: %w", err)
+	}
+
+	uploads, err := s.uploads.ListUserUploads(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get uploads: %w", err)
+	}
+
+	events, err := s.eventRepo.GetAllUserEvents(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get security events: %w", err)
+	}
+
+	return &models.UserDataExport{
+		ExportedAt:     now,
+		Profile:        *user,
+		PublicProfile:  *profile,
+		Listings:       listings,
+		Sessions:       sessions,
+		APIKeys:        apiKeys,
+		Identities:     identities,
+		Uploads:        uploads,
+		SecurityEvents: events,
+	}, nil
+}
+
+// DeleteAccount назначает удаление аккаунта через AccountDeletionGracePeriod и завершает все сеансы.
+// До удаления объявления пользователя скрыты, а API-ключи не действуют; вход в аккаунт отменяет удаление.
+// Данные удаляет фоновая задача Run
+func (s *AccountService) DeleteAccount(userID int, req models.DeleteAccountRequest, client models.ClientInfo) (*models.AccountDeletion, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.PasswordHash != "" {
+		if _, err := s.authService.passwordHasher.Verify(req.Password, user.PasswordHash); err != nil {
+			return nil, fmt.Errorf("invalid password")
+		}
+	}
+
+	scheduledAt := time.Now().UTC().Add(s.authConfig.AccountDeletionGracePeriod)
+	user, err = s.userRepo.ScheduleDeletion(userID, scheduledAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule account deletion: %w", err)
+	}
+
+	if err := s.authService.revokeAllUserTokens(userID); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Account deletion scheduled", "user_id", userID, "deletion_scheduled_at", scheduledAt)
+	s.authService.securityEvents.Record(models.SecurityEvent{
+		Type:    models.SecurityEventDeletionRequested,
+		UserID:  userID,
+		Details: map[string]string{"deletion_scheduled_at": scheduledAt.Format(time.RFC3339)},
+	}, client)
+
+	if user.EmailVerified() {
+		err := s.mailer.Send(mail.Message{
+			To:      user.Email,
+			Subject: "Удаление аккаунта",
+			Body: fmt.Sprintf(
+				"Здравствуйте, %s!\n\nАккаунт и все ваши объявления будут удалены %s (UTC). Чтобы отменить удаление, просто войдите в аккаунт до этого момента.\n",
+				user.Login, scheduledAt.Format("02.01.2006 15:04"),
+			),
+		})
+		if err != nil {
+			s.log.Error("Failed to send account deletion email", "user_id", userID, "error", err)
+		}
+	}
+
+	return &models.AccountDeletion{DeletionScheduledAt: scheduledAt}, nil
+}
+
+// Run раз в AccountPurgeInterval удаляет данные аккаунтов, срок удаления которых наступил, до отмены ctx
+func (s *AccountService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.authConfig.AccountPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.purgeDue(ctx); err != nil {
+				s.log.Error("Failed to purge deleted accounts", "error", err)
+			}
+		}
+	}
+}
+
+// purgeDue удаляет данные всех аккаунтов с наступившим сроком удаления
+func (s *AccountService) purgeDue(ctx context.Context) error {
+	for ctx.Err() == nil {
+		now := time.Now().UTC()
+
+		ids, err := s.accountRepo.ListDueForPurge(now, accountPurgeBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			purged, err := s.accountRepo.PurgeUser(id, now, s.uploads.fileDeleter(ctx))
+			if err != nil {
+				return err
+			}
+			if purged {
+				s.log.Info("Account purged", "user_id", id)
+				s.authService.securityEvents.Record(models.SecurityEvent{Type: models.SecurityEventAccountPurged, UserID: id}, models.ClientInfo{})
+			}
+		}
+
+		if len(ids) < accountPurgeBatchSize {
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"fmt"
+
+	"marketplace-api/internal/database/postgres"
+	"marketplace-api/internal/models"
+)
+
+type AdminService struct {
+	userRepo        *postgres.UserRepository
+	revocationStore *TokenRevocationStore
+	loginThrottle   *LoginThrottle
+	securityEvents  *SecurityEventService
+}
+
+func NewAdminService(userRepo *postgres.UserRepository, revocationStore *TokenRevocationStore, loginThrottle *LoginThrottle, securityEvents *SecurityEventService) *AdminService {
+	return &AdminService{
+		userRepo:        userRepo,
+		revocationStore: revocationStore,
+		loginThrottle:   loginThrottle,
+		securityEvents:  securityEvents,
+	}
+}
+
+type AdminServiceInterface interface {
+	ListUsers(filter models.UsersFilter) (*models.PaginatedUsers, error)
+	UpdateUserRole(actor models.Actor, userID int, req models.UpdateUserRoleRequest, client models.ClientInfo) (*models.User, error)
+	UnlockUser(actor models.Actor, userID int, client models.ClientInfo) error
+}
+
+// ListUsers получает постраничный список пользователей
+func (s *AdminService) ListUsers(filter models.UsersFilter) (*models.PaginatedUsers, error) {
+	filter.SetDefaults()
+
+	users, err := s.userRepo.ListUsers(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return users, nil
+}
+
+// UpdateUserRole изменяет роль пользователя.
+// Выпущенные токены пользователя отзываются, чтобы новая роль применилась при следующем обновлении токена
+func (s *AdminService) UpdateUserRole(actor models.Actor, userID int, req models.UpdateUserRoleRequest, client models.ClientInfo) (*models.User, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+
+	if !req.Role.Valid() {
+		return nil, fmt.Errorf("invalid role")
+	}
+
+	if actor.UserID == userID {
+		return nil, fmt.Errorf("cannot change own role")
+	}
+
+	user, err := s.userRepo.UpdateUserRole(userID, req.Role)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to update user role: %w", err)
+	}
+
+	if err := s.revocationStore.RevokeAllForUser(userID); err != nil {
+		return nil, fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+
+	s.securityEvents.Record(models.SecurityEvent{
+		Type:    models.SecurityEventRoleChanged,
+		UserID:  userID,
+		ActorID: actor.UserID,
+		Details: map[string]string{"role": string(user.Role)},
+	}, client)
+
+	return user, nil
+}
+
+// UnlockUser снимает блокировку входа, наложенную после серии неудачных попыток
+func (s *AdminService) UnlockUser(actor models.Actor, userID int, client models.ClientInfo) error {
+	if userID <= 0 {
+		return fmt.Errorf("invalid user ID")
+	}
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return fmt.Errorf("user not found")
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := s.loginThrottle.Unlock(user.Login); err != nil {
+		return fmt.Errorf("failed to unlock user: %w", err)
+	}
+
+	s.securityEvents.Record(models.SecurityEvent{
+		Type:    models.SecurityEventUserUnlocked,
+		UserID:  userID,
+		ActorID: actor.UserID,
+	}, client)
+
+	return nil
+}
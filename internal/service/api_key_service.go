@@ -0,0 +1,149 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"marketplace-api/internal/database/postgres"
+	"marketplace-api/internal/models"
+	"marketplace-api/pkg/rbac"
+	"marketplace-api/pkg/utils"
+)
+
+type APIKeyService struct {
+	apiKeyRepo     *postgres.APIKeyRepository
+	securityEvents *SecurityEventService
+	log            *slog.Logger
+}
+
+func NewAPIKeyService(apiKeyRepo *postgres.APIKeyRepository, securityEvents *SecurityEventService, log *slog.Logger) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo:     apiKeyRepo,
+		securityEvents: securityEvents,
+		log:            log,
+	}
+}
+
+type APIKeyServiceInterface interface {
+	CreateAPIKey(userID int, req models.CreateAPIKeyRequest, client models.ClientInfo) (*models.CreatedAPIKey, error)
+	ListAPIKeys(userID int) ([]models.APIKey, error)
+	RevokeAPIKey(userID, keyID int, client models.ClientInfo) error
+}
+
+// CreateAPIKey создает API-ключ пользователя. Сам ключ возвращается только здесь, в базе хранится его хеш
+func (s *APIKeyService) CreateAPIKey(userID int, req models.CreateAPIKeyRequest, client models.ClientInfo) (*models.CreatedAPIKey, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("api key name is required")
+	}
+
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			return nil, fmt.Errorf("expiration must be in the future")
+		}
+		utc := req.ExpiresAt.UTC()
+		expiresAt = &utc
+	}
+
+	key, prefix, err := utils.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, err := s.apiKeyRepo.CreateAPIKey(userID, name, prefix, utils.HashToken(key), scopes, expiresAt, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	s.securityEvents.Record(models.SecurityEvent{
+		Type:    models.SecurityEventAPIKeyCreated,
+		UserID:  userID,
+		Details: map[string]string{"key_id": strconv.Itoa(apiKey.ID), "scopes": strings.Join(scopes, " ")},
+	}, client)
+
+	return &models.CreatedAPIKey{APIKey: *apiKey, Key: key}, nil
+}
+
+// ListAPIKeys возвращает API-ключи пользователя без самих ключей
+func (s *APIKeyService) ListAPIKeys(userID int) ([]models.APIKey, error) {
+	keys, err := s.apiKeyRepo.ListAPIKeys(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает API-ключ пользователя. Отозванный ключ перестает приниматься сразу
+func (s *APIKeyService) RevokeAPIKey(userID, keyID int, client models.ClientInfo) error {
+	if err := s.apiKeyRepo.DeleteAPIKey(userID, keyID); err != nil {
+		if err.Error() == "api key not found" {
+			return err
+		}
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	s.securityEvents.Record(models.SecurityEvent{
+		Type:    models.SecurityEventAPIKeyRevoked,
+		UserID:  userID,
+		Details: map[string]string{"key_id": strconv.Itoa(keyID)},
+	}, client)
+
+	return nil
+}
+
+// AuthenticateAPIKey проверяет API-ключ и возвращает данные владельца и scopes ключа.
+// Неизвестный, отозванный и просроченный ключ дают одну и ту же ошибку "invalid api key"
+func (s *APIKeyService) AuthenticateAPIKey(key string) (*utils.APIKeyClaims, error) {
+	now := time.Now().UTC()
+
+	owner, err := s.apiKeyRepo.GetActiveAPIKey(utils.HashToken(key), now)
+	if err != nil {
+		if err.Error() == "api key not found" {
+			return nil, fmt.Errorf("invalid api key")
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	if err := s.apiKeyRepo.TouchAPIKey(owner.ID, now); err != nil {
+		s.log.Warn("failed to record api key usage", "key_id", owner.ID, "error", err)
+	}
+
+	return &utils.APIKeyClaims{
+		KeyID:  owner.ID,
+		UserID: owner.UserID,
+		Login:  owner.Login,
+		Role:   owner.Role,
+		Scopes: owner.Scopes,
+	}, nil
+}
+
+// normalizeScopes проверяет scopes и убирает повторы, сохраняя порядок
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+
+	for _, scope := range scopes {
+		if !rbac.Scope(scope).Valid() {
+			return nil, fmt.Errorf("invalid api key scope")
+		}
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		result = append(result, scope)
+	}
+
+	return result, nil
+}
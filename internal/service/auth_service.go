@@ -1,40 +1,108 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
 
-	"golang.org/x/crypto/bcrypt"
+	"marketplace-api/internal/config"
 	"marketplace-api/internal/database/postgres"
+	"marketplace-api/internal/mail"
 	"marketplace-api/internal/models"
+	"marketplace-api/internal/policy"
 	"marketplace-api/pkg/utils"
 )
 
+// refreshTokenSize длина refresh-токена в байтах
+const refreshTokenSize = 32
+
+// passwordResetTokenSize длина токена сброса пароля в байтах
+const passwordResetTokenSize = 32
+
 type AuthService struct {
-	userRepo  *postgres.UserRepository
-	jwtSecret string
+	userRepo          *postgres.UserRepository
+	refreshTokenRepo  *postgres.RefreshTokenRepository
+	passwordResetRepo *postgres.PasswordResetRepository
+	passwordHistory   *postgres.PasswordHistoryRepository
+	emailService      *EmailService
+	loginThrottle     *LoginThrottle
+	twoFactorService  *TwoFactorService
+	sessionService    *SessionService
+	revocationStore   *TokenRevocationStore
+	securityEvents    *SecurityEventService
+	passwordHasher    *utils.PasswordHashers
+	loginPolicy       *policy.LoginPolicy
+	passwordPolicy    *policy.PasswordPolicy
+	keyRing           *utils.KeyRing
+	mailer            mail.Sender
+	jwtConfig         config.JWTConfig
+	authConfig        config.AuthConfig
+	log               *slog.Logger
 }
 
-func NewAuthService(userRepo *postgres.UserRepository, jwtSecret string) *AuthService {
+func NewAuthService(
+	userRepo *postgres.UserRepository,
+	refreshTokenRepo *postgres.RefreshTokenRepository,
+	passwordResetRepo *postgres.PasswordResetRepository,
+	passwordHistory *postgres.PasswordHistoryRepository,
+	emailService *EmailService,
+	loginThrottle *LoginThrottle,
+	twoFactorService *TwoFactorService,
+	sessionService *SessionService,
+	revocationStore *TokenRevocationStore,
+	securityEvents *SecurityEventService,
+	passwordHasher *utils.PasswordHashers,
+	loginPolicy *policy.LoginPolicy,
+	passwordPolicy *policy.PasswordPolicy,
+	keyRing *utils.KeyRing,
+	mailer mail.Sender,
+	jwtConfig config.JWTConfig,
+	authConfig config.AuthConfig,
+	log *slog.Logger,
+) *AuthService {
 	return &AuthService{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
+		userRepo:          userRepo,
+		refreshTokenRepo:  refreshTokenRepo,
+		passwordResetRepo: passwordResetRepo,
+		passwordHistory:   passwordHistory,
+		emailService:      emailService,
+		loginThrottle:     loginThrottle,
+		twoFactorService:  twoFactorService,
+		sessionService:    sessionService,
+		revocationStore:   revocationStore,
+		securityEvents:    securityEvents,
+		passwordHasher:    passwordHasher,
+		loginPolicy:       loginPolicy,
+		passwordPolicy:    passwordPolicy,
+		keyRing:           keyRing,
+		mailer:            mailer,
+		jwtConfig:         jwtConfig,
+		authConfig:        authConfig,
+		log:               log,
 	}
 }
 
 type AuthServiceInterface interface {
-	Register(req models.RegisterRequest) (*models.AuthResponse, error)
-	Login(req models.LoginRequest) (*models.AuthResponse, error)
+	Register(req models.RegisterRequest, client models.ClientInfo) (*models.AuthResponse, error)
+	Login(req models.LoginRequest, client models.ClientInfo) (*models.LoginResponse, error)
+	LoginTwoFactor(req models.LoginTwoFactorRequest, client models.ClientInfo) (*models.AuthResponse, error)
+	Refresh(req models.RefreshRequest, client models.ClientInfo) (*models.AuthResponse, error)
+	Logout(claims *utils.Claims, req models.LogoutRequest, client models.ClientInfo) error
+	LogoutAll(userID int, client models.ClientInfo) error
+	ChangePassword(userID int, req models.ChangePasswordRequest, client models.ClientInfo) (*models.AuthResponse, error)
+	ForgotPassword(req models.ForgotPasswordRequest, client models.ClientInfo) error
+	ResetPassword(req models.ResetPasswordRequest, client models.ClientInfo) error
 	GetUserByID(id int) (*models.User, error)
 }
 
-// Register регистрирует нового пользователя
-func (s *AuthService) Register(req models.RegisterRequest) (*models.AuthResponse, error) {
-	if !utils.ValidateLogin(req.Login) {
-		return nil, fmt.Errorf("invalid login format")
-	}
-
-	if !utils.ValidatePassword(req.Password) {
-		return nil, fmt.Errorf("password must be at least 6 characters long and contain letters and digits")
+// Register регистрирует нового пользователя.
+// Логин и пароль проверяются по политикам, нарушения обоих возвращаются вместе в PolicyError
+func (s *AuthService) Register(req models.RegisterRequest, client models.ClientInfo) (*models.AuthResponse, error) {
+	violations := append(s.loginPolicy.Check(req.Login), s.passwordPolicy.Check(req.Password)...)
+	if len(violations) > 0 {
+		return nil, &PolicyError{Violations: violations}
 	}
 
 	exists, err := s.userRepo.UserExists(req.Login)
@@ -45,52 +113,583 @@ func (s *AuthService) Register(req models.RegisterRequest) (*models.AuthResponse
 		return nil, fmt.Errorf("user already exists")
 	}
 
+	email := utils.NormalizeEmail(req.Email)
+	if email == "" && s.authConfig.EmailRequired {
+		return nil, fmt.Errorf("email is required")
+	}
+	if email != "" {
+		if err := s.emailService.checkEmailAvailable(email); err != nil {
+			return nil, err
+		}
+	}
+
 	// Хешируем пароль
-	passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	passwordHash, err := s.passwordHasher.Hash(req.Password)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash password: %w", err)
+		return nil, err
 	}
 
-	user, err := s.userRepo.CreateUser(req.Login, string(passwordHash))
+	user, err := s.userRepo.CreateUser(req.Login, email, passwordHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	token, err := utils.GenerateToken(user.ID, user.Login, s.jwtSecret)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+	s.securityEvents.Record(models.SecurityEvent{Type: models.SecurityEventRegistered, UserID: user.ID}, client)
+
+	if user.Email != "" {
+		// пользователь уже создан, письмо можно будет запросить повторно
+		if err := s.emailService.SendVerification(user); err != nil {
+			s.log.Error("Failed to send verification email", "user_id", user.ID, "error", err)
+		}
 	}
 
-	return &models.AuthResponse{
-		User:  *user,
-		Token: token,
-	}, nil
+	return s.issueTokens(user, "", client)
 }
 
-// Login авторизует пользователя
-func (s *AuthService) Login(req models.LoginRequest) (*models.AuthResponse, error) {
+// Login авторизует пользователя.
+// Попытки входа ограничиваются по логину и IP-адресу клиента, см. LoginThrottle.
+// Если у пользователя включен второй фактор, вместо токенов возвращается незавершенный вход,
+// который нужно подтвердить через LoginTwoFactor.
+// Хеш, созданный устаревшим алгоритмом или с устаревшими параметрами, пересчитывается после проверки пароля
+func (s *AuthService) Login(req models.LoginRequest, client models.ClientInfo) (*models.LoginResponse, error) {
+	if err := s.loginThrottle.Check(req.Login, client.IP); err != nil {
+		s.recordThrottledLogin(0, req.Login, err, client)
+		return nil, err
+	}
+
+	var needsRehash bool
 	user, err := s.userRepo.GetUserByLogin(req.Login)
+	if err == nil {
+		needsRehash, err = s.passwordHasher.Verify(req.Password, user.PasswordHash)
+	}
 	if err != nil {
+		if user != nil {
+			s.recordLoginFailure(user.ID, "", "invalid_password", client)
+		} else {
+			s.recordLoginFailure(0, req.Login, "unknown_login", client)
+		}
+		if err := s.loginThrottle.RecordFailure(req.Login, client.IP); err != nil {
+			return nil, fmt.Errorf("failed to record login failure: %w", err)
+		}
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password))
+	if needsRehash {
+		s.rehashPassword(user, req.Password)
+	}
+
+	// счетчик неудач не сбрасывается до ввода второго фактора,
+	// иначе знание пароля позволило бы перебирать коды без блокировки
+	if !user.TwoFactorEnabled {
+		if err := s.loginThrottle.RecordSuccess(req.Login, client.IP); err != nil {
+			return nil, fmt.Errorf("failed to reset login attempts: %w", err)
+		}
+	}
+
+	return s.loginUser(user, client, "password")
+}
+
+// LoginTwoFactor завершает вход кодом TOTP или кодом восстановления.
+// Неверные коды учитываются в LoginThrottle так же, как неверные пароли
+func (s *AuthService) LoginTwoFactor(req models.LoginTwoFactorRequest, client models.ClientInfo) (*models.AuthResponse, error) {
+	challenge, err := s.twoFactorService.AttemptChallenge(req.ChallengeToken)
 	if err != nil {
-		return nil, fmt.Errorf("invalid credentials")
+		return nil, err
 	}
 
-	token, err := utils.GenerateToken(user.ID, user.Login, s.jwtSecret)
+	user, err := s.userRepo.GetUserByID(challenge.UserID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	return &models.AuthResponse{
-		User:  *user,
-		Token: token,
-	}, nil
+	if err := s.loginThrottle.Check(user.Login, client.IP); err != nil {
+		s.recordThrottledLogin(user.ID, "", err, client)
+		return nil, err
+	}
+
+	ok, err := s.twoFactorService.VerifyCode(user.ID, req.Code, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify two-factor code: %w", err)
+	}
+	if !ok {
+		s.recordLoginFailure(user.ID, "", "invalid_two_factor_code", client)
+		if err := s.loginThrottle.RecordFailure(user.Login, client.IP); err != nil {
+			return nil, fmt.Errorf("failed to record login failure: %w", err)
+		}
+		return nil, fmt.Errorf("invalid two-factor code")
+	}
+
+	if err := s.twoFactorService.CompleteChallenge(challenge); err != nil {
+		return nil, err
+	}
+
+	if err := s.loginThrottle.RecordSuccess(user.Login, client.IP); err != nil {
+		return nil, fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+
+	response, err := s.issueTokens(user, "", client)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordLogin(user.ID, "two_factor", client)
+
+	return response, nil
+}
+
+// Refresh обменивает refresh-токен на новую пару токенов.
+// Каждый refresh-токен одноразовый: повторное предъявление уже использованного
+// токена считается кражей, и все семейство токенов отзывается
+func (s *AuthService) Refresh(req models.RefreshRequest, client models.ClientInfo) (*models.AuthResponse, error) {
+	token, err := s.refreshTokenRepo.GetRefreshTokenByHash(utils.HashToken(req.RefreshToken))
+	if err != nil {
+		if err.Error() == "refresh token not found" {
+			return nil, fmt.Errorf("invalid refresh token")
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if token.RevokedAt != nil {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	if token.UsedAt != nil {
+		return nil, s.handleRefreshTokenReuse(token, client)
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	marked, err := s.refreshTokenRepo.MarkRefreshTokenUsed(token.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if !marked {
+		// токен успели использовать параллельным запросом
+		return nil, s.handleRefreshTokenReuse(token, client)
+	}
+
+	user, err := s.userRepo.GetUserByID(token.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return s.issueTokens(user, token.FamilyID, client)
+}
+
+// Logout отзывает текущий access-токен и завершает его сеанс.
+// Если передан refresh-токен, отзывается и он
+func (s *AuthService) Logout(claims *utils.Claims, req models.LogoutRequest, client models.ClientInfo) error {
+	if err := s.revocationStore.Revoke(claims); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	// токен входа от имени пользователя привязан к сеансу администратора, сеанс не завершается
+	if claims.SessionID != 0 && !claims.IsImpersonation() {
+		err := s.sessionService.revoke(claims.UserID, claims.SessionID)
+		if err != nil && err.Error() != "session not found" {
+			return err
+		}
+	}
+
+	s.securityEvents.Record(models.SecurityEvent{
+		Type:    models.SecurityEventLogout,
+		UserID:  claims.UserID,
+		ActorID: claims.ImpersonatorID,
+	}, client)
+
+	if req.RefreshToken == "" {
+		return nil
+	}
+
+	token, err := s.refreshTokenRepo.GetRefreshTokenByHash(utils.HashToken(req.RefreshToken))
+	if err != nil {
+		if err.Error() == "refresh token not found" {
+			return nil
+		}
+		return fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if token.UserID != claims.UserID {
+		return nil
+	}
+
+	if err := s.refreshTokenRepo.RevokeFamily(token.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// LogoutAll завершает все сеансы пользователя
+func (s *AuthService) LogoutAll(userID int, client models.ClientInfo) error {
+	if err := s.revokeAllUserTokens(userID); err != nil {
+		return err
+	}
+
+	s.securityEvents.Record(models.SecurityEvent{Type: models.SecurityEventLogoutAll, UserID: userID}, client)
+
+	return nil
+}
+
+// ChangePassword меняет пароль после проверки текущего.
+// Все прежние сеансы завершаются, вызывающему выдается новая пара токенов
+func (s *AuthService) ChangePassword(userID int, req models.ChangePasswordRequest, client models.ClientInfo) (*models.AuthResponse, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if _, err := s.passwordHasher.Verify(req.CurrentPassword, user.PasswordHash); err != nil {
+		return nil, fmt.Errorf("invalid current password")
+	}
+
+	if err := s.setPassword(user.ID, req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	s.securityEvents.Record(models.SecurityEvent{Type: models.SecurityEventPasswordChanged, UserID: user.ID}, client)
+
+	// отзыв токенов увеличил их версию; новая пара выпускается уже с ней
+	user, err = s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return s.issueTokens(user, "", client)
+}
+
+// ForgotPassword отправляет письмо со ссылкой для сброса пароля на подтвержденный email.
+// Для неизвестного логина и аккаунта без подтвержденного email ошибка не возвращается,
+// чтобы по ответу нельзя было проверить существование аккаунта. По той же причине повторный запрос
+// раньше PasswordResetInterval молча пропускается, а ограничение по IP-адресу не зависит от логина
+func (s *AuthService) ForgotPassword(req models.ForgotPasswordRequest, client models.ClientInfo) error {
+	if err := s.loginThrottle.RecordPasswordReset(client.IP); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetUserByLogin(req.Login)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if !user.EmailVerified() {
+		s.log.Info("Password reset requested for user without verified email", "user_id", user.ID)
+		return nil
+	}
+
+	age, err := s.passwordResetRepo.GetLastSentAge(user.ID)
+	if err != nil {
+		return err
+	}
+	if age != nil && *age < s.authConfig.PasswordResetInterval {
+		s.log.Info("Password reset requested too soon after the previous one", "user_id", user.ID)
+		return nil
+	}
+
+	token, err := utils.GenerateOpaqueToken(passwordResetTokenSize)
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	expiresAt := time.Now().UTC().Add(s.authConfig.PasswordResetTTL)
+	if err := s.passwordResetRepo.CreateResetToken(user.ID, utils.HashToken(token), expiresAt); err != nil {
+		return fmt.Errorf("failed to store reset token: %w", err)
+	}
+
+	s.securityEvents.Record(models.SecurityEvent{Type: models.SecurityEventPasswordResetRequested, UserID: user.ID}, client)
+
+	link := s.authConfig.PasswordResetURL + "?token=" + url.QueryEscape(token)
+	err = s.mailer.Send(mail.Message{
+		To:      user.Email,
+		Subject: "Сброс пароля",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nЧтобы задать новый пароль, перейдите по ссылке:\n%s\n\nСсылка действует %s. Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо.\n",
+			user.Login, link, s.authConfig.PasswordResetTTL,
+		),
+	})
+	if err != nil {
+		s.log.Error("Failed to send password reset email", "user_id", user.ID, "error", err)
+	}
+
+	return nil
+}
+
+// ResetPassword устанавливает новый пароль по одноразовому токену сброса и завершает все сеансы.
+// Токен гасится только после проверки нового пароля, поэтому отклоненный пароль не расходует ссылку
+func (s *AuthService) ResetPassword(req models.ResetPasswordRequest, client models.ClientInfo) error {
+	tokenHash := utils.HashToken(req.Token)
+	now := time.Now().UTC()
+
+	token, err := s.passwordResetRepo.GetResetToken(tokenHash, now)
+	if err != nil {
+		if err.Error() == "reset token not found" {
+			return fmt.Errorf("invalid or expired reset token")
+		}
+		return fmt.Errorf("failed to get reset token: %w", err)
+	}
+
+	if err := s.checkNewPassword(token.UserID, req.NewPassword); err != nil {
+		return err
+	}
+
+	// тот же токен мог быть использован параллельным запросом, пока проверялся пароль
+	if _, err := s.passwordResetRepo.ConsumeResetToken(tokenHash, now); err != nil {
+		if err.Error() == "reset token not found" {
+			return fmt.Errorf("invalid or expired reset token")
+		}
+		return fmt.Errorf("failed to consume reset token: %w", err)
+	}
+
+	if err := s.savePassword(token.UserID, req.NewPassword); err != nil {
+		return err
+	}
+
+	s.securityEvents.Record(models.SecurityEvent{Type: models.SecurityEventPasswordReset, UserID: token.UserID}, client)
+
+	return nil
 }
 
 // GetUserByID получает пользователя по ID
 func (s *AuthService) GetUserByID(id int) (*models.User, error) {
 	return s.userRepo.GetUserByID(id)
 }
+
+// issueTokens выпускает access-токен и refresh-токен.
+// Пустой familyID начинает новое семейство ротаций и новый сеанс, иначе продолжается сеанс семейства.
+// Новый вход отменяет назначенное удаление аккаунта
+func (s *AuthService) issueTokens(user *models.User, familyID string, client models.ClientInfo) (*models.AuthResponse, error) {
+	now := time.Now().UTC()
+	accessExpiresAt := now.Add(s.jwtConfig.AccessTokenTTL)
+	refreshExpiresAt := now.Add(s.jwtConfig.RefreshTokenTTL)
+
+	var session *models.Session
+	var err error
+	if familyID == "" {
+		familyID, err = utils.GenerateRandomID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate token family: %w", err)
+		}
+		session, err = s.sessionService.start(user.ID, familyID, client, refreshExpiresAt)
+		if err == nil && user.DeletionScheduledAt != nil {
+			err = s.cancelAccountDeletion(user, client)
+		}
+	} else {
+		session, err = s.sessionService.continueSession(familyID, client, refreshExpiresAt)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := utils.GenerateToken(s.keyRing, utils.Claims{
+		UserID:       user.ID,
+		Login:        user.Login,
+		Role:         string(user.Role),
+		SessionID:    session.ID,
+		TokenVersion: user.TokenVersion,
+	}, accessExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	refreshToken, err := utils.GenerateOpaqueToken(refreshTokenSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	_, err = s.refreshTokenRepo.CreateRefreshToken(user.ID, utils.HashToken(refreshToken), familyID, refreshExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return &models.AuthResponse{
+		User:                  *user,
+		Token:                 accessToken,
+		TokenExpiresAt:        accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// loginUser завершает вход пользователя, личность которого уже проверена способом method.
+// При включенном втором факторе вместо токенов возвращается незавершенный вход
+func (s *AuthService) loginUser(user *models.User, client models.ClientInfo, method string) (*models.LoginResponse, error) {
+	if user.TwoFactorEnabled {
+		challenge, err := s.twoFactorService.StartChallenge(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &models.LoginResponse{TwoFactor: challenge}, nil
+	}
+
+	response, err := s.issueTokens(user, "", client)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordLogin(user.ID, method, client)
+
+	return &models.LoginResponse{AuthResponse: response}, nil
+}
+
+// cancelAccountDeletion отменяет назначенное удаление аккаунта при входе пользователя
+func (s *AuthService) cancelAccountDeletion(user *models.User, client models.ClientInfo) error {
+	if err := s.userRepo.CancelDeletion(user.ID); err != nil {
+		return fmt.Errorf("failed to cancel account deletion: %w", err)
+	}
+
+	user.DeletionScheduledAt = nil
+	s.log.Info("Account deletion cancelled by login", "user_id", user.ID)
+	s.securityEvents.Record(models.SecurityEvent{Type: models.SecurityEventDeletionCancelled, UserID: user.ID}, client)
+
+	return nil
+}
+
+// handleRefreshTokenReuse отзывает семейство токенов при повторном использовании
+func (s *AuthService) handleRefreshTokenReuse(token *models.RefreshToken, client models.ClientInfo) error {
+	if err := s.refreshTokenRepo.RevokeFamily(token.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", err)
+	}
+
+	s.securityEvents.Record(models.SecurityEvent{Type: models.SecurityEventRefreshTokenReused, UserID: token.UserID}, client)
+
+	return fmt.Errorf("refresh token reuse detected")
+}
+
+// recordLogin записывает успешный вход в журнал безопасности
+func (s *AuthService) recordLogin(userID int, method string, client models.ClientInfo) {
+	s.securityEvents.Record(models.SecurityEvent{
+		Type:    models.SecurityEventLoginSucceeded,
+		UserID:  userID,
+		Details: map[string]string{"method": method},
+	}, client)
+}
+
+// recordLoginFailure записывает неудачный вход в журнал безопасности.
+// Если пользователь не определен, сохраняется логин, с которым пытались войти
+func (s *AuthService) recordLoginFailure(userID int, login, reason string, client models.ClientInfo) {
+	s.securityEvents.Record(models.SecurityEvent{
+		Type:    models.SecurityEventLoginFailed,
+		UserID:  userID,
+		Login:   login,
+		Details: map[string]string{"reason": reason},
+	}, client)
+}
+
+// recordThrottledLogin записывает попытку входа, отклоненную LoginThrottle
+func (s *AuthService) recordThrottledLogin(userID int, login string, err error, client models.ClientInfo) {
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		return
+	}
+
+	reason := "too_many_attempts"
+	if rateLimitErr.Message == "account locked" {
+		reason = "account_locked"
+	}
+	s.recordLoginFailure(userID, login, reason, client)
+}
+
+// revokeAllUserTokens отзывает все access- и refresh-токены пользователя.
+// Используется при выходе со всех устройств и при смене пароля
+func (s *AuthService) revokeAllUserTokens(userID int) error {
+	if err := s.revocationStore.RevokeAllForUser(userID); err != nil {
+		return fmt.Errorf("failed to revoke access tokens: %w", err)
+	}
+
+	if err := s.refreshTokenRepo.RevokeUserTokens(userID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	return s.sessionService.revokeAll(userID)
+}
+
+// setPassword проверяет новый пароль по политике и истории паролей, сохраняет его,
+// затем отзывает все токены пользователя
+func (s *AuthService) setPassword(userID int, password string) error {
+	if err := s.checkNewPassword(userID, password); err != nil {
+		return err
+	}
+
+	return s.savePassword(userID, password)
+}
+
+// checkNewPassword проверяет новый пароль по политике и истории паролей
+func (s *AuthService) checkNewPassword(userID int, password string) error {
+	if violations := s.passwordPolicy.Check(password); len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+
+	history := s.passwordPolicy.History()
+	if history > 0 {
+		reused, err := s.isRecentPassword(userID, password, history)
+		if err != nil {
+			return err
+		}
+		if reused {
+			return &PolicyError{Violations: []policy.Violation{policy.ReusedViolation(history)}}
+		}
+	}
+
+	return nil
+}
+
+// savePassword сохраняет уже проверенный пароль и отзывает все токены пользователя
+func (s *AuthService) savePassword(userID int, password string) error {
+	passwordHash, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	// текущий пароль тоже входит в history, поэтому прежних хранится на один меньше
+	keep := max(s.passwordPolicy.History()-1, 0)
+	if err := s.passwordHistory.ChangePassword(userID, passwordHash, keep, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	return s.revokeAllUserTokens(userID)
+}
+
+// isRecentPassword сообщает, совпадает ли пароль с текущим или одним из history-1 прежних
+func (s *AuthService) isRecentPassword(userID int, password string, history int) (bool, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	hashes := []string{user.PasswordHash}
+	if history > 1 {
+		previous, err := s.passwordHistory.ListRecent(userID, history-1)
+		if err != nil {
+			return false, err
+		}
+		hashes = append(hashes, previous...)
+	}
+
+	for _, hash := range hashes {
+		// хеши в неизвестном формате и пустые хеши просто не совпадают
+		if _, err := s.passwordHasher.Verify(password, hash); err == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// rehashPassword пересчитывает хеш уже проверенного пароля текущим алгоритмом.
+// Сеансы не отзываются: пароль не менялся. Ошибка не мешает входу, хеш пересчитается при следующем
+func (s *AuthService) rehashPassword(user *models.User, password string) {
+	passwordHash, err := s.passwordHasher.Hash(password)
+	if err == nil {
+		err = s.userRepo.UpdatePassword(user.ID, passwordHash)
+	}
+	if err != nil {
+		s.log.Error("Failed to rehash password", "user_id", user.ID, "error", err)
+		return
+	}
+
+	user.PasswordHash = passwordHash
+}
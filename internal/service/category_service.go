@@ -0,0 +1,215 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"marketplace-api/internal/database/postgres"
+	"marketplace-api/internal/models"
+)
+
+// categorySlugPattern slug категории: строчные латинские буквы и цифры, разделенные одиночными дефисами
+var categorySlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type CategoryService struct {
+	categoryRepo   *postgres.CategoryRepository
+	securityEvents *SecurityEventService
+}
+
+func NewCategoryService(categoryRepo *postgres.CategoryRepository, securityEvents *SecurityEventService) *CategoryService {
+	return &CategoryService{
+		categoryRepo:   categoryRepo,
+		securityEvents: securityEvents,
+	}
+}
+
+type CategoryServiceInterface interface {
+	GetCategoryTree() ([]models.Category, error)
+	CreateCategory(actor models.Actor, req models.CreateCategoryRequest, client models.ClientInfo) (*models.Category, error)
+	UpdateCategory(actor models.Actor, id int, req models.UpdateCategoryRequest, client models.ClientInfo) (*models.Category, error)
+	DeleteCategory(actor models.Actor, id int, client models.ClientInfo) error
+}
+
+// GetCategoryTree получает дерево категорий. Категории одного уровня упорядочены по sort_order и названию
+func (s *CategoryService) GetCategoryTree() ([]models.Category, error) {
+	categories, err := s.categoryRepo.GetAllCategories()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	byParent := make(map[int][]models.Category)
+	for _, category := range categories {
+		parentID := 0
+		if category.ParentID != nil {
+			parentID = *category.ParentID
+		}
+		byParent[parentID] = append(byParent[parentID], category)
+	}
+
+	tree := buildCategoryTree(byParent, 0)
+	if countCategories(tree) != len(categories) {
+		// Категории, не достижимые от верхнего уровня, образуют цикл. Переносы под блокировкой его не допускают,
+		// поэтому молча отбрасывать такие категории нельзя
+		return nil, fmt.Errorf("failed to get categories: category tree contains a cycle")
+	}
+
+	return tree, nil
+}
+
+// CreateCategory создает категорию верхнего уровня или подкатегорию
+func (s *CategoryService) CreateCategory(actor models.Actor, req models.CreateCategoryRequest, client models.ClientInfo) (*models.Category, error) {
+	if !categorySlugPattern.MatchString(req.Slug) {
+		return nil, fmt.Errorf("invalid category slug")
+	}
+
+	if req.ParentID != nil {
+		if _, err := s.categoryRepo.GetCategoryByID(*req.ParentID); err != nil {
+			if err.Error() == "category not found" {
+				return nil, fmt.Errorf("parent category not found")
+			}
+			return nil, fmt.Errorf("failed to get parent category: %w", err)
+		}
+	}
+
+	if err := s.checkSlugAvailable(req.Slug, 0); err != nil {
+		return nil, err
+	}
+
+	category, err := s.categoryRepo.CreateCategory(req, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+
+	s.recordCategoryEvent(models.SecurityEventCategoryCreated, actor, category.ID, category.Slug, client)
+
+	return category, nil
+}
+
+// UpdateCategory обновляет категорию. Категорию нельзя перенести в нее саму или в ее подкатегорию
+func (s *CategoryService) UpdateCategory(actor models.Actor, id int, req models.UpdateCategoryRequest, client models.ClientInfo) (*models.Category, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid category ID")
+	}
+
+	if req.Slug != nil && !categorySlugPattern.MatchString(*req.Slug) {
+		return nil, fmt.Errorf("invalid category slug")
+	}
+
+	if _, err := s.categoryRepo.GetCategoryByID(id); err != nil {
+		if err.Error() == "category not found" {
+			return nil, fmt.Errorf("category not found")
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	if req.ParentID != nil && *req.ParentID != 0 {
+		if _, err := s.categoryRepo.GetCategoryByID(*req.ParentID); err != nil {
+			if err.Error() == "category not found" {
+				return nil, fmt.Errorf("parent category not found")
+			}
+			return nil, fmt.Errorf("failed to get parent category: %w", err)
+		}
+	}
+
+	if req.Slug != nil {
+		if err := s.checkSlugAvailable(*req.Slug, id); err != nil {
+			return nil, err
+		}
+	}
+
+	category, err := s.categoryRepo.UpdateCategory(id, req, time.Now().UTC())
+	if err != nil {
+		switch err.Error() {
+		case "category not found", "no fields to update", "category cannot be moved into itself or its subcategory":
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update category: %w", err)
+	}
+
+	s.recordCategoryEvent(models.SecurityEventCategoryUpdated, actor, category.ID, category.Slug, client)
+
+	return category, nil
+}
+
+// DeleteCategory удаляет категорию без подкатегорий и объявлений
+func (s *CategoryService) DeleteCategory(actor models.Actor, id int, client models.ClientInfo) error {
+	if id <= 0 {
+		return fmt.Errorf("invalid category ID")
+	}
+
+	category, err := s.categoryRepo.GetCategoryByID(id)
+	if err != nil {
+		if err.Error() == "category not found" {
+			return err
+		}
+		return fmt.Errorf("failed to get category: %w", err)
+	}
+
+	hasChildren, hasListings, err := s.categoryRepo.GetCategoryUsage(id)
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	if hasChildren {
+		return fmt.Errorf("category has subcategories")
+	}
+	if hasListings {
+		return fmt.Errorf("category has listings")
+	}
+
+	if err := s.categoryRepo.DeleteCategory(id); err != nil {
+		if err.Error() == "category not found" {
+			return err
+		}
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	s.recordCategoryEvent(models.SecurityEventCategoryDeleted, actor, category.ID, category.Slug, client)
+
+	return nil
+}
+
+// recordCategoryEvent записывает изменение категории администратором в его журнал безопасности
+func (s *CategoryService) recordCategoryEvent(eventType models.SecurityEventType, actor models.Actor, categoryID int, slug string, client models.ClientInfo) {
+	s.securityEvents.Record(models.SecurityEvent{
+		Type:    eventType,
+		UserID:  actor.UserID,
+		Details: map[string]string{"category_id": strconv.Itoa(categoryID), "slug": slug},
+	}, client)
+}
+
+// checkSlugAvailable проверяет, что slug не занят другой категорией
+func (s *CategoryService) checkSlugAvailable(slug string, categoryID int) error {
+	existing, err := s.categoryRepo.GetCategoryBySlug(slug)
+	if err != nil {
+		if err.Error() == "category not found" {
+			return nil
+		}
+		return fmt.Errorf("failed to check category slug: %w", err)
+	}
+	if existing.ID != categoryID {
+		return fmt.Errorf("category slug already exists")
+	}
+	return nil
+}
+
+// buildCategoryTree собирает подкатегории parentID вместе со всеми их потомками
+func buildCategoryTree(byParent map[int][]models.Category, parentID int) []models.Category {
+	children := byParent[parentID]
+	tree := make([]models.Category, 0, len(children))
+	for _, category := range children {
+		category.Children = buildCategoryTree(byParent, category.ID)
+		tree = append(tree, category)
+	}
+	return tree
+}
+
+// countCategories считает категории дерева вместе со всеми потомками
+func countCategories(tree []models.Category) int {
+	count := len(tree)
+	for _, category := range tree {
+		count += countCategories(category.Children)
+	}
+	return count
+}
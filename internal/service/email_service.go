@@ -0,0 +1,185 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"marketplace-api/internal/config"
+	"marketplace-api/internal/database/postgres"
+	"marketplace-api/internal/mail"
+	"marketplace-api/internal/models"
+	"marketplace-api/pkg/utils"
+)
+
+// emailVerificationTokenSize длина токена подтверждения email в байтах
+const emailVerificationTokenSize = 32
+
+type EmailService struct {
+	userRepo         *postgres.UserRepository
+	verificationRepo *postgres.EmailVerificationRepository
+	passwordHasher   *utils.PasswordHashers
+	securityEvents   *SecurityEventService
+	mailer           mail.Sender
+	authConfig       config.AuthConfig
+}
+
+func NewEmailService(
+	userRepo *postgres.UserRepository,
+	verificationRepo *postgres.EmailVerificationRepository,
+	passwordHasher *utils.PasswordHashers,
+	securityEvents *SecurityEventService,
+	mailer mail.Sender,
+	authConfig config.AuthConfig,
+) *EmailService {
+	return &EmailService{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		passwordHasher:   passwordHasher,
+		securityEvents:   securityEvents,
+		mailer:           mailer,
+		authConfig:       authConfig,
+	}
+}
+
+type EmailServiceInterface interface {
+	ChangeEmail(userID int, req models.ChangeEmailRequest, client models.ClientInfo) (*models.User, error)
+	VerifyEmail(req models.VerifyEmailRequest) error
+	ResendVerification(userID int) error
+}
+
+// ChangeEmail устанавливает или меняет email после проверки пароля и отправляет письмо для его подтверждения.
+// До подтверждения новый email считается неподтвержденным
+func (s *EmailService) ChangeEmail(userID int, req models.ChangeEmailRequest, client models.ClientInfo) (*models.User, error) {
+	email := utils.NormalizeEmail(req.Email)
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if _, err := s.passwordHasher.Verify(req.Password, user.PasswordHash); err != nil {
+		return nil, fmt.Errorf("invalid current password")
+	}
+
+	if email == user.Email {
+		return user, nil
+	}
+
+	if err := s.checkResendInterval(user.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkEmailAvailable(email); err != nil {
+		return nil, err
+	}
+
+	user, err = s.userRepo.UpdateEmail(user.ID, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update email: %w", err)
+	}
+
+	s.securityEvents.Record(models.SecurityEvent{Type: models.SecurityEventEmailChanged, UserID: user.ID}, client)
+
+	if err := s.SendVerification(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// VerifyEmail подтверждает email по одноразовому токену из письма
+func (s *EmailService) VerifyEmail(req models.VerifyEmailRequest) error {
+	_, err := s.verificationRepo.VerifyEmail(utils.HashToken(req.Token), time.Now().UTC())
+	if err != nil {
+		if err.Error() == "verification token not found" {
+			return fmt.Errorf("invalid or expired verification token")
+		}
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	return nil
+}
+
+// ResendVerification повторно отправляет письмо подтверждения не чаще раза в EmailResendInterval
+func (s *EmailService) ResendVerification(userID int) error {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.Email == "" {
+		return fmt.Errorf("email is not set")
+	}
+	if user.EmailVerified() {
+		return fmt.Errorf("email already verified")
+	}
+
+	if err := s.checkResendInterval(user.ID); err != nil {
+		return err
+	}
+
+	return s.SendVerification(user)
+}
+
+// SendVerification выдает новый токен подтверждения и отправляет его на текущий email пользователя
+func (s *EmailService) SendVerification(user *models.User) error {
+	token, err := utils.GenerateOpaqueToken(emailVerificationTokenSize)
+	if err != nil {
+		return fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	expiresAt := time.Now().UTC().Add(s.authConfig.EmailVerificationTTL)
+	if err := s.verificationRepo.CreateVerificationToken(user.ID, user.Email, utils.HashToken(token), expiresAt); err != nil {
+		return fmt.Errorf("failed to store verification token: %w", err)
+	}
+
+	link := s.authConfig.EmailVerificationURL + "?token=" + url.QueryEscape(token)
+	err = s.mailer.Send(mail.Message{
+		To:      user.Email,
+		Subject: "Подтверждение email",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nЧтобы подтвердить адрес электронной почты, перейдите по ссылке:\n%s\n\nСсылка действует %s.\n",
+			user.Login, link, s.authConfig.EmailVerificationTTL,
+		),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	return nil
+}
+
+// checkEmailAvailable проверяет формат email и что он не занят другим пользователем
+func (s *EmailService) checkEmailAvailable(email string) error {
+	if !utils.ValidateEmail(email) {
+		return fmt.Errorf("invalid email format")
+	}
+
+	exists, err := s.userRepo.EmailExists(email)
+	if err != nil {
+		return fmt.Errorf("failed to check email existence: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("email already in use")
+	}
+
+	return nil
+}
+
+// checkResendInterval ограничивает частоту писем подтверждения одному пользователю
+func (s *EmailService) checkResendInterval(userID int) error {
+	age, err := s.verificationRepo.GetLastSentAge(userID)
+	if err != nil {
+		return err
+	}
+	if age == nil {
+		return nil
+	}
+
+	if wait := s.authConfig.EmailResendInterval - *age; wait > 0 {
+		return &RateLimitError{Message: "verification email recently sent", RetryAfter: wait}
+	}
+
+	return nil
+}
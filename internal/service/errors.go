@@ -0,0 +1,27 @@
+package service
+
+import (
+	"time"
+
+	"marketplace-api/internal/policy"
+)
+
+// RateLimitError ошибка превышения допустимой частоты запросов.
+// Error() возвращает текст, по которому обработчики различают ошибки, RetryAfter — когда можно повторить
+type RateLimitError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return e.Message
+}
+
+// PolicyError логин или пароль не соответствуют требованиям. Violations перечисляет все нарушенные правила
+type PolicyError struct {
+	Violations []policy.Violation
+}
+
+func (e *PolicyError) Error() string {
+	return "policy violation"
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"marketplace-api/internal/config"
+	"marketplace-api/internal/database/postgres"
+	"marketplace-api/internal/models"
+	"marketplace-api/pkg/rbac"
+	"marketplace-api/pkg/utils"
+)
+
+// maxImpersonatedPathLength длина пути запроса, которая помещается в журнал
+const maxImpersonatedPathLength = 2048
+
+type ImpersonationService struct {
+	userRepo          *postgres.UserRepository
+	impersonationRepo *postgres.ImpersonationRepository
+	keyRing           *utils.KeyRing
+	securityEvents    *SecurityEventService
+	authConfig        config.AuthConfig
+	log               *slog.Logger
+}
+
+func NewImpersonationService(
+	userRepo *postgres.UserRepository,
+	impersonationRepo *postgres.ImpersonationRepository,
+	keyRing *utils.KeyRing,
+	securityEvents *SecurityEventService,
+	authConfig config.AuthConfig,
+	log *slog.Logger,
+) *ImpersonationService {
+	return &ImpersonationService{
+		userRepo:          userRepo,
+		impersonationRepo: impersonationRepo,
+		keyRing:           keyRing,
+		securityEvents:    securityEvents,
+		authConfig:        authConfig,
+		log:               log,
+	}
+}
+
+type ImpersonationServiceInterface interface {
+	Impersonate(admin *utils.Claims, userID int, req models.ImpersonateRequest, client models.ClientInfo) (*models.ImpersonationResponse, error)
+	ListImpersonations(filter models.ImpersonationsFilter) (*models.PaginatedImpersonations, error)
+	GetImpersonation(id int) (*models.Impersonation, error)
+}
+
+// Impersonate выпускает администратору короткоживущий access-токен пользователя userID.
+// Токен привязан к сеансу администратора: выход администратора отзывает и его.
+// Refresh-токен не выдается, по истечении срока нужно войти от имени пользователя заново
+func (s *ImpersonationService) Impersonate(admin *utils.Claims, userID int, req models.ImpersonateRequest, client models.ClientInfo) (*models.ImpersonationResponse, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+
+	if admin.IsImpersonation() {
+		return nil, fmt.Errorf("nested impersonation is not allowed")
+	}
+
+	if admin.UserID == userID {
+		return nil, fmt.Errorf("cannot impersonate yourself")
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.DeletedAt != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	// токен с ролью администратора дал бы доступ к администрированию в обход журнала прав
+	if user.Role.Can(rbac.PermAdminAccess) {
+		return nil, fmt.Errorf("cannot impersonate an administrator")
+	}
+
+	client = normalizeClient(client)
+	now := time.Now().UTC()
+	expiresAt := now.Add(s.authConfig.ImpersonationTokenTTL)
+
+	token, err := utils.GenerateToken(s.keyRing, utils.Claims{
+		UserID:             user.ID,
+		Login:              user.Login,
+		Role:               string(user.Role),
+		SessionID:          admin.SessionID,
+		TokenVersion:       user.TokenVersion,
+		ImpersonatorID:     admin.UserID,
+		ImpersonationWrite: req.AllowWrite,
+	}, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	claims, err := utils.ValidateToken(token, s.keyRing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token id: %w", err)
+	}
+
+	imp, err := s.impersonationRepo.CreateImpersonation(&models.Impersonation{
+		AdminID:    admin.UserID,
+		UserID:     user.ID,
+		TokenID:    claims.ID,
+		Reason:     reason,
+		AllowWrite: req.AllowWrite,
+		IPAddress:  client.IP,
+		UserAgent:  client.UserAgent,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Impersonation started",
+		"impersonation_id", imp.ID,
+		"admin_id", admin.UserID,
+		"user_id", user.ID,
+		"allow_write", req.AllowWrite,
+	)
+	s.securityEvents.Record(models.SecurityEvent{
+		Type:    models.SecurityEventImpersonationStarted,
+		UserID:  user.ID,
+		ActorID: admin.UserID,
+		Details: map[string]string{
+			"impersonation_id": strconv.Itoa(imp.ID),
+			"allow_write":      strconv.FormatBool(req.AllowWrite),
+		},
+	}, client)
+
+	return &models.ImpersonationResponse{
+		ImpersonationID: imp.ID,
+		User:            *user,
+		Token:           token,
+		TokenExpiresAt:  expiresAt,
+		AllowWrite:      req.AllowWrite,
+	}, nil
+}
+
+// ListImpersonations получает постраничный журнал входов от имени пользователей
+func (s *ImpersonationService) ListImpersonations(filter models.ImpersonationsFilter) (*models.PaginatedImpersonations, error) {
+	filter.SetDefaults()
+
+	impersonations, err := s.impersonationRepo.ListImpersonations(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list impersonations: %w", err)
+	}
+
+	return impersonations, nil
+}
+
+// GetImpersonation получает запись о входе от имени пользователя вместе с выполненными запросами
+func (s *ImpersonationService) GetImpersonation(id int) (*models.Impersonation, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid impersonation ID")
+	}
+
+	imp, err := s.impersonationRepo.GetImpersonationByID(id)
+	if err != nil {
+		if err.Error() == "impersonation not found" {
+			return nil, fmt.Errorf("impersonation not found")
+		}
+		return nil, fmt.Errorf("failed to get impersonation: %w", err)
+	}
+
+	return imp, nil
+}
+
+// StartRequest записывает запрос, выполненный с токеном входа от имени пользователя.
+// Используется middleware.ImpersonationGuard
+func (s *ImpersonationService) StartRequest(claims *utils.Claims, method, path, ipAddress string, blocked bool) (int64, error) {
+	if runes := []rune(path); len(runes) > maxImpersonatedPathLength {
+		path = string(runes[:maxImpersonatedPathLength])
+	}
+	return s.impersonationRepo.StartRequest(claims.ID, method, path, ipAddress, blocked, time.Now().UTC())
+}
+
+// FinishRequest сохраняет код ответа на записанный запрос
+func (s *ImpersonationService) FinishRequest(requestID int64, status int) error {
+	return s.impersonationRepo.FinishRequest(requestID, status)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"marketplace-api/internal/config"
+	"marketplace-api/internal/database/postgres"
+	"marketplace-api/internal/models"
+	"marketplace-api/pkg/utils"
+)
+
+// KeyRotationService управляет асимметричными ключами подписи JWT.
+// Ключи хранятся в базе данных, поэтому все реплики подписывают и проверяют
+// токены одним набором. Новый ключ публикуется за KeyRefreshInterval до начала
+// использования, чтобы каждая реплика успела его загрузить. Закрытые ключи хранятся
+// зашифрованными ключом JWT_KEY_ENCRYPTION_KEY
+type KeyRotationService struct {
+	repo      *postgres.SigningKeyRepository
+	keyRing   *utils.KeyRing
+	jwtConfig config.JWTConfig
+	log       *slog.Logger
+}
+
+func NewKeyRotationService(repo *postgres.SigningKeyRepository, keyRing *utils.KeyRing, jwtConfig config.JWTConfig, log *slog.Logger) *KeyRotationService {
+	return &KeyRotationService{
+		repo:      repo,
+		keyRing:   keyRing,
+		jwtConfig: jwtConfig,
+		log:       log,
+	}
+}
+
+// Init создает первый ключ, если его еще нет, и загружает ключи в набор
+func (s *KeyRotationService) Init() error {
+	if _, err := s.rotate(true); err != nil {
+		return err
+	}
+	return s.Reload()
+}
+
+// Run периодически перечитывает ключи и выполняет плановую ротацию до отмены ctx
+func (s *KeyRotationService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.jwtConfig.KeyRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rotated, err := s.rotate(false)
+			if err != nil {
+				s.log.Error("Failed to rotate signing key", "error", err)
+			} else if rotated {
+				s.log.Info("Signing key rotated")
+			}
+
+			if err := s.Reload(); err != nil {
+				s.log.Error("Failed to reload signing keys", "error", err)
+			}
+		}
+	}
+}
+
+// Reload загружает действующие ключи из базы данных
+func (s *KeyRotationService) Reload() error {
+	records, err := s.repo.GetSigningKeys(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	keys := make([]*utils.SigningKey, 0, len(records))
+	for _, record := range records {
+		pemData, err := utils.DecryptPrivateKey(record.PrivateKey, record.ID, s.jwtConfig.KeyEncryptionKey)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt signing key %s: %w", record.ID, err)
+		}
+		privateKey, err := utils.DecodePrivateKeyPEM(pemData)
+		if err != nil {
+			return fmt.Errorf("failed to decode signing key %s: %w", record.ID, err)
+		}
+		keys = append(keys, &utils.SigningKey{
+			ID:          record.ID,
+			Algorithm:   record.Algorithm,
+			PrivateKey:  privateKey,
+			ActivatesAt: record.ActivatesAt,
+			RetiresAt:   record.RetiresAt,
+			ExpiresAt:   record.ExpiresAt,
+		})
+	}
+
+	s.keyRing.Replace(keys)
+	return nil
+}
+
+// rotate выпускает новый ключ, если текущего нет, он другого алгоритма или пора его сменить.
+// При первом запуске (initial) ключ начинает действовать сразу
+func (s *KeyRotationService) rotate(initial bool) (bool, error) {
+	now := time.Now().UTC()
+
+	due := func(current *models.SigningKey) bool {
+		if current == nil || current.Algorithm != s.jwtConfig.Algorithm {
+			return true
+		}
+		return now.Sub(current.ActivatesAt) >= s.jwtConfig.KeyRotationInterval
+	}
+
+	current, err := s.repo.GetCurrentSigningKey()
+	if err != nil && err.Error() != "signing key not found" {
+		return false, err
+	}
+	if !due(current) {
+		return false, nil
+	}
+
+	activatesAt := now.Add(s.jwtConfig.KeyRefreshInterval)
+	if initial && current == nil {
+		activatesAt = now
+	}
+
+	key, err := utils.GenerateSigningKey(s.jwtConfig.Algorithm, activatesAt)
+	if err != nil {
+		return false, err
+	}
+
+	pemData, err := utils.EncodePrivateKeyPEM(key.PrivateKey)
+	if err != nil {
+		return false, err
+	}
+
+	privateKey, err := utils.EncryptPrivateKey(pemData, key.ID, s.jwtConfig.KeyEncryptionKey)
+	if err != nil {
+		return false, err
+	}
+
+	record := models.SigningKey{
+		ID:          key.ID,
+		Algorithm:   key.Algorithm,
+		PrivateKey:  privateKey,
+		ActivatesAt: activatesAt,
+	}
+
+	return s.repo.RotateSigningKey(record, activatesAt.Add(s.jwtConfig.KeyGracePeriod), due)
+}
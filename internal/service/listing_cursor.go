@@ -0,0 +1,61 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"marketplace-api/internal/models"
+)
+
+// Курсор — это base64url(JSON позиции) и base64url(HMAC-SHA256) через точку.
+// Подпись не дает клиенту подставить в запрос произвольные значения ключа сортировки
+
+// encodeListingCursor сериализует и подписывает позицию в ленте объявлений
+func encodeListingCursor(position models.ListingCursor, secret string) (string, error) {
+	payload, err := json.Marshal(position)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signListingCursor(encoded, secret), nil
+}
+
+// decodeListingCursor проверяет подпись курсора и возвращает позицию в ленте
+func decodeListingCursor(cursor, secret string) (*models.ListingCursor, error) {
+	encoded, signature, ok := strings.Cut(cursor, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(signListingCursor(encoded, secret))) {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var position models.ListingCursor
+	if err := json.Unmarshal(payload, &position); err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	switch position.SortBy {
+	case "created_at", "price", models.SortByRelevance:
+	default:
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	if (position.SortDir != "asc" && position.SortDir != "desc") || position.ID <= 0 || position.Value == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &position, nil
+}
+
+func signListingCursor(encoded, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
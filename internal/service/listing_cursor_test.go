@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"marketplace-api/internal/config"
+	"marketplace-api/internal/models"
+)
+
+func TestListingCursor_RoundTrip(t *testing.T) {
+	position := models.ListingCursor{
+		SortBy:  "created_at",
+		SortDir: "desc",
+		Value:   "2025-07-21 20:28:29.123456",
+		ID:      42,
+		Before:  true,
+	}
+
+	cursor, err := encodeListingCursor(position, "secret")
+	require.NoError(t, err)
+
+	decoded, err := decodeListingCursor(cursor, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, position, *decoded)
+}
+
+func TestListingCursor_Invalid(t *testing.T) {
+	cursor, err := encodeListingCursor(models.ListingCursor{SortBy: "price", SortDir: "asc", Value: "150.00", ID: 3}, "secret")
+	require.NoError(t, err)
+
+	forged, err := encodeListingCursor(models.ListingCursor{SortBy: "price", SortDir: "asc", Value: "0", ID: 1}, "other")
+	require.NoError(t, err)
+
+	unknownSort, err := encodeListingCursor(models.ListingCursor{SortBy: "title", SortDir: "asc", Value: "a", ID: 1}, "secret")
+	require.NoError(t, err)
+
+	testTable := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "Wrong secret", cursor: forged},
+		{name: "Tampered payload", cursor: "x" + cursor},
+		{name: "No signature", cursor: cursor[:len(cursor)-44]},
+		{name: "Unknown sort", cursor: unknownSort},
+		{name: "Garbage", cursor: "not-a-cursor"},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := decodeListingCursor(testCase.cursor, "secret")
+			assert.EqualError(t, err, "invalid cursor")
+		})
+	}
+}
+
+func TestListingService_ApplyCursor(t *testing.T) {
+	s := NewListingService(nil, nil, nil, nil, config.ListingsConfig{CursorSecret: "secret"})
+
+	cursor, err := encodeListingCursor(models.ListingCursor{SortBy: "price", SortDir: "asc", Value: "150.00", ID: 3}, "secret")
+	require.NoError(t, err)
+
+	t.Run("Sort taken from cursor", func(t *testing.T) {
+		filter := models.ListingsFilter{Cursor: cursor}
+		require.NoError(t, s.applyCursor(&filter))
+		assert.Equal(t, "price", filter.SortBy)
+		assert.Equal(t, "asc", filter.SortDir)
+		require.NotNil(t, filter.Position)
+		assert.Equal(t, 3, filter.Position.ID)
+		assert.False(t, filter.CountTotal())
+		assert.Equal(t, 0, filter.GetOffset())
+	})
+
+	t.Run("Sort mismatch", func(t *testing.T) {
+		filter := models.ListingsFilter{Cursor: cursor, SortDir: "desc"}
+		assert.EqualError(t, s.applyCursor(&filter), "invalid cursor")
+	})
+
+	t.Run("Combined with page", func(t *testing.T) {
+		filter := models.ListingsFilter{Cursor: cursor, Page: 2}
+		assert.EqualError(t, s.applyCursor(&filter), "cursor cannot be combined with page")
+	})
+}
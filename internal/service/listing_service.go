@@ -2,19 +2,32 @@ package service
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"marketplace-api/internal/config"
 	"marketplace-api/internal/database/postgres"
 	"marketplace-api/internal/models"
+	"marketplace-api/pkg/rbac"
 	"marketplace-api/pkg/utils"
 )
 
 type ListingService struct {
-	listingRepo *postgres.ListingRepository
+	listingRepo  *postgres.ListingRepository
+	userRepo     *postgres.UserRepository
+	categoryRepo *postgres.CategoryRepository
+	// uploads проверяет принадлежность загруженных файлов и подписывает ссылки на них
+	uploads *UploadService
+	config  config.ListingsConfig
 }
 
-func NewListingService(listingRepo *postgres.ListingRepository) *ListingService {
+func NewListingService(listingRepo *postgres.ListingRepository, userRepo *postgres.UserRepository, categoryRepo *postgres.CategoryRepository, uploads *UploadService, config config.ListingsConfig) *ListingService {
 	return &ListingService{
-		listingRepo: listingRepo,
+		listingRepo:  listingRepo,
+		userRepo:     userRepo,
+		categoryRepo: categoryRepo,
+		uploads:      uploads,
+		config:       config,
 	}
 }
 
@@ -22,38 +35,96 @@ type ListingServiceInterface interface {
 	CreateListing(userID int, req models.CreateListingRequest) (*models.Listing, error)
 	GetListings(filter models.ListingsFilter, currentUserID *int) (*models.PaginatedListings, error)
 	GetListingByID(id int, currentUserID *int) (*models.Listing, error)
-	UpdateListing(id int, userID int, req models.UpdateListingRequest) (*models.Listing, error)
-	DeleteListing(id int, userID int) error
+	UpdateListing(id int, actor models.Actor, req models.UpdateListingRequest) (*models.Listing, error)
+	DeleteListing(id int, actor models.Actor) error
 	GetUserListings(userID int, filter models.ListingsFilter) (*models.PaginatedListings, error)
+	GetSellerListings(login string, filter models.ListingsFilter, currentUserID *int) (*models.PaginatedListings, error)
+	AddListingImage(listingID int, actor models.Actor, req models.ListingImageRequest) (*models.ListingImage, error)
+	DeleteListingImage(listingID, imageID int, actor models.Actor) error
+	ReorderListingImages(listingID int, actor models.Actor, req models.ReorderListingImagesRequest) ([]models.ListingImage, error)
 }
 
-// CreateListing создает новое объявление
+// CreateListing создает новое объявление.
+// Если включена политика RequireVerifiedEmail, публиковать могут только пользователи с подтвержденным email
 func (s *ListingService) CreateListing(userID int, req models.CreateListingRequest) (*models.Listing, error) {
 	if err := s.validateCreateListingRequest(req); err != nil {
 		return nil, err
 	}
 
+	if req.ImageURL != nil && *req.ImageURL != "" {
+		req.Images = []models.ListingImageRequest{{URL: req.ImageURL}}
+	}
+
+	if s.config.RequireVerifiedEmail {
+		user, err := s.userRepo.GetUserByID(userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if !user.EmailVerified() {
+			return nil, fmt.Errorf("email verification required")
+		}
+	}
+
+	if err := s.checkCategoryExists(req.CategoryID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkImageFiles(userID, req.Images); err != nil {
+		return nil, err
+	}
+
 	listing, err := s.listingRepo.CreateListing(userID, req)
 	if err != nil {
+		// загрузку могли удалить между проверкой и сохранением объявления
+		if err.Error() == "image file not found" {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to create listing: %w", err)
 	}
 
+	if err := s.signImageURLs(listing); err != nil {
+		return nil, err
+	}
+
 	return listing, nil
 }
 
 // GetListings получает список объявлений с фильтрацией
 func (s *ListingService) GetListings(filter models.ListingsFilter, currentUserID *int) (*models.PaginatedListings, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	if err := s.applyCursor(&filter); err != nil {
+		return nil, err
+	}
 	filter.SetDefaults()
 
 	if err := s.validateListingsFilter(filter); err != nil {
 		return nil, err
 	}
 
+	if filter.Category != "" {
+		if _, err := s.categoryRepo.GetCategoryBySlug(filter.Category); err != nil {
+			if err.Error() == "category not found" {
+				return nil, fmt.Errorf("category not found")
+			}
+			return nil, fmt.Errorf("failed to get category: %w", err)
+		}
+	}
+
 	listings, err := s.listingRepo.GetListings(filter, currentUserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get listings: %w", err)
 	}
 
+	if err := s.signCursors(listings); err != nil {
+		return nil, err
+	}
+
+	for i := range listings.Data {
+		if err := s.signImageURLs(&listings.Data[i]); err != nil {
+			return nil, err
+		}
+	}
+
 	return listings, nil
 }
 
@@ -71,11 +142,16 @@ func (s *ListingService) GetListingByID(id int, currentUserID *int) (*models.Lis
 		return nil, fmt.Errorf("failed to get listing: %w", err)
 	}
 
+	if err := s.signImageURLs(listing); err != nil {
+		return nil, err
+	}
+
 	return listing, nil
 }
 
-// UpdateListing обновляет объявление
-func (s *ListingService) UpdateListing(id, userID int, req models.UpdateListingRequest) (*models.Listing, error) {
+// UpdateListing обновляет объявление. Владелец может редактировать свое объявление,
+// а роли с правом listings:update:any — любое
+func (s *ListingService) UpdateListing(id int, actor models.Actor, req models.UpdateListingRequest) (*models.Listing, error) {
 	if id <= 0 {
 		return nil, fmt.Errorf("invalid listing ID")
 	}
@@ -84,33 +160,73 @@ func (s *ListingService) UpdateListing(id, userID int, req models.UpdateListingR
 		return nil, err
 	}
 
-	listing, err := s.listingRepo.UpdateListing(id, userID, req)
+	// image_url заменяет все изображения одним, а пустая строка удаляет их
+	if req.ImageURL != nil {
+		images := []models.ListingImageRequest{}
+		if *req.ImageURL != "" {
+			images = append(images, models.ListingImageRequest{URL: req.ImageURL})
+		}
+		req.Images = &images
+	}
+
+	ownerID, err := s.checkCanEditListing(id, actor)
 	if err != nil {
-		if err.Error() == "listing not found" {
-			return nil, fmt.Errorf("listing not found")
+		return nil, err
+	}
+
+	if req.Images != nil {
+		if err := s.checkImageFiles(ownerID, *req.Images); err != nil {
+			return nil, err
 		}
-		if err.Error() == "access denied: not owner" {
-			return nil, fmt.Errorf("access denied: you can only edit your own listings")
+	}
+
+	if req.CategoryID != nil {
+		if err := s.checkCategoryExists(*req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	listing, err := s.listingRepo.UpdateListing(id, req)
+	if err != nil {
+		switch err.Error() {
+		case "listing not found", "image file not found":
+			return nil, err
 		}
 		return nil, fmt.Errorf("failed to update listing: %w", err)
 	}
 
+	if err := s.signImageURLs(listing); err != nil {
+		return nil, err
+	}
+
+	listing.IsOwner = listing.UserID == actor.UserID
+
 	return listing, nil
 }
 
-// DeleteListing удаляет объявление
-func (s *ListingService) DeleteListing(id, userID int) error {
+// DeleteListing удаляет объявление. Владелец может удалить свое объявление,
+// а роли с правом listings:delete:any — любое
+func (s *ListingService) DeleteListing(id int, actor models.Actor) error {
 	if id <= 0 {
 		return fmt.Errorf("invalid listing ID")
 	}
 
-	err := s.listingRepo.DeleteListing(id, userID)
+	ownerID, err := s.listingRepo.GetListingOwnerID(id)
 	if err != nil {
 		if err.Error() == "listing not found" {
 			return fmt.Errorf("listing not found")
 		}
-		if err.Error() == "access denied: not owner" {
-			return fmt.Errorf("access denied: you can only delete your own listings")
+		return fmt.Errorf("failed to delete listing: %w", err)
+	}
+
+	if !actor.Role.CanModifyOwned(ownerID == actor.UserID, rbac.PermListingsDeleteAny) {
+		return fmt.Errorf("access denied: you can only delete your own listings")
+	}
+
+	err = s.listingRepo.DeleteListing(id)
+	if err != nil {
+		if err.Error() == "listing not found" {
+			return fmt.Errorf("listing not found")
 		}
 		return fmt.Errorf("failed to delete listing: %w", err)
 	}
@@ -124,6 +240,12 @@ func (s *ListingService) GetUserListings(userID int, filter models.ListingsFilte
 		return nil, fmt.Errorf("invalid user ID")
 	}
 
+	// поиск и фасеты по своим объявлениям не поддерживаются
+	filter.Query = ""
+	filter.Facets = ""
+	if err := s.applyCursor(&filter); err != nil {
+		return nil, err
+	}
 	filter.SetDefaults()
 
 	if err := s.validateListingsFilter(filter); err != nil {
@@ -135,9 +257,246 @@ func (s *ListingService) GetUserListings(userID int, filter models.ListingsFilte
 		return nil, fmt.Errorf("failed to get user listings: %w", err)
 	}
 
+	if err := s.signCursors(listings); err != nil {
+		return nil, err
+	}
+
+	for i := range listings.Data {
+		if err := s.signImageURLs(&listings.Data[i]); err != nil {
+			return nil, err
+		}
+	}
+
 	return listings, nil
 }
 
+// GetSellerListings получает объявления продавца по его логину для публичной страницы продавца
+func (s *ListingService) GetSellerListings(login string, filter models.ListingsFilter, currentUserID *int) (*models.PaginatedListings, error) {
+	seller, err := s.userRepo.GetUserByLogin(login)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if seller.DeletionScheduledAt != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	filter.SellerID = seller.ID
+
+	return s.GetListings(filter, currentUserID)
+}
+
+// AddListingImage добавляет изображение в конец списка изображений объявления
+func (s *ListingService) AddListingImage(listingID int, actor models.Actor, req models.ListingImageRequest) (*models.ListingImage, error) {
+	if listingID <= 0 {
+		return nil, fmt.Errorf("invalid listing ID")
+	}
+
+	if err := validateImage(req); err != nil {
+		return nil, err
+	}
+
+	ownerID, err := s.checkCanEditListing(listingID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkImageFiles(ownerID, []models.ListingImageRequest{req}); err != nil {
+		return nil, err
+	}
+
+	image, err := s.listingRepo.AddListingImage(listingID, req, s.config.MaxImages, time.Now().UTC())
+	if err != nil {
+		switch err.Error() {
+		case "listing not found", "too many images", "image file not found":
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to add listing image: %w", err)
+	}
+
+	if err := s.signImageURL(image); err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+// DeleteListingImage удаляет изображение объявления. Следующее изображение становится обложкой, если удалена она
+func (s *ListingService) DeleteListingImage(listingID, imageID int, actor models.Actor) error {
+	if listingID <= 0 {
+		return fmt.Errorf("invalid listing ID")
+	}
+	if imageID <= 0 {
+		return fmt.Errorf("invalid image ID")
+	}
+
+	if _, err := s.checkCanEditListing(listingID, actor); err != nil {
+		return err
+	}
+
+	if err := s.listingRepo.DeleteListingImage(listingID, imageID, time.Now().UTC()); err != nil {
+		switch err.Error() {
+		case "listing not found", "image not found":
+			return err
+		}
+		return fmt.Errorf("failed to delete listing image: %w", err)
+	}
+
+	return nil
+}
+
+// ReorderListingImages меняет порядок изображений объявления; первое изображение становится обложкой
+func (s *ListingService) ReorderListingImages(listingID int, actor models.Actor, req models.ReorderListingImagesRequest) ([]models.ListingImage, error) {
+	if listingID <= 0 {
+		return nil, fmt.Errorf("invalid listing ID")
+	}
+
+	if _, err := s.checkCanEditListing(listingID, actor); err != nil {
+		return nil, err
+	}
+
+	images, err := s.listingRepo.ReorderListingImages(listingID, req.ImageIDs, time.Now().UTC())
+	if err != nil {
+		switch err.Error() {
+		case "listing not found", "image_ids must list every image of the listing exactly once":
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to reorder listing images: %w", err)
+	}
+
+	for i := range images {
+		if err := s.signImageURL(&images[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return images, nil
+}
+
+// checkCanEditListing проверяет, что объявление существует и актор может его редактировать.
+// Возвращает ID владельца объявления
+func (s *ListingService) checkCanEditListing(listingID int, actor models.Actor) (int, error) {
+	ownerID, err := s.listingRepo.GetListingOwnerID(listingID)
+	if err != nil {
+		if err.Error() == "listing not found" {
+			return 0, fmt.Errorf("listing not found")
+		}
+		return 0, fmt.Errorf("failed to get listing owner: %w", err)
+	}
+
+	if !actor.Role.CanModifyOwned(ownerID == actor.UserID, rbac.PermListingsUpdateAny) {
+		return 0, fmt.Errorf("access denied: you can only edit your own listings")
+	}
+
+	return ownerID, nil
+}
+
+// checkImageFiles проверяет, что file_key изображений указывают на файлы, загруженные владельцем объявления
+func (s *ListingService) checkImageFiles(ownerID int, images []models.ListingImageRequest) error {
+	var fileKeys []string
+	for _, image := range images {
+		if image.FileKey != nil {
+			fileKeys = append(fileKeys, *image.FileKey)
+		}
+	}
+	if len(fileKeys) == 0 {
+		return nil
+	}
+	if s.uploads == nil {
+		return fmt.Errorf("image file not found")
+	}
+
+	return s.uploads.CheckFileKeys(ownerID, fileKeys)
+}
+
+// signImageURLs заполняет подписанные ссылки изображений объявления, заданных загруженными файлами
+func (s *ListingService) signImageURLs(listing *models.Listing) error {
+	if listing.Cover != nil {
+		if err := s.signImageURL(listing.Cover); err != nil {
+			return err
+		}
+		listing.ImageURL = listing.Cover.URL
+	}
+	for i := range listing.Images {
+		if err := s.signImageURL(&listing.Images[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// signImageURL заполняет url изображения подписанной ссылкой на загруженный файл
+func (s *ListingService) signImageURL(image *models.ListingImage) error {
+	if image.FileKey == nil || s.uploads == nil {
+		return nil
+	}
+
+	url, err := s.uploads.FileURL(*image.FileKey)
+	if err != nil {
+		return err
+	}
+	image.URL = &url
+	return nil
+}
+
+// applyCursor расшифровывает курсор фильтра в позицию. Сортировка берется из курсора,
+// а явно переданные sort_by и sort_dir должны с ней совпадать
+func (s *ListingService) applyCursor(filter *models.ListingsFilter) error {
+	if filter.Cursor == "" {
+		return nil
+	}
+	if filter.Page != 0 {
+		return fmt.Errorf("cursor cannot be combined with page")
+	}
+
+	position, err := decodeListingCursor(filter.Cursor, s.config.CursorSecret)
+	if err != nil {
+		return err
+	}
+	if (filter.SortBy != "" && filter.SortBy != position.SortBy) || (filter.SortDir != "" && filter.SortDir != position.SortDir) {
+		return fmt.Errorf("invalid cursor")
+	}
+
+	filter.SortBy = position.SortBy
+	filter.SortDir = position.SortDir
+	filter.Position = position
+	return nil
+}
+
+// signCursors подписывает позиции соседних страниц в next_cursor и prev_cursor
+func (s *ListingService) signCursors(listings *models.PaginatedListings) error {
+	var err error
+	if listings.NextPosition != nil {
+		if listings.NextCursor, err = encodeListingCursor(*listings.NextPosition, s.config.CursorSecret); err != nil {
+			return err
+		}
+	}
+	if listings.PrevPosition != nil {
+		if listings.PrevCursor, err = encodeListingCursor(*listings.PrevPosition, s.config.CursorSecret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCategoryExists проверяет, что категория объявления существует
+func (s *ListingService) checkCategoryExists(categoryID int) error {
+	if categoryID <= 0 {
+		return fmt.Errorf("category is required")
+	}
+
+	if _, err := s.categoryRepo.GetCategoryByID(categoryID); err != nil {
+		if err.Error() == "category not found" {
+			return fmt.Errorf("category not found")
+		}
+		return fmt.Errorf("failed to get category: %w", err)
+	}
+
+	return nil
+}
+
 // validateCreateListingRequest валидирует запрос на создание объявления
 func (s *ListingService) validateCreateListingRequest(req models.CreateListingRequest) error {
 	if req.Title == "" {
@@ -153,6 +512,9 @@ func (s *ListingService) validateCreateListingRequest(req models.CreateListingRe
 		return fmt.Errorf("price must be greater than 0")
 	}
 	if req.ImageURL != nil && *req.ImageURL != "" {
+		if len(req.Images) > 0 {
+			return fmt.Errorf("image_url cannot be combined with images")
+		}
 		if !utils.ValidateURL(*req.ImageURL) {
 			return fmt.Errorf("invalid image URL format")
 		}
@@ -160,7 +522,7 @@ func (s *ListingService) validateCreateListingRequest(req models.CreateListingRe
 			return fmt.Errorf("image URL must be less than 500 characters")
 		}
 	}
-	return nil
+	return s.validateImages(req.Images)
 }
 
 // validateUpdateListingRequest валидирует запрос на обновление объявления
@@ -179,6 +541,9 @@ func (s *ListingService) validateUpdateListingRequest(req models.UpdateListingRe
 	if req.Price != nil && *req.Price <= 0 {
 		return fmt.Errorf("price must be greater than 0")
 	}
+	if req.ImageURL != nil && req.Images != nil {
+		return fmt.Errorf("image_url cannot be combined with images")
+	}
 	if req.ImageURL != nil && *req.ImageURL != "" {
 		if !utils.ValidateURL(*req.ImageURL) {
 			return fmt.Errorf("invalid image URL format")
@@ -187,6 +552,47 @@ func (s *ListingService) validateUpdateListingRequest(req models.UpdateListingRe
 			return fmt.Errorf("image URL must be less than 500 characters")
 		}
 	}
+	if req.Images != nil {
+		return s.validateImages(*req.Images)
+	}
+	return nil
+}
+
+// validateImages проверяет количество изображений объявления и каждое из них
+func (s *ListingService) validateImages(images []models.ListingImageRequest) error {
+	if len(images) > s.config.MaxImages {
+		return fmt.Errorf("too many images")
+	}
+	for _, image := range images {
+		if err := validateImage(image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateImage проверяет изображение: задан ровно один источник, URL корректен
+func validateImage(image models.ListingImageRequest) error {
+	if (image.URL == nil) == (image.FileKey == nil) {
+		return fmt.Errorf("image must have either url or file_key")
+	}
+	if image.URL != nil {
+		if !utils.ValidateURL(*image.URL) {
+			return fmt.Errorf("invalid image URL format")
+		}
+		if len(*image.URL) > 500 {
+			return fmt.Errorf("image URL must be less than 500 characters")
+		}
+	}
+	if image.FileKey != nil && (*image.FileKey == "" || len(*image.FileKey) > 255) {
+		return fmt.Errorf("invalid image file_key")
+	}
+	if len(image.AltText) > 255 {
+		return fmt.Errorf("alt text must be less than 255 characters")
+	}
+	if (image.Width != nil && *image.Width <= 0) || (image.Height != nil && *image.Height <= 0) {
+		return fmt.Errorf("image width and height must be positive")
+	}
 	return nil
 }
 
@@ -201,6 +607,16 @@ func (s *ListingService) validateListingsFilter(filter models.ListingsFilter) er
 	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
 		return fmt.Errorf("min_price cannot be greater than max_price")
 	}
+	if filter.SortBy == models.SortByRelevance && filter.Query == "" {
+		return fmt.Errorf("sort_by=relevance requires q")
+	}
+	for _, name := range filter.FacetNames() {
+		switch name {
+		case models.FacetCategory, models.FacetPrice, models.FacetSeller, models.FacetCreatedAt:
+		default:
+			return fmt.Errorf("facets must be a comma-separated list of category, price, seller, created_at")
+		}
+	}
 	if filter.Page < 1 {
 		return fmt.Errorf("page must be greater than 0")
 	}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"marketplace-api/internal/config"
+	"marketplace-api/internal/models"
+)
+
+// loginThrottleCleanupInterval период удаления устаревших счетчиков попыток входа
+const loginThrottleCleanupInterval = time.Hour
+
+// LoginAttemptStore хранилище счетчиков неудачных попыток входа.
+// Хранилище общее для всех реплик API; реализация по умолчанию — postgres.LoginAttemptRepository
+type LoginAttemptStore interface {
+	GetLoginAttempt(scope, key string) (*models.LoginAttempt, error)
+	RecordLoginFailure(scope, key string, now, windowStart time.Time) (*models.LoginAttempt, error)
+	LockLoginAttempt(scope, key string, until time.Time) error
+	ResetLoginAttempt(scope, key string) error
+	DeleteStaleLoginAttempts(before, now time.Time) error
+	RememberLoginIP(login, clientIP string, now time.Time) error
+	IsKnownLoginIP(login, clientIP string, since time.Time) (bool, error)
+	DeleteStaleLoginIPs(before time.Time) error
+}
+
+// LoginThrottle ограничивает попытки входа по логину и по IP-адресу клиента:
+// после нескольких неудач вводит растущую задержку между попытками, а затем временно блокирует вход.
+// Ограничения по логину не действуют для адресов, с которых в аккаунт уже успешно входили,
+// чтобы перебор с чужих адресов не лишал владельца доступа
+type LoginThrottle struct {
+	store  LoginAttemptStore
+	config config.LoginThrottleConfig
+	log    *slog.Logger
+}
+
+func NewLoginThrottle(store LoginAttemptStore, config config.LoginThrottleConfig, log *slog.Logger) *LoginThrottle {
+	return &LoginThrottle{
+		store:  store,
+		config: config,
+		log:    log,
+	}
+}
+
+// Check проверяет, разрешена ли сейчас попытка входа.
+// Возвращает *RateLimitError: "account locked" при блокировке логина,
+// "too many login attempts" при блокировке IP или слишком частых попытках.
+// Блокировка и задержка по логину пропускаются для известного адреса владельца
+func (t *LoginThrottle) Check(login, clientIP string) error {
+	now := time.Now().UTC()
+
+	ipAttempt, err := t.get(models.LoginAttemptScopeIP, clientIP)
+	if err != nil {
+		return err
+	}
+	if wait := lockedFor(ipAttempt, now); wait > 0 {
+		return &RateLimitError{Message: "too many login attempts", RetryAfter: wait}
+	}
+
+	loginAttempt, err := t.get(models.LoginAttemptScopeLogin, login)
+	if err != nil {
+		return err
+	}
+	if lockedFor(loginAttempt, now) > 0 || t.delayFor(loginAttempt, now) > 0 {
+		known, err := t.store.IsKnownLoginIP(login, clientIP, now.Add(-t.config.KnownIPTTL))
+		if err != nil {
+			return fmt.Errorf("failed to check known login address: %w", err)
+		}
+		if known {
+			loginAttempt = nil
+		}
+	}
+	if wait := lockedFor(loginAttempt, now); wait > 0 {
+		return &RateLimitError{Message: "account locked", RetryAfter: wait}
+	}
+
+	wait := t.delayFor(ipAttempt, now)
+	if loginWait := t.delayFor(loginAttempt, now); loginWait > wait {
+		wait = loginWait
+	}
+	if wait > 0 {
+		return &RateLimitError{Message: "too many login attempts", RetryAfter: wait}
+	}
+
+	return nil
+}
+
+// RecordFailure учитывает неудачную попытку входа и при превышении порога блокирует логин или IP
+func (t *LoginThrottle) RecordFailure(login, clientIP string) error {
+	if err := t.recordFailure(models.LoginAttemptScopeLogin, login, t.config.MaxFailures); err != nil {
+		return err
+	}
+	return t.recordFailure(models.LoginAttemptScopeIP, clientIP, t.config.MaxFailuresPerIP)
+}
+
+// RecordSuccess сбрасывает счетчик неудач логина после успешного входа и запоминает адрес клиента как известный.
+// Счетчик IP не сбрасывается, чтобы вход в свой аккаунт не снимал ограничения перебора чужих
+func (t *LoginThrottle) RecordSuccess(login, clientIP string) error {
+	if err := t.store.RememberLoginIP(login, clientIP, time.Now().UTC()); err != nil {
+		return err
+	}
+	return t.store.ResetLoginAttempt(models.LoginAttemptScopeLogin, login)
+}
+
+// RecordPasswordReset учитывает запрос сброса пароля с IP-адреса клиента.
+// Возвращает *RateLimitError "too many password reset requests", если за FailureWindow
+// с этого адреса уже было PasswordResetsPerIP запросов; отклоненные запросы не продлевают ограничение
+func (t *LoginThrottle) RecordPasswordReset(clientIP string) error {
+	now := time.Now().UTC()
+
+	attempt, err := t.get(models.LoginAttemptScopePasswordReset, clientIP)
+	if err != nil {
+		return err
+	}
+	if attempt != nil && attempt.Failures >= t.config.PasswordResetsPerIP {
+		if wait := attempt.LastFailureAt.Add(t.config.FailureWindow).Sub(now); wait > 0 {
+			return &RateLimitError{Message: "too many password reset requests", RetryAfter: wait}
+		}
+	}
+
+	if _, err := t.store.RecordLoginFailure(models.LoginAttemptScopePasswordReset, clientIP, now, now.Add(-t.config.FailureWindow)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Unlock снимает блокировку входа для логина
+func (t *LoginThrottle) Unlock(login string) error {
+	return t.store.ResetLoginAttempt(models.LoginAttemptScopeLogin, login)
+}
+
+// Run периодически удаляет устаревшие счетчики до отмены ctx
+func (t *LoginThrottle) Run(ctx context.Context) {
+	ticker := time.NewTicker(loginThrottleCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now().UTC()
+			if err := t.store.DeleteStaleLoginAttempts(now.Add(-t.config.FailureWindow), now); err != nil {
+				t.log.Error("Failed to delete stale login attempts", "error", err)
+			}
+			if err := t.store.DeleteStaleLoginIPs(now.Add(-t.config.KnownIPTTL)); err != nil {
+				t.log.Error("Failed to delete stale known login addresses", "error", err)
+			}
+		}
+	}
+}
+
+func (t *LoginThrottle) get(scope, key string) (*models.LoginAttempt, error) {
+	attempt, err := t.store.GetLoginAttempt(scope, key)
+	if err != nil {
+		if err.Error() == "login attempt not found" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get login attempts: %w", err)
+	}
+	return attempt, nil
+}
+
+func (t *LoginThrottle) recordFailure(scope, key string, maxFailures int) error {
+	now := time.Now().UTC()
+
+	attempt, err := t.store.RecordLoginFailure(scope, key, now, now.Add(-t.config.FailureWindow))
+	if err != nil {
+		return err
+	}
+
+	if attempt.Failures < maxFailures || lockedFor(attempt, now) > 0 {
+		return nil
+	}
+
+	if err := t.store.LockLoginAttempt(scope, key, now.Add(t.config.LockoutDuration)); err != nil {
+		return err
+	}
+
+	t.log.Warn("Login locked after repeated failures", "scope", scope, "key", key, "failures", attempt.Failures)
+	return nil
+}
+
+// delayFor возвращает, сколько еще нужно ждать до следующей попытки.
+// Задержка удваивается с каждой неудачей после DelayAfterFailures и не превышает DelayMax
+func (t *LoginThrottle) delayFor(attempt *models.LoginAttempt, now time.Time) time.Duration {
+	if attempt == nil || t.config.DelayBase <= 0 || attempt.Failures < t.config.DelayAfterFailures {
+		return 0
+	}
+	if now.Sub(attempt.LastFailureAt) >= t.config.FailureWindow {
+		return 0
+	}
+
+	delay := t.config.DelayBase
+	for i := t.config.DelayAfterFailures; i < attempt.Failures && delay < t.config.DelayMax; i++ {
+		delay *= 2
+	}
+	if delay > t.config.DelayMax {
+		delay = t.config.DelayMax
+	}
+
+	return attempt.LastFailureAt.Add(delay).Sub(now)
+}
+
+// lockedFor возвращает оставшееся время блокировки
+func lockedFor(attempt *models.LoginAttempt, now time.Time) time.Duration {
+	if attempt == nil || attempt.LockedUntil == nil {
+		return 0
+	}
+	return attempt.LockedUntil.Sub(now)
+}
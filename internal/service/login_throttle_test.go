@@ -0,0 +1,221 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"marketplace-api/internal/config"
+	"marketplace-api/internal/models"
+)
+
+// memoryLoginAttemptStore хранилище попыток входа в памяти для тестов
+type memoryLoginAttemptStore struct {
+	attempts map[string]*models.LoginAttempt
+	knownIPs map[string]time.Time
+}
+
+func newMemoryLoginAttemptStore() *memoryLoginAttemptStore {
+	return &memoryLoginAttemptStore{
+		attempts: make(map[string]*models.LoginAttempt),
+		knownIPs: make(map[string]time.Time),
+	}
+}
+
+func (s *memoryLoginAttemptStore) GetLoginAttempt(scope, key string) (*models.LoginAttempt, error) {
+	attempt, ok := s.attempts[scope+":"+key]
+	if !ok {
+		return nil, fmt.Errorf("login attempt not found")
+	}
+	copied := *attempt
+	return &copied, nil
+}
+
+func (s *memoryLoginAttemptStore) RecordLoginFailure(scope, key string, now, windowStart time.Time) (*models.LoginAttempt, error) {
+	attempt, ok := s.attempts[scope+":"+key]
+	if !ok {
+		attempt = &models.LoginAttempt{Scope: scope, Key: key}
+		s.attempts[scope+":"+key] = attempt
+	}
+	if attempt.LastFailureAt.Before(windowStart) {
+		attempt.Failures = 0
+	}
+	attempt.Failures++
+	attempt.LastFailureAt = now
+	copied := *attempt
+	return &copied, nil
+}
+
+func (s *memoryLoginAttemptStore) LockLoginAttempt(scope, key string, until time.Time) error {
+	s.attempts[scope+":"+key].LockedUntil = &until
+	return nil
+}
+
+func (s *memoryLoginAttemptStore) ResetLoginAttempt(scope, key string) error {
+	delete(s.attempts, scope+":"+key)
+	return nil
+}
+
+func (s *memoryLoginAttemptStore) DeleteStaleLoginAttempts(before, now time.Time) error {
+	return nil
+}
+
+func (s *memoryLoginAttemptStore) RememberLoginIP(login, clientIP string, now time.Time) error {
+	s.knownIPs[login+":"+clientIP] = now
+	return nil
+}
+
+func (s *memoryLoginAttemptStore) IsKnownLoginIP(login, clientIP string, since time.Time) (bool, error) {
+	lastSuccess, ok := s.knownIPs[login+":"+clientIP]
+	return ok && !lastSuccess.Before(since), nil
+}
+
+func (s *memoryLoginAttemptStore) DeleteStaleLoginIPs(before time.Time) error {
+	return nil
+}
+
+func newTestLoginThrottle(store LoginAttemptStore) *LoginThrottle {
+	return NewLoginThrottle(store, config.LoginThrottleConfig{
+		MaxFailures:         5,
+		MaxFailuresPerIP:    8,
+		PasswordResetsPerIP: 3,
+		FailureWindow:       15 * time.Minute,
+		LockoutDuration:     15 * time.Minute,
+		DelayAfterFailures:  3,
+		DelayBase:           time.Second,
+		DelayMax:            4 * time.Second,
+		KnownIPTTL:          30 * 24 * time.Hour,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestLoginThrottle_ProgressiveDelay(t *testing.T) {
+	store := newMemoryLoginAttemptStore()
+	throttle := newTestLoginThrottle(store)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, throttle.RecordFailure("victim", "198.51.100.1"))
+		assert.NoError(t, throttle.Check("victim", "198.51.100.1"), "no delay before DelayAfterFailures")
+	}
+
+	require.NoError(t, throttle.RecordFailure("victim", "198.51.100.1"))
+	err := throttle.Check("victim", "198.51.100.1")
+	var rateErr *RateLimitError
+	require.ErrorAs(t, err, &rateErr)
+	assert.Equal(t, "too many login attempts", rateErr.Message)
+	assert.InDelta(t, time.Second, rateErr.RetryAfter, float64(100*time.Millisecond))
+
+	// задержка удваивается и ограничена DelayMax
+	require.NoError(t, throttle.RecordFailure("victim", "198.51.100.1"))
+	assert.InDelta(t, 2*time.Second, throttle.delayFor(store.attempts["login:victim"], time.Now().UTC()), float64(100*time.Millisecond))
+	store.attempts["login:victim"].Failures = 10
+	assert.InDelta(t, 4*time.Second, throttle.delayFor(store.attempts["login:victim"], time.Now().UTC()), float64(100*time.Millisecond))
+}
+
+func TestLoginThrottle_LockoutAndUnlock(t *testing.T) {
+	store := newMemoryLoginAttemptStore()
+	throttle := newTestLoginThrottle(store)
+
+	// попытки с разных адресов, чтобы сработала блокировка логина, а не IP
+	for i := 0; i < 5; i++ {
+		require.NoError(t, throttle.RecordFailure("victim", fmt.Sprintf("198.51.100.%d", i)))
+	}
+
+	err := throttle.Check("victim", "203.0.113.50")
+	var rateErr *RateLimitError
+	require.ErrorAs(t, err, &rateErr)
+	assert.Equal(t, "account locked", rateErr.Message)
+	assert.InDelta(t, 15*time.Minute, rateErr.RetryAfter, float64(time.Second))
+
+	assert.NoError(t, throttle.Check("other", "203.0.113.50"), "other logins are not affected")
+
+	require.NoError(t, throttle.Unlock("victim"))
+	assert.NoError(t, throttle.Check("victim", "203.0.113.50"))
+}
+
+func TestLoginThrottle_KnownAddressBypassesLoginLockout(t *testing.T) {
+	store := newMemoryLoginAttemptStore()
+	throttle := newTestLoginThrottle(store)
+
+	require.NoError(t, throttle.RecordSuccess("victim", "203.0.113.7"))
+
+	// перебор логина с чужих адресов
+	for i := 0; i < 5; i++ {
+		require.NoError(t, throttle.RecordFailure("victim", fmt.Sprintf("198.51.100.%d", i)))
+	}
+
+	err := throttle.Check("victim", "198.51.100.20")
+	var rateErr *RateLimitError
+	require.ErrorAs(t, err, &rateErr)
+	assert.Equal(t, "account locked", rateErr.Message)
+
+	assert.NoError(t, throttle.Check("victim", "203.0.113.7"), "owner's address is not locked out")
+
+	// известный адрес освобождается только от ограничений по логину
+	for i := 0; i < 8; i++ {
+		require.NoError(t, throttle.RecordFailure(fmt.Sprintf("user%d", i), "203.0.113.7"))
+	}
+	require.ErrorAs(t, throttle.Check("victim", "203.0.113.7"), &rateErr)
+	assert.Equal(t, "too many login attempts", rateErr.Message)
+}
+
+func TestLoginThrottle_IPLockout(t *testing.T) {
+	store := newMemoryLoginAttemptStore()
+	throttle := newTestLoginThrottle(store)
+
+	// перебор разных логинов с одного адреса
+	for i := 0; i < 8; i++ {
+		require.NoError(t, throttle.RecordFailure(fmt.Sprintf("user%d", i), "198.51.100.1"))
+	}
+
+	err := throttle.Check("fresh_login", "198.51.100.1")
+	var rateErr *RateLimitError
+	require.ErrorAs(t, err, &rateErr)
+	assert.Equal(t, "too many login attempts", rateErr.Message)
+	assert.InDelta(t, 15*time.Minute, rateErr.RetryAfter, float64(time.Second))
+
+	assert.NoError(t, throttle.Check("fresh_login", "198.51.100.2"))
+}
+
+func TestLoginThrottle_SuccessResetsLoginOnly(t *testing.T) {
+	store := newMemoryLoginAttemptStore()
+	throttle := newTestLoginThrottle(store)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, throttle.RecordFailure("owner", "198.51.100.1"))
+	}
+	require.NoError(t, throttle.RecordSuccess("owner", "198.51.100.1"))
+
+	_, err := store.GetLoginAttempt(models.LoginAttemptScopeLogin, "owner")
+	assert.Error(t, err)
+
+	ipAttempt, err := store.GetLoginAttempt(models.LoginAttemptScopeIP, "198.51.100.1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, ipAttempt.Failures)
+}
+
+func TestLoginThrottle_PasswordResetLimit(t *testing.T) {
+	store := newMemoryLoginAttemptStore()
+	throttle := newTestLoginThrottle(store)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, throttle.RecordPasswordReset("198.51.100.1"))
+	}
+
+	err := throttle.RecordPasswordReset("198.51.100.1")
+	var rateErr *RateLimitError
+	require.ErrorAs(t, err, &rateErr)
+	assert.Equal(t, "too many password reset requests", rateErr.Message)
+	assert.InDelta(t, 15*time.Minute, rateErr.RetryAfter, float64(time.Second))
+
+	// отклоненный запрос не учитывается
+	assert.Equal(t, 3, store.attempts["password_reset:198.51.100.1"].Failures)
+
+	// другой адрес и попытки входа с этого адреса не ограничены
+	assert.NoError(t, throttle.RecordPasswordReset("198.51.100.2"))
+	assert.NoError(t, throttle.Check("victim", "198.51.100.1"))
+}
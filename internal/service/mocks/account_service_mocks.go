@@ -0,0 +1,54 @@
+package mocks
+
+import (
+	"github.com/golang/mock/gomock"
+	"marketplace-api/internal/models"
+	"reflect"
+)
+
+//go:generate mockgen -source=../account_service.go -destination=account_service_mocks.go
+
+type MockAccountService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountServiceMockRecorder
+}
+
+type MockAccountServiceMockRecorder struct {
+	mock *MockAccountService
+}
+
+func NewMockAccountService(ctrl *gomock.Controller) *MockAccountService {
+	mock := &MockAccountService{ctrl: ctrl}
+	mock.recorder = &MockAccountServiceMockRecorder{mock}
+	return mock
+}
+
+func (m *MockAccountService) EXPECT() *MockAccountServiceMockRecorder {
+	return m.recorder
+}
+
+func (m *MockAccountService) ExportData(userID int) (*models.UserDataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportData", userID)
+	ret0, _ := ret[0].(*models.UserDataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockAccountServiceMockRecorder) ExportData(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportData", reflect.TypeOf((*MockAccountService)(nil).ExportData), userID)
+}
+
+func (m *MockAccountService) DeleteAccount(userID int, req models.DeleteAccountRequest, client models.ClientInfo) (*models.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", userID, req, client)
+	ret0, _ := ret[0].(*models.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockAccountServiceMockRecorder) DeleteAccount(userID, req, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockAccountService)(nil).DeleteAccount), userID, req, client)
+}
@@ -0,0 +1,66 @@
+package mocks
+
+import (
+	"github.com/golang/mock/gomock"
+	"marketplace-api/internal/models"
+	"reflect"
+)
+
+//go:generate mockgen -source=../admin_service.go -destination=admin_service_mocks.go
+
+type MockAdminService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminServiceMockRecorder
+}
+
+type MockAdminServiceMockRecorder struct {
+	mock *MockAdminService
+}
+
+func NewMockAdminService(ctrl *gomock.Controller) *MockAdminService {
+	mock := &MockAdminService{ctrl: ctrl}
+	mock.recorder = &MockAdminServiceMockRecorder{mock}
+	return mock
+}
+
+func (m *MockAdminService) EXPECT() *MockAdminServiceMockRecorder {
+	return m.recorder
+}
+
+func (m *MockAdminService) ListUsers(filter models.UsersFilter) (*models.PaginatedUsers, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", filter)
+	ret0, _ := ret[0].(*models.PaginatedUsers)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockAdminServiceMockRecorder) ListUsers(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockAdminService)(nil).ListUsers), filter)
+}
+
+func (m *MockAdminService) UpdateUserRole(actor models.Actor, userID int, req models.UpdateUserRoleRequest, client models.ClientInfo) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", actor, userID, req, client)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockAdminServiceMockRecorder) UpdateUserRole(actor, userID, req, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockAdminService)(nil).UpdateUserRole), actor, userID, req, client)
+}
+
+func (m *MockAdminService) UnlockUser(actor models.Actor, userID int, client models.ClientInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockUser", actor, userID, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (mr *MockAdminServiceMockRecorder) UnlockUser(actor, userID, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockUser", reflect.TypeOf((*MockAdminService)(nil).UnlockUser), actor, userID, client)
+}
@@ -3,6 +3,7 @@ package mocks
 import (
 	"github.com/golang/mock/gomock"
 	"marketplace-api/internal/models"
+	"marketplace-api/pkg/utils"
 	"reflect"
 )
 
@@ -27,30 +28,117 @@ func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
 	return m.recorder
 }
 
-func (m *MockAuthService) Register(req models.RegisterRequest) (*models.AuthResponse, error) {
+func (m *MockAuthService) Register(req models.RegisterRequest, client models.ClientInfo) (*models.AuthResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Register", req)
+	ret := m.ctrl.Call(m, "Register", req, client)
 	ret0, _ := ret[0].(*models.AuthResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (mr *MockAuthServiceMockRecorder) Register(req interface{}) *gomock.Call {
+func (mr *MockAuthServiceMockRecorder) Register(req, client interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthService)(nil).Register), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthService)(nil).Register), req, client)
 }
 
-func (m *MockAuthService) Login(req models.LoginRequest) (*models.AuthResponse, error) {
+func (m *MockAuthService) Login(req models.LoginRequest, client models.ClientInfo) (*models.LoginResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", req)
+	ret := m.ctrl.Call(m, "Login", req, client)
+	ret0, _ := ret[0].(*models.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockAuthServiceMockRecorder) Login(req, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthService)(nil).Login), req, client)
+}
+
+func (m *MockAuthService) LoginTwoFactor(req models.LoginTwoFactorRequest, client models.ClientInfo) (*models.AuthResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginTwoFactor", req, client)
+	ret0, _ := ret[0].(*models.AuthResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockAuthServiceMockRecorder) LoginTwoFactor(req, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginTwoFactor", reflect.TypeOf((*MockAuthService)(nil).LoginTwoFactor), req, client)
+}
+
+func (m *MockAuthService) Refresh(req models.RefreshRequest, client models.ClientInfo) (*models.AuthResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", req, client)
+	ret0, _ := ret[0].(*models.AuthResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockAuthServiceMockRecorder) Refresh(req, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockAuthService)(nil).Refresh), req, client)
+}
+
+func (m *MockAuthService) Logout(claims *utils.Claims, req models.LogoutRequest, client models.ClientInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", claims, req, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (mr *MockAuthServiceMockRecorder) Logout(claims, req, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthService)(nil).Logout), claims, req, client)
+}
+
+func (m *MockAuthService) LogoutAll(userID int, client models.ClientInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutAll", userID, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (mr *MockAuthServiceMockRecorder) LogoutAll(userID, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAll", reflect.TypeOf((*MockAuthService)(nil).LogoutAll), userID, client)
+}
+
+func (m *MockAuthService) ChangePassword(userID int, req models.ChangePasswordRequest, client models.ClientInfo) (*models.AuthResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", userID, req, client)
 	ret0, _ := ret[0].(*models.AuthResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (mr *MockAuthServiceMockRecorder) Login(req interface{}) *gomock.Call {
+func (mr *MockAuthServiceMockRecorder) ChangePassword(userID, req, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAuthService)(nil).ChangePassword), userID, req, client)
+}
+
+func (m *MockAuthService) ForgotPassword(req models.ForgotPasswordRequest, client models.ClientInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", req, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (mr *MockAuthServiceMockRecorder) ForgotPassword(req, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockAuthService)(nil).ForgotPassword), req, client)
+}
+
+func (m *MockAuthService) ResetPassword(req models.ResetPasswordRequest, client models.ClientInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", req, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (mr *MockAuthServiceMockRecorder) ResetPassword(req, client interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthService)(nil).Login), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthService)(nil).ResetPassword), req, client)
 }
 
 func (m *MockAuthService) GetUserByID(id int) (*models.User, error) {
@@ -0,0 +1,79 @@
+package mocks
+
+import (
+	"github.com/golang/mock/gomock"
+	"marketplace-api/internal/models"
+	"reflect"
+)
+
+//go:generate mockgen -source=../category_service.go -destination=category_service_mocks.go
+
+type MockCategoryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryServiceMockRecorder
+}
+
+type MockCategoryServiceMockRecorder struct {
+	mock *MockCategoryService
+}
+
+func NewMockCategoryService(ctrl *gomock.Controller) *MockCategoryService {
+	mock := &MockCategoryService{ctrl: ctrl}
+	mock.recorder = &MockCategoryServiceMockRecorder{mock}
+	return mock
+}
+
+func (m *MockCategoryService) EXPECT() *MockCategoryServiceMockRecorder {
+	return m.recorder
+}
+
+func (m *MockCategoryService) GetCategoryTree() ([]models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryTree")
+	ret0, _ := ret[0].([]models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockCategoryServiceMockRecorder) GetCategoryTree() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryTree", reflect.TypeOf((*MockCategoryService)(nil).GetCategoryTree))
+}
+
+func (m *MockCategoryService) CreateCategory(actor models.Actor, req models.CreateCategoryRequest, client models.ClientInfo) (*models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCategory", actor, req, client)
+	ret0, _ := ret[0].(*models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockCategoryServiceMockRecorder) CreateCategory(actor, req, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockCategoryService)(nil).CreateCategory), actor, req, client)
+}
+
+func (m *MockCategoryService) UpdateCategory(actor models.Actor, id int, req models.UpdateCategoryRequest, client models.ClientInfo) (*models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCategory", actor, id, req, client)
+	ret0, _ := ret[0].(*models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockCategoryServiceMockRecorder) UpdateCategory(actor, id, req, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategory", reflect.TypeOf((*MockCategoryService)(nil).UpdateCategory), actor, id, req, client)
+}
+
+func (m *MockCategoryService) DeleteCategory(actor models.Actor, id int, client models.ClientInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCategory", actor, id, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (mr *MockCategoryServiceMockRecorder) DeleteCategory(actor, id, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockCategoryService)(nil).DeleteCategory), actor, id, client)
+}
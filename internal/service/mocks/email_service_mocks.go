@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"github.com/golang/mock/gomock"
+	"marketplace-api/internal/models"
+	"reflect"
+)
+
+//go:generate mockgen -source=../email_service.go -destination=email_service_mocks.go
+
+type MockEmailService struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailServiceMockRecorder
+}
+
+type MockEmailServiceMockRecorder struct {
+	mock *MockEmailService
+}
+
+func NewMockEmailService(ctrl *gomock.Controller) *MockEmailService {
+	mock := &MockEmailService{ctrl: ctrl}
+	mock.recorder = &MockEmailServiceMockRecorder{mock}
+	return mock
+}
+
+func (m *MockEmailService) EXPECT() *MockEmailServiceMockRecorder {
+	return m.recorder
+}
+
+func (m *MockEmailService) ChangeEmail(userID int, req models.ChangeEmailRequest, client models.ClientInfo) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeEmail", userID, req, client)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockEmailServiceMockRecorder) ChangeEmail(userID, req, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeEmail", reflect.TypeOf((*MockEmailService)(nil).ChangeEmail), userID, req, client)
+}
+
+func (m *MockEmailService) VerifyEmail(req models.VerifyEmailRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (mr *MockEmailServiceMockRecorder) VerifyEmail(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockEmailService)(nil).VerifyEmail), req)
+}
+
+func (m *MockEmailService) ResendVerification(userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerification", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (mr *MockEmailServiceMockRecorder) ResendVerification(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerification", reflect.TypeOf((*MockEmailService)(nil).ResendVerification), userID)
+}
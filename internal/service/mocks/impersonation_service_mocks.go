@@ -0,0 +1,68 @@
+package mocks
+
+import (
+	"github.com/golang/mock/gomock"
+	"marketplace-api/internal/models"
+	"marketplace-api/pkg/utils"
+	"reflect"
+)
+
+//go:generate mockgen -source=../impersonation_service.go -destination=impersonation_service_mocks.go
+
+type MockImpersonationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockImpersonationServiceMockRecorder
+}
+
+type MockImpersonationServiceMockRecorder struct {
+	mock *MockImpersonationService
+}
+
+func NewMockImpersonationService(ctrl *gomock.Controller) *MockImpersonationService {
+	mock := &MockImpersonationService{ctrl: ctrl}
+	mock.recorder = &MockImpersonationServiceMockRecorder{mock}
+	return mock
+}
+
+func (m *MockImpersonationService) EXPECT() *MockImpersonationServiceMockRecorder {
+	return m.recorder
+}
+
+func (m *MockImpersonationService) Impersonate(admin *utils.Claims, userID int, req models.ImpersonateRequest, client models.ClientInfo) (*models.ImpersonationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Impersonate", admin, userID, req, client)
+	ret0, _ := ret[0].(*models.ImpersonationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockImpersonationServiceMockRecorder) Impersonate(admin, userID, req, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Impersonate", reflect.TypeOf((*MockImpersonationService)(nil).Impersonate), admin, userID, req, client)
+}
+
+func (m *MockImpersonationService) ListImpersonations(filter models.ImpersonationsFilter) (*models.PaginatedImpersonations, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImpersonations", filter)
+	ret0, _ := ret[0].(*models.PaginatedImpersonations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockImpersonationServiceMockRecorder) ListImpersonations(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImpersonations", reflect.TypeOf((*MockImpersonationService)(nil).ListImpersonations), filter)
+}
+
+func (m *MockImpersonationService) GetImpersonation(id int) (*models.Impersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImpersonation", id)
+	ret0, _ := ret[0].(*models.Impersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockImpersonationServiceMockRecorder) GetImpersonation(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImpersonation", reflect.TypeOf((*MockImpersonationService)(nil).GetImpersonation), id)
+}
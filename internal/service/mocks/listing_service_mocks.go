@@ -66,29 +66,29 @@ func (mr *MockListingServiceMockRecorder) GetListingByID(id, currentUserID inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListingByID", reflect.TypeOf((*MockListingService)(nil).GetListingByID), id, currentUserID)
 }
 
-func (m *MockListingService) UpdateListing(id int, userID int, req models.UpdateListingRequest) (*models.Listing, error) {
+func (m *MockListingService) UpdateListing(id int, actor models.Actor, req models.UpdateListingRequest) (*models.Listing, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateListing", id, userID, req)
+	ret := m.ctrl.Call(m, "UpdateListing", id, actor, req)
 	ret0, _ := ret[0].(*models.Listing)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (mr *MockListingServiceMockRecorder) UpdateListing(id, userID, req interface{}) *gomock.Call {
+func (mr *MockListingServiceMockRecorder) UpdateListing(id, actor, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateListing", reflect.TypeOf((*MockListingService)(nil).UpdateListing), id, userID, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateListing", reflect.TypeOf((*MockListingService)(nil).UpdateListing), id, actor, req)
 }
 
-func (m *MockListingService) DeleteListing(id int, userID int) error {
+func (m *MockListingService) DeleteListing(id int, actor models.Actor) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteListing", id, userID)
+	ret := m.ctrl.Call(m, "DeleteListing", id, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (mr *MockListingServiceMockRecorder) DeleteListing(id, userID interface{}) *gomock.Call {
+func (mr *MockListingServiceMockRecorder) DeleteListing(id, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteListing", reflect.TypeOf((*MockListingService)(nil).DeleteListing), id, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteListing", reflect.TypeOf((*MockListingService)(nil).DeleteListing), id, actor)
 }
 
 func (m *MockListingService) GetUserListings(userID int, filter models.ListingsFilter) (*models.PaginatedListings, error) {
@@ -103,3 +103,54 @@ func (mr *MockListingServiceMockRecorder) GetUserListings(userID, filter interfa
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserListings", reflect.TypeOf((*MockListingService)(nil).GetUserListings), userID, filter)
 }
+
+func (m *MockListingService) GetSellerListings(login string, filter models.ListingsFilter, currentUserID *int) (*models.PaginatedListings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSellerListings", login, filter, currentUserID)
+	ret0, _ := ret[0].(*models.PaginatedListings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockListingServiceMockRecorder) GetSellerListings(login, filter, currentUserID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSellerListings", reflect.TypeOf((*MockListingService)(nil).GetSellerListings), login, filter, currentUserID)
+}
+
+func (m *MockListingService) AddListingImage(listingID int, actor models.Actor, req models.ListingImageRequest) (*models.ListingImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddListingImage", listingID, actor, req)
+	ret0, _ := ret[0].(*models.ListingImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockListingServiceMockRecorder) AddListingImage(listingID, actor, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddListingImage", reflect.TypeOf((*MockListingService)(nil).AddListingImage), listingID, actor, req)
+}
+
+func (m *MockListingService) DeleteListingImage(listingID, imageID int, actor models.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteListingImage", listingID, imageID, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (mr *MockListingServiceMockRecorder) DeleteListingImage(listingID, imageID, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteListingImage", reflect.TypeOf((*MockListingService)(nil).DeleteListingImage), listingID, imageID, actor)
+}
+
+func (m *MockListingService) ReorderListingImages(listingID int, actor models.Actor, req models.ReorderListingImagesRequest) ([]models.ListingImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderListingImages", listingID, actor, req)
+	ret0, _ := ret[0].([]models.ListingImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockListingServiceMockRecorder) ReorderListingImages(listingID, actor, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderListingImages", reflect.TypeOf((*MockListingService)(nil).ReorderListingImages), listingID, actor, req)
+}
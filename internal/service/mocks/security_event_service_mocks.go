@@ -0,0 +1,54 @@
+package mocks
+
+import (
+	"github.com/golang/mock/gomock"
+	"marketplace-api/internal/models"
+	"reflect"
+)
+
+//go:generate mockgen -source=../security_event_service.go -destination=security_event_service_mocks.go
+
+type MockSecurityEventService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecurityEventServiceMockRecorder
+}
+
+type MockSecurityEventServiceMockRecorder struct {
+	mock *MockSecurityEventService
+}
+
+func NewMockSecurityEventService(ctrl *gomock.Controller) *MockSecurityEventService {
+	mock := &MockSecurityEventService{ctrl: ctrl}
+	mock.recorder = &MockSecurityEventServiceMockRecorder{mock}
+	return mock
+}
+
+func (m *MockSecurityEventService) EXPECT() *MockSecurityEventServiceMockRecorder {
+	return m.recorder
+}
+
+func (m *MockSecurityEventService) ListUserEvents(userID int, filter models.SecurityEventsFilter) (*models.PaginatedSecurityEvents, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserEvents", userID, filter)
+	ret0, _ := ret[0].(*models.PaginatedSecurityEvents)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockSecurityEventServiceMockRecorder) ListUserEvents(userID, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserEvents", reflect.TypeOf((*MockSecurityEventService)(nil).ListUserEvents), userID, filter)
+}
+
+func (m *MockSecurityEventService) SearchEvents(filter models.AdminSecurityEventsFilter) (*models.PaginatedSecurityEvents, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchEvents", filter)
+	ret0, _ := ret[0].(*models.PaginatedSecurityEvents)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockSecurityEventServiceMockRecorder) SearchEvents(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchEvents", reflect.TypeOf((*MockSecurityEventService)(nil).SearchEvents), filter)
+}
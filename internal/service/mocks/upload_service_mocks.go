@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"github.com/golang/mock/gomock"
+	"io"
+	"marketplace-api/internal/models"
+	"reflect"
+)
+
+//go:generate mockgen -source=../upload_service.go -destination=upload_service_mocks.go
+
+type MockUploadService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUploadServiceMockRecorder
+}
+
+type MockUploadServiceMockRecorder struct {
+	mock *MockUploadService
+}
+
+func NewMockUploadService(ctrl *gomock.Controller) *MockUploadService {
+	mock := &MockUploadService{ctrl: ctrl}
+	mock.recorder = &MockUploadServiceMockRecorder{mock}
+	return mock
+}
+
+func (m *MockUploadService) EXPECT() *MockUploadServiceMockRecorder {
+	return m.recorder
+}
+
+func (m *MockUploadService) Upload(userID int, file io.Reader) (*models.Upload, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", userID, file)
+	ret0, _ := ret[0].(*models.Upload)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+func (mr *MockUploadServiceMockRecorder) Upload(userID, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockUploadService)(nil).Upload), userID, file)
+}
+
+func (m *MockUploadService) OpenFile(key, expires, signature string) (io.ReadCloser, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenFile", key, expires, signature)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+func (mr *MockUploadServiceMockRecorder) OpenFile(key, expires, signature interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenFile", reflect.TypeOf((*MockUploadService)(nil).OpenFile), key, expires, signature)
+}
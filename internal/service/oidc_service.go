@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"marketplace-api/internal/config"
+	"marketplace-api/internal/database/postgres"
+	"marketplace-api/internal/models"
+	"marketplace-api/internal/oidc"
+	"marketplace-api/pkg/utils"
+)
+
+const (
+	// oidcStateSize длина параметра state в байтах
+	oidcStateSize = 32
+	// oidcNonceSize длина nonce в байтах
+	oidcNonceSize = 32
+	// oidcRequestTimeout время ожидания ответа провайдера
+	oidcRequestTimeout = 10 * time.Second
+	// oidcLoginMaxLength длина основы логина, чтобы с суффиксом уложиться в 50 символов
+	oidcLoginMaxLength = 40
+	// oidcLoginAttempts количество попыток подобрать свободный логин
+	oidcLoginAttempts = 5
+)
+
+type OIDCService struct {
+	provider     *oidc.Provider
+	identityRepo *postgres.IdentityRepository
+	userRepo     *postgres.UserRepository
+	authService  *AuthService
+	oidcConfig   config.OIDCConfig
+	authConfig   config.AuthConfig
+	log          *slog.Logger
+}
+
+// NewOIDCService создает сервис входа через провайдера. provider равен nil, если вход через провайдера не настроен
+func NewOIDCService(
+	provider *oidc.Provider,
+	identityRepo *postgres.IdentityRepository,
+	userRepo *postgres.UserRepository,
+	authService *AuthService,
+	oidcConfig config.OIDCConfig,
+	authConfig config.AuthConfig,
+	log *slog.Logger,
+) *OIDCService {
+	return &OIDCService{
+		provider:     provider,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+		oidcConfig:   oidcConfig,
+		authConfig:   authConfig,
+		log:          log,
+	}
+}
+
+type OIDCServiceInterface interface {
+	AuthorizationURL() (*models.OIDCAuthorization, error)
+	Callback(req models.OIDCCallbackRequest, client models.ClientInfo) (*models.LoginResponse, error)
+	ListIdentities(userID int) ([]models.UserIdentity, error)
+}
+
+// AuthorizationURL начинает вход через провайдера: сохраняет state, nonce и секрет PKCE
+// и возвращает адрес страницы входа, на которую нужно отправить пользователя
+func (s *OIDCService) AuthorizationURL() (*models.OIDCAuthorization, error) {
+	if s.provider == nil {
+		return nil, fmt.Errorf("oidc disabled")
+	}
+
+	state, err := utils.GenerateOpaqueToken(oidcStateSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate state: %w", err)
+	}
+
+	nonce, err := utils.GenerateOpaqueToken(oidcNonceSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	loginState := models.OIDCLoginState{
+		CodeVerifier: oauth2.GenerateVerifier(),
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().UTC().Add(s.oidcConfig.StateTTL),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oidcRequestTimeout)
+	defer cancel()
+
+	authURL, err := s.provider.AuthCodeURL(ctx, state, loginState.Nonce, loginState.CodeVerifier)
+	if err != nil {
+		return nil, s.providerError(err)
+	}
+
+	if err := s.identityRepo.CreateLoginState(utils.HashToken(state), loginState, time.Now().UTC()); err != nil {
+		return nil, fmt.Errorf("failed to store login state: %w", err)
+	}
+
+	return &models.OIDCAuthorization{
+		AuthorizationURL: authURL,
+		ExpiresAt:        loginState.ExpiresAt,
+	}, nil
+}
+
+// Callback завершает вход по коду авторизации, с которым провайдер вернул пользователя.
+// Пользователь определяется по привязанной учетной записи провайдера; если ее нет,
+// учетная запись привязывается к пользователю с тем же подтвержденным email
+// или создается новый пользователь без пароля.
+// Второй фактор, если он включен, запрашивается так же, как при входе по паролю
+func (s *OIDCService) Callback(req models.OIDCCallbackRequest, client models.ClientInfo) (*models.LoginResponse, error) {
+	if s.provider == nil {
+		return nil, fmt.Errorf("oidc disabled")
+	}
+
+	now := time.Now().UTC()
+
+	// state одноразовый и удаляется даже при отказе провайдера
+	state, err := s.identityRepo.ConsumeLoginState(utils.HashToken(req.State), now)
+	if err != nil {
+		if err.Error() == "state not found" {
+			return nil, fmt.Errorf("oidc state invalid")
+		}
+		return nil, fmt.Errorf("failed to get login state: %w", err)
+	}
+
+	if req.Error != "" {
+		s.log.Info("OIDC login denied by provider", "error", req.Error, "description", req.ErrorDescription)
+		return nil, fmt.Errorf("oidc login failed")
+	}
+	if req.Code == "" {
+		return nil, fmt.Errorf("oidc login failed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oidcRequestTimeout)
+	defer cancel()
+
+	identity, err := s.provider.Exchange(ctx, req.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		return nil, s.providerError(err)
+	}
+
+	user, err := s.resolveUser(identity, now, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authService.loginUser(user, client, "oidc")
+}
+
+// ListIdentities возвращает учетные записи провайдеров, привязанные к пользователю
+func (s *OIDCService) ListIdentities(userID int) ([]models.UserIdentity, error) {
+	identities, err := s.identityRepo.ListIdentities(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identities: %w", err)
+	}
+
+	return identities, nil
+}
+
+// resolveUser находит или создает пользователя для учетной записи провайдера.
+// Привязка к существующему пользователю по email выполняется, только если email подтвержден
+// и провайдером, и у нас: иначе владелец чужого email у провайдера получил бы доступ к аккаунту
+func (s *OIDCService) resolveUser(identity *oidc.Identity, now time.Time, client models.ClientInfo) (*models.User, error) {
+	email := utils.NormalizeEmail(identity.Email)
+	if email != "" && !utils.ValidateEmail(email) {
+		email = ""
+	}
+
+	userID, err := s.identityRepo.TouchIdentity(identity.Issuer, identity.Subject, email, now)
+	if err == nil {
+		return s.userRepo.GetUserByID(userID)
+	}
+	if err.Error() != "identity not found" {
+		return nil, fmt.Errorf("failed to get identity: %w", err)
+	}
+
+	if email != "" {
+		user, err := s.userRepo.GetUserByEmail(email)
+		if err == nil {
+			if !identity.EmailVerified || !user.EmailVerified() {
+				return nil, fmt.Errorf("email already in use")
+			}
+
+			if err := s.identityRepo.LinkIdentity(user.ID, identity.Issuer, identity.Subject, email, now); err != nil {
+				return nil, fmt.Errorf("failed to link identity: %w", err)
+			}
+
+			s.log.Info("OIDC identity linked by email", "user_id", user.ID, "issuer", identity.Issuer)
+			return user, nil
+		}
+		if err.Error() != "user not found" {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+	}
+
+	if email == "" && s.authConfig.EmailRequired {
+		return nil, fmt.Errorf("email is required")
+	}
+
+	login, err := s.availableLogin(identity, email)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.identityRepo.CreateUserWithIdentity(login, email, identity.EmailVerified, identity.Issuer, identity.Subject, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	s.log.Info("User created from OIDC identity", "user_id", user.ID, "issuer", identity.Issuer)
+	s.authService.securityEvents.Record(models.SecurityEvent{
+		Type:    models.SecurityEventRegistered,
+		UserID:  user.ID,
+		Details: map[string]string{"method": "oidc", "issuer": identity.Issuer},
+	}, client)
+	return user, nil
+}
+
+// availableLogin подбирает свободный логин на основе имени пользователя у провайдера или его email
+func (s *OIDCService) availableLogin(identity *oidc.Identity, email string) (string, error) {
+	base := oidcLoginBase(identity.PreferredUsername, email)
+
+	login := base
+	for attempt := 0; attempt < oidcLoginAttempts; attempt++ {
+		exists, err := s.userRepo.UserExists(login)
+		if err != nil {
+			return "", fmt.Errorf("failed to check user existence: %w", err)
+		}
+		if !exists && !s.authService.loginPolicy.IsReserved(login) {
+			return login, nil
+		}
+
+		suffix, err := utils.GenerateRandomID()
+		if err != nil {
+			return "", fmt.Errorf("failed to generate login suffix: %w", err)
+		}
+		login = base + "_" + suffix[:6]
+	}
+
+	return "", fmt.Errorf("failed to find available login")
+}
+
+// oidcLoginBase приводит имя пользователя у провайдера к допустимому логину.
+// Недопустимые символы заменяются на "_", слишком короткое имя дополняется префиксом user_
+func oidcLoginBase(preferredUsername, email string) string {
+	name := preferredUsername
+	if name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+
+	login := strings.Trim(b.String(), "_")
+	if len(login) > oidcLoginMaxLength {
+		login = login[:oidcLoginMaxLength]
+	}
+	if len(login) < 3 {
+		login = "user_" + login
+	}
+
+	return strings.TrimRight(login, "_")
+}
+
+// providerError приводит ошибку провайдера к ошибке, по которой обработчик выбирает ответ
+func (s *OIDCService) providerError(err error) error {
+	if errors.Is(err, oidc.ErrProviderUnavailable) {
+		s.log.Error("OIDC provider unavailable", "issuer", s.oidcConfig.Issuer, "error", err)
+		return fmt.Errorf("oidc provider unavailable")
+	}
+
+	s.log.Warn("OIDC login failed", "issuer", s.oidcConfig.Issuer, "error", err)
+	return fmt.Errorf("oidc login failed")
+}
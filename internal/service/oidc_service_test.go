@@ -0,0 +1,35 @@
+package service
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// defaultLoginPattern логин, допустимый политикой по умолчанию
+var defaultLoginPattern = regexp.MustCompile(`^[a-zA-Z0-9_]{3,50}$`)
+
+func TestOIDCLoginBase(t *testing.T) {
+	tests := []struct {
+		name              string
+		preferredUsername string
+		email             string
+		want              string
+	}{
+		{name: "preferred username", preferredUsername: "alice", email: "other@example.com", want: "alice"},
+		{name: "email local part", email: "bob.smith@example.com", want: "bob_smith"},
+		{name: "invalid characters", preferredUsername: "иван-petrov", want: "petrov"},
+		{name: "too short", preferredUsername: "al", want: "user_al"},
+		{name: "empty", want: "user"},
+		{name: "too long", preferredUsername: "a123456789b123456789c123456789d123456789e123456789", want: "a123456789b123456789c123456789d123456789"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			login := oidcLoginBase(tt.preferredUsername, tt.email)
+			assert.Equal(t, tt.want, login)
+			assert.Regexp(t, defaultLoginPattern, login)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"marketplace-api/internal/database/postgres"
+	"marketplace-api/internal/models"
+	"marketplace-api/pkg/utils"
+)
+
+// phonePattern допустимый телефон: цифры, пробелы, скобки и дефисы, необязательный + в начале
+var phonePattern = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{4,31}$`)
+
+type ProfileService struct {
+	userRepo    *postgres.UserRepository
+	profileRepo *postgres.ProfileRepository
+}
+
+func NewProfileService(userRepo *postgres.UserRepository, profileRepo *postgres.ProfileRepository) *ProfileService {
+	return &ProfileService{
+		userRepo:    userRepo,
+		profileRepo: profileRepo,
+	}
+}
+
+type ProfileServiceInterface interface {
+	GetProfile(userID int) (*models.UserProfile, error)
+	UpdateProfile(userID int, req models.UpdateProfileRequest) (*models.UserProfile, error)
+	GetPublicProfile(login string) (*models.PublicProfile, error)
+}
+
+// GetProfile возвращает профиль текущего пользователя со всеми полями, включая скрытые контакты
+func (s *ProfileService) GetProfile(userID int) (*models.UserProfile, error) {
+	profile, err := s.profileRepo.GetProfile(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// UpdateProfile изменяет заданные в запросе поля профиля
+func (s *ProfileService) UpdateProfile(userID int, req models.UpdateProfileRequest) (*models.UserProfile, error) {
+	profile, err := s.profileRepo.GetProfile(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	if req.DisplayName != nil {
+		profile.DisplayName = strings.TrimSpace(*req.DisplayName)
+	}
+	if req.Bio != nil {
+		profile.Bio = strings.TrimSpace(*req.Bio)
+	}
+	if req.AvatarURL != nil {
+		profile.AvatarURL = nil
+		if avatarURL := strings.TrimSpace(*req.AvatarURL); avatarURL != "" {
+			profile.AvatarURL = &avatarURL
+		}
+	}
+	if req.Location != nil {
+		profile.Location = strings.TrimSpace(*req.Location)
+	}
+	if req.Phone != nil {
+		profile.Phone = strings.TrimSpace(*req.Phone)
+	}
+	if req.ShowEmail != nil {
+		profile.ShowEmail = *req.ShowEmail
+	}
+	if req.ShowPhone != nil {
+		profile.ShowPhone = *req.ShowPhone
+	}
+	if req.PreferredContact != nil {
+		profile.PreferredContact = *req.PreferredContact
+	}
+
+	if err := s.validateProfile(userID, profile); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.profileRepo.SaveProfile(userID, *profile, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	return saved, nil
+}
+
+// GetPublicProfile возвращает публичный профиль продавца по логину
+func (s *ProfileService) GetPublicProfile(login string) (*models.PublicProfile, error) {
+	profile, err := s.profileRepo.GetPublicProfile(login)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// validateProfile проверяет профиль после применения изменений
+func (s *ProfileService) validateProfile(userID int, profile *models.UserProfile) error {
+	if profile.AvatarURL != nil && !utils.ValidateURL(*profile.AvatarURL) {
+		return fmt.Errorf("invalid avatar URL format")
+	}
+
+	if profile.Phone != "" && !phonePattern.MatchString(profile.Phone) {
+		return fmt.Errorf("invalid phone format")
+	}
+
+	switch profile.PreferredContact {
+	case models.ContactPhone:
+		if profile.Phone == "" || !profile.ShowPhone {
+			return fmt.Errorf("preferred contact must be visible")
+		}
+	case models.ContactEmail:
+		if !profile.ShowEmail {
+			return fmt.Errorf("preferred contact must be visible")
+		}
+
+		user, err := s.userRepo.GetUserByID(userID)
+		if err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		if !user.EmailVerified() {
+			return fmt.Errorf("email verification required")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"marketplace-api/internal/config"
+	"marketplace-api/internal/database/postgres"
+	"marketplace-api/internal/models"
+)
+
+// maxAttemptedLoginLength длина логина из неудачной попытки входа, которая сохраняется в журнале
+const maxAttemptedLoginLength = 255
+
+// SecurityEventService ведет журнал безопасности: входы, регистрации, смены пароля,
+// отзывы токенов и действия администраторов
+type SecurityEventService struct {
+	securityEventRepo *postgres.SecurityEventRepository
+	config            config.SecurityEventsConfig
+	log               *slog.Logger
+}
+
+func NewSecurityEventService(securityEventRepo *postgres.SecurityEventRepository, config config.SecurityEventsConfig, log *slog.Logger) *SecurityEventService {
+	return &SecurityEventService{
+		securityEventRepo: securityEventRepo,
+		config:            config,
+		log:               log,
+	}
+}
+
+type SecurityEventServiceInterface interface {
+	ListUserEvents(userID int, filter models.SecurityEventsFilter) (*models.PaginatedSecurityEvents, error)
+	SearchEvents(filter models.AdminSecurityEventsFilter) (*models.PaginatedSecurityEvents, error)
+}
+
+// Record добавляет событие в журнал, дополняя его сведениями о клиенте.
+// Ошибка записи только логируется: недоступность журнала не должна прерывать вход или выход
+func (s *SecurityEventService) Record(event models.SecurityEvent, client models.ClientInfo) {
+	client = normalizeClient(client)
+
+	event.IPAddress = client.IP
+	event.UserAgent = client.UserAgent
+	event.RequestID = client.RequestID
+	event.CreatedAt = time.Now().UTC()
+	if runes := []rune(event.Login); len(runes) > maxAttemptedLoginLength {
+		event.Login = string(runes[:maxAttemptedLoginLength])
+	}
+
+	if err := s.securityEventRepo.CreateEvent(&event); err != nil {
+		s.log.Error("Failed to record security event",
+			"event_type", event.Type,
+			"user_id", event.UserID,
+			"request_id", event.RequestID,
+			"error", err,
+		)
+	}
+}
+
+// ListUserEvents получает постраничный журнал событий аккаунта пользователя
+func (s *SecurityEventService) ListUserEvents(userID int, filter models.SecurityEventsFilter) (*models.PaginatedSecurityEvents, error) {
+	return s.SearchEvents(models.AdminSecurityEventsFilter{
+		SecurityEventsFilter: filter,
+		UserID:               userID,
+	})
+}
+
+// SearchEvents ищет события по журналу безопасности
+func (s *SecurityEventService) SearchEvents(filter models.AdminSecurityEventsFilter) (*models.PaginatedSecurityEvents, error) {
+	filter.SetDefaults()
+
+	if filter.Type != "" && !filter.Type.Valid() {
+		return nil, fmt.Errorf("invalid event type")
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("invalid time range")
+	}
+
+	events, err := s.securityEventRepo.ListEvents(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list security events: %w", err)
+	}
+
+	return events, nil
+}
+
+// Run периодически стирает сведения о клиенте в записях старше ClientDataRetention, пока не отменен ctx.
+// Так логины из неудачных попыток входа, часто опечатки в пароле, не хранятся бессрочно
+func (s *SecurityEventService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.securityEventRepo.AnonymizeEventsBefore(time.Now().UTC().Add(-s.config.ClientDataRetention))
+			if err != nil {
+				s.log.Error("Failed to anonymize old security events", "error", err)
+				continue
+			}
+			if count > 0 {
+				s.log.Info("Old security events anonymized", "events", count)
+			}
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"marketplace-api/internal/database/postgres"
+	"marketplace-api/internal/models"
+	"marketplace-api/pkg/utils"
+)
+
+// maxUserAgentLength максимальная длина сохраняемого user agent в символах
+const maxUserAgentLength = 512
+
+// sessionSeenInterval как часто запросы с access-токеном обновляют время активности сеанса
+const sessionSeenInterval = time.Minute
+
+type SessionService struct {
+	sessionRepo     *postgres.SessionRepository
+	revocationStore *TokenRevocationStore
+	securityEvents  *SecurityEventService
+	log             *slog.Logger
+
+	mu sync.Mutex
+	// seenAt время последнего обновления last_seen_at по ID сеанса на этой реплике
+	seenAt map[int]time.Time
+	// sweptAt время последней очистки seenAt от устаревших записей
+	sweptAt time.Time
+}
+
+func NewSessionService(sessionRepo *postgres.SessionRepository, revocationStore *TokenRevocationStore, securityEvents *SecurityEventService, log *slog.Logger) *SessionService {
+	return &SessionService{
+		sessionRepo:     sessionRepo,
+		revocationStore: revocationStore,
+		securityEvents:  securityEvents,
+		log:             log,
+		seenAt:          make(map[int]time.Time),
+	}
+}
+
+type SessionServiceInterface interface {
+	ListSessions(userID, currentSessionID int) ([]models.Session, error)
+	RevokeSession(userID, sessionID int, client models.ClientInfo) error
+}
+
+// ListSessions возвращает действующие сеансы пользователя. Сеанс, из которого сделан запрос, отмечается как текущий
+func (s *SessionService) ListSessions(userID, currentSessionID int) ([]models.Session, error) {
+	sessions, err := s.sessionRepo.ListActiveSessions(userID, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession завершает сеанс пользователя: его refresh-токены отзываются,
+// а access-токены перестают приниматься AuthMiddleware
+func (s *SessionService) RevokeSession(userID, sessionID int, client models.ClientInfo) error {
+	if err := s.revoke(userID, sessionID); err != nil {
+		return err
+	}
+
+	s.securityEvents.Record(models.SecurityEvent{
+		Type:    models.SecurityEventSessionRevoked,
+		UserID:  userID,
+		Details: map[string]string{"session_id": strconv.Itoa(sessionID)},
+	}, client)
+
+	return nil
+}
+
+// revoke завершает сеанс без записи в журнал безопасности; используется при выходе, который записывается отдельно
+func (s *SessionService) revoke(userID, sessionID int) error {
+	if err := s.sessionRepo.RevokeSession(userID, sessionID, time.Now().UTC()); err != nil {
+		if err.Error() == "session not found" {
+			return err
+		}
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	s.revocationStore.ForgetSession(sessionID)
+
+	return nil
+}
+
+// start начинает сеанс для нового семейства refresh-токенов
+func (s *SessionService) start(userID int, familyID string, client models.ClientInfo, expiresAt time.Time) (*models.Session, error) {
+	session, err := s.sessionRepo.CreateSession(userID, familyID, normalizeClient(client), expiresAt, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return session, nil
+}
+
+// continueSession находит действующий сеанс семейства refresh-токенов и отмечает его активность
+func (s *SessionService) continueSession(familyID string, client models.ClientInfo, expiresAt time.Time) (*models.Session, error) {
+	session, err := s.sessionRepo.GetSessionByFamily(familyID)
+	if err != nil {
+		if err.Error() == "session not found" {
+			return nil, fmt.Errorf("invalid refresh token")
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	if session.RevokedAt != nil {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	client = normalizeClient(client)
+	now := time.Now().UTC()
+	if err := s.sessionRepo.TouchSession(session.ID, client, expiresAt, now); err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+
+	session.UserAgent = client.UserAgent
+	session.IPAddress = client.IP
+	session.ExpiresAt = expiresAt
+	session.LastSeenAt = now
+
+	return session, nil
+}
+
+// RecordSessionActivity отмечает использование access-токена сеанса. Чтобы не писать в базу на каждый запрос,
+// last_seen_at обновляется не чаще раза в sessionSeenInterval; ошибка записи только логируется
+func (s *SessionService) RecordSessionActivity(claims *utils.Claims) {
+	if claims.SessionID == 0 || claims.IsImpersonation() {
+		return
+	}
+
+	now := time.Now().UTC()
+	staleBefore := now.Add(-sessionSeenInterval)
+
+	s.mu.Lock()
+	if seenAt, ok := s.seenAt[claims.SessionID]; ok && seenAt.After(staleBefore) {
+		s.mu.Unlock()
+		return
+	}
+	s.seenAt[claims.SessionID] = now
+	if s.sweptAt.Before(staleBefore) {
+		for id, seenAt := range s.seenAt {
+			if !seenAt.After(staleBefore) {
+				delete(s.seenAt, id)
+			}
+		}
+		s.sweptAt = now
+	}
+	s.mu.Unlock()
+
+	if err := s.sessionRepo.MarkSessionSeen(claims.SessionID, now, staleBefore); err != nil {
+		s.log.Error("Failed to update session activity", "session_id", claims.SessionID, "error", err)
+	}
+}
+
+// revokeAll отзывает все сеансы пользователя
+func (s *SessionService) revokeAll(userID int) error {
+	if err := s.sessionRepo.RevokeUserSessions(userID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+// normalizeClient обрезает user agent до размера колонки
+func normalizeClient(client models.ClientInfo) models.ClientInfo {
+	if runes := []rune(client.UserAgent); len(runes) > maxUserAgentLength {
+		client.UserAgent = string(runes[:maxUserAgentLength])
+	}
+	return client
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"marketplace-api/internal/database/postgres"
+	"marketplace-api/pkg/utils"
+)
+
+// revocationCacheSweepSize размер кэша, после которого из него вычищаются устаревшие записи
+const revocationCacheSweepSize = 10000
+
+// TokenRevocationStore хранит отозванные access-токены в PostgreSQL и кэширует
+// результаты проверок в памяти. Отзыв, сделанный на этой реплике, виден сразу;
+// отзыв с другой реплики — не позже чем через cacheTTL
+type TokenRevocationStore struct {
+	repo     *postgres.TokenRevocationRepository
+	cacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]revocationCacheEntry
+}
+
+type revocationCacheEntry struct {
+	userID       int
+	sessionID    int
+	revoked      bool
+	tokenVersion int
+	fetchedAt    time.Time
+	expiresAt    time.Time
+}
+
+func NewTokenRevocationStore(repo *postgres.TokenRevocationRepository, cacheTTL time.Duration) *TokenRevocationStore {
+	return &TokenRevocationStore{
+		repo:     repo,
+		cacheTTL: cacheTTL,
+		cache:    make(map[string]revocationCacheEntry),
+	}
+}
+
+// IsRevoked проверяет, отозван ли токен
+func (s *TokenRevocationStore) IsRevoked(claims *utils.Claims) (bool, error) {
+	if claims.ID == "" {
+		return true, nil
+	}
+
+	now := time.Now()
+
+	s.mu.Lock()
+	entry, ok := s.cache[claims.ID]
+	s.mu.Unlock()
+
+	if !ok || (!entry.revoked && now.Sub(entry.fetchedAt) > s.cacheTTL) {
+		revoked, tokenVersion, err := s.repo.GetRevocationState(claims.ID, claims.UserID, claims.SessionID)
+		if err != nil {
+			return false, fmt.Errorf("failed to check token revocation: %w", err)
+		}
+
+		entry = revocationCacheEntry{
+			userID:       claims.UserID,
+			sessionID:    claims.SessionID,
+			revoked:      revoked,
+			tokenVersion: tokenVersion,
+			fetchedAt:    now,
+		}
+		if claims.ExpiresAt != nil {
+			entry.expiresAt = claims.ExpiresAt.Time
+		}
+		s.store(claims.ID, entry)
+	}
+
+	return entry.revoked || claims.TokenVersion < entry.tokenVersion, nil
+}
+
+// Revoke отзывает один access-токен
+func (s *TokenRevocationStore) Revoke(claims *utils.Claims) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return fmt.Errorf("token has no id or expiration")
+	}
+
+	if err := s.repo.RevokeToken(claims.ID, claims.UserID, claims.ExpiresAt.Time.UTC()); err != nil {
+		return err
+	}
+
+	s.store(claims.ID, revocationCacheEntry{
+		userID:    claims.UserID,
+		sessionID: claims.SessionID,
+		revoked:   true,
+		fetchedAt: time.Now(),
+		expiresAt: claims.ExpiresAt.Time,
+	})
+
+	return nil
+}
+
+// RevokeAllForUser отзывает все выпущенные на текущий момент токены пользователя.
+// Токены, выпущенные после отзыва, получают новую версию и остаются действительными
+func (s *TokenRevocationStore) RevokeAllForUser(userID int) error {
+	if err := s.repo.RevokeUserTokens(userID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	for jti, entry := range s.cache {
+		if entry.userID == userID {
+			delete(s.cache, jti)
+		}
+	}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// ForgetSession сбрасывает кэш проверок токенов сеанса, чтобы его отзыв на этой реплике был виден сразу.
+// Сам сеанс отзывается в SessionRepository
+func (s *TokenRevocationStore) ForgetSession(sessionID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for jti, entry := range s.cache {
+		if entry.sessionID == sessionID {
+			delete(s.cache, jti)
+		}
+	}
+}
+
+func (s *TokenRevocationStore) store(jti string, entry revocationCacheEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.cache) >= revocationCacheSweepSize {
+		now := time.Now()
+		for key, cached := range s.cache {
+			if now.After(cached.expiresAt) || (!cached.revoked && now.Sub(cached.fetchedAt) > s.cacheTTL) {
+				delete(s.cache, key)
+			}
+		}
+	}
+
+	s.cache[jti] = entry
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"strings"
+	"time"
+
+	qrcode "github.com/skip2/go-qrcode"
+	"marketplace-api/internal/config"
+	"marketplace-api/internal/database/postgres"
+	"marketplace-api/internal/models"
+	"marketplace-api/pkg/utils"
+)
+
+const (
+	// totpSkew допуск расхождения часов в 30-секундных интервалах
+	totpSkew = 1
+	// recoveryCodeCount количество выдаваемых кодов восстановления
+	recoveryCodeCount = 10
+	// recoveryCodeSize длина кода восстановления в байтах (16 символов base32)
+	recoveryCodeSize = 10
+	// challengeTokenSize длина токена незавершенного входа в байтах
+	challengeTokenSize = 32
+	// challengeMaxAttempts количество попыток ввода кода для одного незавершенного входа
+	challengeMaxAttempts = 5
+	// qrCodeSize размер QR-кода в пикселях
+	qrCodeSize = 256
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type TwoFactorService struct {
+	userRepo       *postgres.UserRepository
+	twoFactorRepo  *postgres.TwoFactorRepository
+	passwordHasher *utils.PasswordHashers
+	securityEvents *SecurityEventService
+	authConfig     config.AuthConfig
+}
+
+func NewTwoFactorService(
+	userRepo *postgres.UserRepository,
+	twoFactorRepo *postgres.TwoFactorRepository,
+	passwordHasher *utils.PasswordHashers,
+	securityEvents *SecurityEventService,
+	authConfig config.AuthConfig,
+) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:       userRepo,
+		twoFactorRepo:  twoFactorRepo,
+		passwordHasher: passwordHasher,
+		securityEvents: securityEvents,
+		authConfig:     authConfig,
+	}
+}
+
+type TwoFactorServiceInterface interface {
+	Enroll(userID int) (*models.TwoFactorEnrollment, error)
+	Confirm(userID int, req models.TwoFactorCodeRequest, client models.ClientInfo) (*models.RecoveryCodesResponse, error)
+	Disable(userID int, req models.DisableTwoFactorRequest, client models.ClientInfo) error
+}
+
+// Enroll начинает подключение TOTP: создает секрет и возвращает его в виде otpauth URI и QR-кода.
+// Второй фактор включается только после Confirm
+func (s *TwoFactorService) Enroll(userID int) (*models.TwoFactorEnrollment, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.TwoFactorEnabled {
+		return nil, fmt.Errorf("two-factor already enabled")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.SavePendingTOTP(user.ID, secret); err != nil {
+		if err.Error() == "totp already enabled" {
+			return nil, fmt.Errorf("two-factor already enabled")
+		}
+		return nil, fmt.Errorf("failed to save totp secret: %w", err)
+	}
+
+	uri := utils.TOTPURI(s.authConfig.TOTPIssuer, user.Login, secret)
+
+	png, err := qrcode.Encode(uri, qrcode.Medium, qrCodeSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate qr code: %w", err)
+	}
+
+	return &models.TwoFactorEnrollment{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRCodePNG:  png,
+	}, nil
+}
+
+// Confirm включает второй фактор после проверки первого кода и выдает коды восстановления
+func (s *TwoFactorService) Confirm(userID int, req models.TwoFactorCodeRequest, client models.ClientInfo) (*models.RecoveryCodesResponse, error) {
+	totp, err := s.twoFactorRepo.GetTOTP(userID)
+	if err != nil {
+		if err.Error() == "totp not found" {
+			return nil, fmt.Errorf("two-factor enrollment not started")
+		}
+		return nil, fmt.Errorf("failed to get totp: %w", err)
+	}
+
+	if totp.EnabledAt != nil {
+		return nil, fmt.Errorf("two-factor already enabled")
+	}
+
+	now := time.Now().UTC()
+	step, ok := utils.ValidateTOTP(totp.Secret, strings.TrimSpace(req.Code), now, totpSkew)
+	if !ok {
+		return nil, fmt.Errorf("invalid two-factor code")
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, utils.HashToken(normalizeRecoveryCode(code)))
+	}
+
+	if err := s.twoFactorRepo.EnableTOTP(userID, step, hashes, now); err != nil {
+		if err.Error() == "totp not found" {
+			return nil, fmt.Errorf("two-factor enrollment not started")
+		}
+		return nil, fmt.Errorf("failed to enable two-factor: %w", err)
+	}
+
+	s.securityEvents.Record(models.SecurityEvent{Type: models.SecurityEventTwoFactorEnabled, UserID: userID}, client)
+
+	return &models.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable отключает второй фактор после проверки пароля и кода
+func (s *TwoFactorService) Disable(userID int, req models.DisableTwoFactorRequest, client models.ClientInfo) error {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if !user.TwoFactorEnabled {
+		return fmt.Errorf("two-factor not enabled")
+	}
+
+	if _, err := s.passwordHasher.Verify(req.Password, user.PasswordHash); err != nil {
+		return fmt.Errorf("invalid current password")
+	}
+
+	ok, err := s.VerifyCode(user.ID, req.Code, client)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("invalid two-factor code")
+	}
+
+	if err := s.twoFactorRepo.DisableTOTP(user.ID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to disable two-factor: %w", err)
+	}
+
+	s.securityEvents.Record(models.SecurityEvent{Type: models.SecurityEventTwoFactorDisabled, UserID: user.ID}, client)
+
+	return nil
+}
+
+// VerifyCode проверяет код TOTP или код восстановления. Каждый код принимается только один раз,
+// использование кода восстановления записывается в журнал безопасности
+func (s *TwoFactorService) VerifyCode(userID int, code string, client models.ClientInfo) (bool, error) {
+	code = strings.TrimSpace(code)
+	now := time.Now().UTC()
+
+	if isTOTPCode(code) {
+		totp, err := s.twoFactorRepo.GetTOTP(userID)
+		if err != nil {
+			if err.Error() == "totp not found" {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to get totp: %w", err)
+		}
+
+		step, ok := utils.ValidateTOTP(totp.Secret, code, now, totpSkew)
+		if !ok || totp.EnabledAt == nil {
+			return false, nil
+		}
+
+		return s.twoFactorRepo.UseTOTPStep(userID, step)
+	}
+
+	used, err := s.twoFactorRepo.UseRecoveryCode(userID, utils.HashToken(normalizeRecoveryCode(code)), now)
+	if err != nil || !used {
+		return used, err
+	}
+
+	s.securityEvents.Record(models.SecurityEvent{Type: models.SecurityEventRecoveryCodeUsed, UserID: userID}, client)
+
+	return true, nil
+}
+
+// StartChallenge создает незавершенный вход, который нужно подтвердить вторым фактором
+func (s *TwoFactorService) StartChallenge(userID int) (*models.TwoFactorChallenge, error) {
+	token, err := utils.GenerateOpaqueToken(challengeTokenSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate challenge token: %w", err)
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(s.authConfig.TwoFactorChallengeTTL)
+	if err := s.twoFactorRepo.CreateChallenge(userID, utils.HashToken(token), expiresAt, now); err != nil {
+		return nil, fmt.Errorf("failed to store challenge: %w", err)
+	}
+
+	return &models.TwoFactorChallenge{
+		ChallengeToken: token,
+		ExpiresAt:      expiresAt,
+	}, nil
+}
+
+// AttemptChallenge учитывает попытку завершить вход и возвращает незавершенный вход
+func (s *TwoFactorService) AttemptChallenge(token string) (*models.LoginChallenge, error) {
+	challenge, err := s.twoFactorRepo.AttemptChallenge(utils.HashToken(token), challengeMaxAttempts, time.Now().UTC())
+	if err != nil {
+		if err.Error() == "challenge not found" {
+			return nil, fmt.Errorf("invalid challenge")
+		}
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// CompleteChallenge помечает незавершенный вход завершенным
+func (s *TwoFactorService) CompleteChallenge(challenge *models.LoginChallenge) error {
+	ok, err := s.twoFactorRepo.ConsumeChallenge(challenge.ID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("invalid challenge")
+	}
+	return nil
+}
+
+// generateRecoveryCode создает код восстановления вида xxxx-xxxx-xxxx-xxxx
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate recovery code: %w", err)
+	}
+
+	raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf))
+	return raw[0:4] + "-" + raw[4:8] + "-" + raw[8:12] + "-" + raw[12:16], nil
+}
+
+// normalizeRecoveryCode убирает разделители и регистр, чтобы код можно было вводить в любом виде
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, char := range code {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"time"
+
+	"marketplace-api/internal/config"
+	"marketplace-api/internal/database/postgres"
+	"marketplace-api/internal/models"
+	"marketplace-api/internal/storage"
+)
+
+// uploadStorageTimeout ограничение на запись и удаление файла в хранилище
+const uploadStorageTimeout = 30 * time.Second
+
+// uploadExtensions расширения ключей файлов по MIME-типу
+var uploadExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type UploadService struct {
+	uploadRepo *postgres.UploadRepository
+	blobs      storage.BlobStore
+	config     config.UploadsConfig
+	log        *slog.Logger
+}
+
+func NewUploadService(uploadRepo *postgres.UploadRepository, blobs storage.BlobStore, config config.UploadsConfig, log *slog.Logger) *UploadService {
+	return &UploadService{
+		uploadRepo: uploadRepo,
+		blobs:      blobs,
+		config:     config,
+		log:        log,
+	}
+}
+
+type UploadServiceInterface interface {
+	Upload(userID int, file io.Reader) (*models.Upload, bool, error)
+	OpenFile(key, expires, signature string) (io.ReadCloser, string, error)
+}
+
+// Upload сохраняет файл пользователя. Тип файла определяется по содержимому, а не по имени и заголовкам.
+// Ключ файла в хранилище получается из SHA-256 содержимого, поэтому повторная загрузка того же файла
+// возвращает существующую загрузку; второй результат сообщает, создана ли новая
+func (s *UploadService) Upload(userID int, file io.Reader) (*models.Upload, bool, error) {
+	data, err := io.ReadAll(io.LimitReader(file, s.config.MaxSize+1))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read file: %w", err)
+	}
+	if int64(len(data)) > s.config.MaxSize {
+		return nil, false, fmt.Errorf("file too large")
+	}
+	if len(data) == 0 {
+		return nil, false, fmt.Errorf("file is empty")
+	}
+
+	upload, err := inspectUpload(data, s.config.AllowedTypes)
+	if err != nil {
+		return nil, false, err
+	}
+	upload.UserID = userID
+
+	existing, err := s.uploadRepo.GetUserUploadByHash(userID, upload.ContentHash)
+	if err == nil {
+		return s.withURL(existing, false)
+	}
+	if err.Error() != "upload not found" {
+		return nil, false, fmt.Errorf("failed to get upload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), uploadStorageTimeout)
+	defer cancel()
+
+	// файл записывается, пока строка загрузки не зафиксирована и ключ заблокирован, поэтому очистка
+	// не удалит его между записью и сохранением загрузки. Файл с тем же ключом мог загрузить
+	// другой пользователь; перезапись тем же содержимым безопасна
+	created, err := s.uploadRepo.CreateUpload(*upload, time.Now().UTC(), func() error {
+		if err := s.blobs.Put(ctx, upload.FileKey, data, upload.ContentType); err != nil {
+			return fmt.Errorf("failed to store file: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if err.Error() != "upload already exists" {
+			return nil, false, fmt.Errorf("failed to create upload: %w", err)
+		}
+		// тот же файл одновременно загружен параллельным запросом
+		existing, err := s.uploadRepo.GetUserUploadByHash(userID, upload.ContentHash)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get upload: %w", err)
+		}
+		return s.withURL(existing, false)
+	}
+
+	return s.withURL(created, true)
+}
+
+// OpenFile открывает файл по подписанной ссылке драйвера local и возвращает его MIME-тип
+func (s *UploadService) OpenFile(key, expires, signature string) (io.ReadCloser, string, error) {
+	if err := storage.VerifyURL(s.config.URLSecret, key, expires, signature, time.Now()); err != nil {
+		return nil, "", err
+	}
+
+	file, err := s.blobs.Get(context.Background(), key)
+	if err != nil {
+		if err.Error() == "blob not found" {
+			return nil, "", fmt.Errorf("file not found")
+		}
+		return nil, "", fmt.Errorf("failed to open file: %w", err)
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return file, contentType, nil
+}
+
+// CheckFileKeys проверяет, что все ключи файлов принадлежат загрузкам пользователя
+func (s *UploadService) CheckFileKeys(userID int, fileKeys []string) error {
+	if len(fileKeys) == 0 {
+		return nil
+	}
+
+	unique := slices.Compact(slices.Sorted(slices.Values(fileKeys)))
+
+	count, err := s.uploadRepo.CountUserFileKeys(userID, unique)
+	if err != nil {
+		return fmt.Errorf("failed to check image files: %w", err)
+	}
+	if count != len(unique) {
+		return fmt.Errorf("image file not found")
+	}
+
+	return nil
+}
+
+// ListUserUploads возвращает загрузки пользователя с подписанными ссылками на файлы
+func (s *UploadService) ListUserUploads(userID int) ([]models.Upload, error) {
+	uploads, err := s.uploadRepo.ListUserUploads(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range uploads {
+		if uploads[i].URL, err = s.FileURL(uploads[i].FileKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return uploads, nil
+}
+
+// FileURL возвращает подписанную ссылку на скачивание файла
+func (s *UploadService) FileURL(fileKey string) (string, error) {
+	url, err := s.blobs.SignedURL(fileKey, s.config.URLTTL)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign file URL: %w", err)
+	}
+	return url, nil
+}
+
+// Run периодически удаляет неиспользуемые загрузки, пока не отменен ctx
+func (s *UploadService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.cleanupOrphans(ctx); err != nil {
+				s.log.Error("Failed to clean up orphaned uploads", "error", err)
+			}
+		}
+	}
+}
+
+// cleanupOrphans удаляет загрузки старше OrphanTTL, которые не используются в объявлениях владельца,
+// и файлы, на которые после этого не ссылается ни одна загрузка
+func (s *UploadService) cleanupOrphans(ctx context.Context) error {
+	deleted, err := s.uploadRepo.DeleteOrphanedUploads(time.Now().UTC().Add(-s.config.OrphanTTL), s.fileDeleter(ctx))
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		s.log.Info("Orphaned uploads removed", "files", deleted)
+	}
+
+	return nil
+}
+
+// fileDeleter возвращает функцию удаления файла из хранилища для репозиториев. Строка загрузки к этому
+// моменту уже удалена, поэтому ошибка хранилища только логируется: файл останется в хранилище без ссылок
+func (s *UploadService) fileDeleter(ctx context.Context) func(key string) {
+	return func(key string) {
+		deleteCtx, cancel := context.WithTimeout(ctx, uploadStorageTimeout)
+		defer cancel()
+
+		if err := s.blobs.Delete(deleteCtx, key); err != nil {
+			s.log.Error("Failed to delete file", "key", key, "error", err)
+		}
+	}
+}
+
+// withURL заполняет подписанную ссылку на файл загрузки
+func (s *UploadService) withURL(upload *models.Upload, created bool) (*models.Upload, bool, error) {
+	url, err := s.FileURL(upload.FileKey)
+	if err != nil {
+		return nil, false, err
+	}
+	upload.URL = url
+	return upload, created, nil
+}
+
+// inspectUpload определяет MIME-тип файла по содержимому, размеры изображения и ключ файла в хранилище
+func inspectUpload(data []byte, allowedTypes []string) (*models.Upload, error) {
+	contentType := http.DetectContentType(data)
+	if !slices.Contains(allowedTypes, contentType) {
+		return nil, fmt.Errorf("unsupported file type")
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	upload := &models.Upload{
+		ContentHash: hash,
+		FileKey:     hash[:2] + "/" + hash + uploadExtensions[contentType],
+		ContentType: contentType,
+		Size:        int64(len(data)),
+	}
+
+	// размеры читаются из заголовка без декодирования изображения; для форматов без декодера они не заполняются
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	switch {
+	case err == nil:
+		if imageConfig.Width <= 0 || imageConfig.Height <= 0 {
+			return nil, fmt.Errorf("invalid image file")
+		}
+		upload.Width = &imageConfig.Width
+		upload.Height = &imageConfig.Height
+	case !errors.Is(err, image.ErrFormat):
+		return nil, fmt.Errorf("invalid image file")
+	}
+
+	return upload, nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testAllowedTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+func TestInspectUpload(t *testing.T) {
+	var pngData bytes.Buffer
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(0, 0, color.White)
+	require.NoError(t, png.Encode(&pngData, img))
+
+	t.Run("png", func(t *testing.T) {
+		upload, err := inspectUpload(pngData.Bytes(), testAllowedTypes)
+		require.NoError(t, err)
+
+		assert.Equal(t, "image/png", upload.ContentType)
+		assert.Len(t, upload.ContentHash, 64)
+		assert.Equal(t, upload.ContentHash[:2]+"/"+upload.ContentHash+".png", upload.FileKey)
+		assert.Equal(t, int64(pngData.Len()), upload.Size)
+		require.NotNil(t, upload.Width)
+		require.NotNil(t, upload.Height)
+		assert.Equal(t, 3, *upload.Width)
+		assert.Equal(t, 2, *upload.Height)
+	})
+
+	t.Run("same content same key", func(t *testing.T) {
+		first, err := inspectUpload(pngData.Bytes(), testAllowedTypes)
+		require.NoError(t, err)
+		second, err := inspectUpload(bytes.Clone(pngData.Bytes()), testAllowedTypes)
+		require.NoError(t, err)
+		assert.Equal(t, first.FileKey, second.FileKey)
+	})
+
+	t.Run("webp without decoder", func(t *testing.T) {
+		webp := append([]byte("RIFF\x24\x00\x00\x00WEBPVP8 "), make([]byte, 32)...)
+		upload, err := inspectUpload(webp, testAllowedTypes)
+		require.NoError(t, err)
+		assert.Equal(t, "image/webp", upload.ContentType)
+		assert.True(t, strings.HasSuffix(upload.FileKey, ".webp"))
+		assert.Nil(t, upload.Width)
+	})
+
+	t.Run("type not allowed", func(t *testing.T) {
+		_, err := inspectUpload(pngData.Bytes(), []string{"image/jpeg"})
+		assert.EqualError(t, err, "unsupported file type")
+	})
+
+	t.Run("html renamed to png", func(t *testing.T) {
+		_, err := inspectUpload([]byte("<html><script>alert(1)</script></html>"), testAllowedTypes)
+		assert.EqualError(t, err, "unsupported file type")
+	})
+
+	t.Run("truncated png", func(t *testing.T) {
+		_, err := inspectUpload(pngData.Bytes()[:20], testAllowedTypes)
+		assert.EqualError(t, err, "invalid image file")
+	})
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testURLSecret = "secret"
+
+func TestLocalStore_PutGetDelete(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "http://localhost:8080/", testURLSecret)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "ab/abcdef.png", []byte("first"), "image/png"))
+	require.NoError(t, store.Put(ctx, "ab/abcdef.png", []byte("second"), "image/png"))
+
+	reader, err := store.Get(ctx, "ab/abcdef.png")
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+
+	require.NoError(t, store.Delete(ctx, "ab/abcdef.png"))
+	// повторное удаление не считается ошибкой
+	require.NoError(t, store.Delete(ctx, "ab/abcdef.png"))
+
+	_, err = store.Get(ctx, "ab/abcdef.png")
+	require.EqualError(t, err, "blob not found")
+}
+
+func TestLocalStore_InvalidKey(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "http://localhost:8080", testURLSecret)
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../secret.png", "/etc/passwd", "ab/../../secret.png"} {
+		t.Run(key, func(t *testing.T) {
+			assert.EqualError(t, store.Put(context.Background(), key, []byte("data"), "image/png"), "invalid blob key")
+			_, err := store.Get(context.Background(), key)
+			assert.EqualError(t, err, "invalid blob key")
+		})
+	}
+}
+
+func TestLocalStore_SignedURL(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "http://localhost:8080/", testURLSecret)
+	require.NoError(t, err)
+
+	signedURL, err := store.SignedURL("ab/abcdef.png", time.Hour)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(signedURL)
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/api/uploads/files/ab/abcdef.png", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+
+	key := strings.TrimPrefix(parsed.Path, LocalFilesPath)
+	expires := parsed.Query().Get("expires")
+	signature := parsed.Query().Get("signature")
+
+	tests := []struct {
+		name      string
+		secret    string
+		key       string
+		expires   string
+		signature string
+		now       time.Time
+		wantErr   string
+	}{
+		{name: "valid", secret: testURLSecret, key: key, expires: expires, signature: signature, now: time.Now()},
+		{name: "expired", secret: testURLSecret, key: key, expires: expires, signature: signature, now: time.Now().Add(2 * time.Hour), wantErr: "link expired"},
+		{name: "other key", secret: testURLSecret, key: "ab/other.png", expires: expires, signature: signature, now: time.Now(), wantErr: "invalid signature"},
+		{name: "extended expiry", secret: testURLSecret, key: key, expires: expires + "0", signature: signature, now: time.Now(), wantErr: "invalid signature"},
+		{name: "other secret", secret: "other", key: key, expires: expires, signature: signature, now: time.Now(), wantErr: "invalid signature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyURL(tt.secret, tt.key, tt.expires, tt.signature, tt.now)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// Package s3test запускает локальную замену S3-совместимого хранилища (в духе MinIO) для тестов.
+//
+// Сервер хранит объекты одного бакета в памяти и поддерживает PutObject, GetObject и DeleteObject
+// в стиле пути /{bucket}/{key}. Подписи AWS Signature Version 4 — в заголовке Authorization
+// и в presigned-ссылках — проверяются собственной реализацией, независимой от проверяемого клиента.
+package s3test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	algorithm       = "AWS4-HMAC-SHA256"
+	dateFormat      = "20060102T150405Z"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// Object сохраненный объект
+type Object struct {
+	Data        []byte
+	ContentType string
+}
+
+// Server локальное S3-совместимое хранилище
+type Server struct {
+	Server    *httptest.Server
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+
+	mu      sync.Mutex
+	objects map[string]Object
+}
+
+// NewServer запускает хранилище с одним бакетом. Остановить его нужно вызовом Close
+func NewServer(region, bucket, accessKey, secretKey string) *Server {
+	s := &Server{
+		Region:    region,
+		Bucket:    bucket,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		objects:   make(map[string]Object),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// URL адрес хранилища для S3_ENDPOINT
+func (s *Server) URL() string {
+	return s.Server.URL
+}
+
+// Close останавливает хранилище
+func (s *Server) Close() {
+	s.Server.Close()
+}
+
+// Object возвращает сохраненный объект по ключу
+func (s *Server) Object(key string) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	object, ok := s.objects[key]
+	return object, ok
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != s.Bucket {
+		writeError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	if key == "" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody")
+		return
+	}
+
+	if code := s.authenticate(r, body); code != "" {
+		writeError(w, http.StatusForbidden, code)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		s.objects[key] = Object{Data: body, ContentType: r.Header.Get("Content-Type")}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		object, ok := s.objects[key]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		if object.ContentType != "" {
+			w.Header().Set("Content-Type", object.ContentType)
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(object.Data)))
+		w.Write(object.Data)
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+// authenticate проверяет подпись запроса и возвращает код ошибки S3 или пустую строку
+func (s *Server) authenticate(r *http.Request, body []byte) string {
+	query := r.URL.Query()
+
+	var credential, signedHeaders, signature, amzDate, payloadHash string
+	if query.Get("X-Amz-Signature") != "" {
+		if query.Get("X-Amz-Algorithm") != algorithm {
+			return "AuthorizationQueryParametersError"
+		}
+		credential = query.Get("X-Amz-Credential")
+		signedHeaders = query.Get("X-Amz-SignedHeaders")
+		signature = query.Get("X-Amz-Signature")
+		amzDate = query.Get("X-Amz-Date")
+		payloadHash = unsignedPayload
+
+		signedAt, err := time.Parse(dateFormat, amzDate)
+		if err != nil {
+			return "AuthorizationQueryParametersError"
+		}
+		expires, err := strconv.Atoi(query.Get("X-Amz-Expires"))
+		if err != nil || expires <= 0 {
+			return "AuthorizationQueryParametersError"
+		}
+		if time.Now().After(signedAt.Add(time.Duration(expires) * time.Second)) {
+			return "AccessDenied"
+		}
+		query.Del("X-Amz-Signature")
+	} else {
+		params, ok := strings.CutPrefix(r.Header.Get("Authorization"), algorithm+" ")
+		if !ok {
+			return "AccessDenied"
+		}
+		for _, param := range strings.Split(params, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			switch name {
+			case "Credential":
+				credential = value
+			case "SignedHeaders":
+				signedHeaders = value
+			case "Signature":
+				signature = value
+			}
+		}
+		amzDate = r.Header.Get("X-Amz-Date")
+		payloadHash = r.Header.Get("X-Amz-Content-Sha256")
+
+		sum := sha256.Sum256(body)
+		if payloadHash != hex.EncodeToString(sum[:]) {
+			return "XAmzContentSHA256Mismatch"
+		}
+	}
+
+	scope := strings.SplitN(credential, "/", 2)
+	if len(scope) != 2 || scope[0] != s.AccessKey {
+		return "InvalidAccessKeyId"
+	}
+	if len(amzDate) < 8 || scope[1] != amzDate[:8]+"/"+s.Region+"/s3/aws4_request" {
+		return "AuthorizationHeaderMalformed"
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range strings.Split(signedHeaders, ";") {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		canonicalQuery(query),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := strings.Join([]string{algorithm, amzDate, scope[1], hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := []byte("AWS4" + s.SecretKey)
+	for _, part := range []string{amzDate[:8], s.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(hex.EncodeToString(hmacSHA256(key, stringToSign)))) {
+		return "SignatureDoesNotMatch"
+	}
+
+	return ""
+}
+
+func canonicalQuery(query url.Values) string {
+	var parts []string
+	for name, values := range query {
+		for _, value := range values {
+			parts = append(parts, encode(name)+"="+encode(value))
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "&")
+}
+
+func encode(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<Error><Code>%s</Code></Error>", code)
+}
@@ -0,0 +1,55 @@
+// Package storage хранит загруженные пользователями файлы.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"marketplace-api/internal/config"
+)
+
+// BlobStore хранилище файлов по ключу. Ключ — относительный путь из сегментов, разделенных "/"
+type BlobStore interface {
+	// Put сохраняет файл, перезаписывая существующий с тем же ключом
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get открывает файл; если файла нет, возвращает ошибку "blob not found"
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete удаляет файл; отсутствие файла ошибкой не считается
+	Delete(ctx context.Context, key string) error
+	// SignedURL возвращает ссылку на скачивание файла, действующую ttl
+	SignedURL(key string, ttl time.Duration) (string, error)
+}
+
+// NewBlobStore создает хранилище по настройкам: local или s3
+func NewBlobStore(cfg config.UploadsConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case "local":
+		return NewLocalStore(cfg.LocalDir, cfg.PublicURL, cfg.URLSecret)
+	case "s3":
+		return NewS3Store(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey)
+	default:
+		return nil, fmt.Errorf("unsupported upload driver: %s", cfg.Driver)
+	}
+}
+
+// validateKey запрещает пустые и абсолютные ключи и выход за пределы хранилища через ".."
+func validateKey(key string) error {
+	if key == "" || !filepath.IsLocal(filepath.FromSlash(key)) {
+		return fmt.Errorf("invalid blob key")
+	}
+	return nil
+}
+
+// escapeKey кодирует сегменты ключа для подстановки в путь URL
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -4,41 +4,136 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"marketplace-api/pkg/rbac"
 	"marketplace-api/pkg/utils"
 )
 
-// AuthMiddleware проверяет JWT токен и добавляет пользователя в контекст
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// TokenRevocationChecker проверяет, не отозван ли токен на стороне сервера
+type TokenRevocationChecker interface {
+	IsRevoked(claims *utils.Claims) (bool, error)
+}
+
+// SessionActivityRecorder отмечает использование access-токена сеанса
+type SessionActivityRecorder interface {
+	RecordSessionActivity(claims *utils.Claims)
+}
+
+// APIKeyAuthenticator проверяет персональные API-ключи
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(key string) (*utils.APIKeyClaims, error)
+}
+
+// AuthMiddleware проверяет JWT токен и добавляет пользователя в контекст.
+// Если передан apiKeys, вместо JWT принимается и персональный API-ключ;
+// права ключа на маршруте проверяет RequireScope. Без apiKeys API-ключи отклоняются.
+// Принятый access-токен отмечается в sessions, если он передан
+func AuthMiddleware(keys *utils.KeyRing, revocations TokenRevocationChecker, sessions SessionActivityRecorder, apiKeys APIKeyAuthenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
+		if c.GetHeader("Authorization") == "" {
 			utils.Unauthorized(c, "Authorization header required")
 			c.Abort()
 			return
 		}
 
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			utils.Unauthorized(c, "Bearer token required")
+		if !authenticate(c, keys, revocations, sessions, apiKeys) {
 			c.Abort()
 			return
 		}
 
-		token := strings.TrimPrefix(authHeader, "Bearer ")
+		c.Next()
+	}
+}
 
-		claims, err := utils.ValidateToken(token, jwtSecret)
-		if err != nil {
-			utils.Unauthorized(c, "Invalid token")
-			c.Abort()
+// OptionalAuthMiddleware пропускает анонимные запросы, но если передан токен,
+// проверяет его так же строго, как AuthMiddleware, и добавляет пользователя в контекст.
+// Используется на публичных маршрутах, ответы которых зависят от того, кто смотрит
+func OptionalAuthMiddleware(keys *utils.KeyRing, revocations TokenRevocationChecker, sessions SessionActivityRecorder, apiKeys APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
 			return
 		}
 
-		c.Set("user_id", claims.UserID)
-		c.Set("user_login", claims.Login)
+		if !authenticate(c, keys, revocations, sessions, apiKeys) {
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
 }
 
+// authenticate проверяет Bearer токен или API-ключ из заголовка Authorization и сохраняет claims в контексте.
+// При ошибке отправляет ответ и возвращает false
+func authenticate(c *gin.Context, keys *utils.KeyRing, revocations TokenRevocationChecker, sessions SessionActivityRecorder, apiKeys APIKeyAuthenticator) bool {
+	authHeader := c.GetHeader("Authorization")
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		utils.Unauthorized(c, "Bearer token required")
+		return false
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+
+	if utils.IsAPIKey(token) {
+		return authenticateAPIKey(c, token, apiKeys)
+	}
+
+	claims, err := utils.ValidateToken(token, keys)
+	if err != nil {
+		utils.Unauthorized(c, "Invalid token")
+		return false
+	}
+
+	if revocations != nil {
+		revoked, err := revocations.IsRevoked(claims)
+		if err != nil {
+			utils.InternalError(c, "Failed to verify token")
+			return false
+		}
+		if revoked {
+			utils.Unauthorized(c, "Token has been revoked")
+			return false
+		}
+	}
+
+	if sessions != nil {
+		sessions.RecordSessionActivity(claims)
+	}
+
+	c.Set("user_id", claims.UserID)
+	c.Set("user_login", claims.Login)
+	c.Set("user_role", rbac.Role(claims.Role))
+	c.Set("token_claims", claims)
+
+	return true
+}
+
+// authenticateAPIKey проверяет персональный API-ключ и сохраняет данные владельца в контексте
+func authenticateAPIKey(c *gin.Context, key string, apiKeys APIKeyAuthenticator) bool {
+	if apiKeys == nil {
+		utils.Forbidden(c, "API keys are not accepted for this endpoint")
+		return false
+	}
+
+	claims, err := apiKeys.AuthenticateAPIKey(key)
+	if err != nil {
+		if err.Error() == "invalid api key" {
+			utils.Unauthorized(c, "Invalid API key")
+			return false
+		}
+		utils.InternalError(c, "Failed to verify API key")
+		return false
+	}
+
+	c.Set("user_id", claims.UserID)
+	c.Set("user_login", claims.Login)
+	c.Set("user_role", rbac.Role(claims.Role))
+	c.Set("api_key_claims", claims)
+
+	return true
+}
+
 // GetUserID извлекает ID пользователя из контекста
 func GetUserID(c *gin.Context) (int, bool) {
 	userID, exists := c.Get("user_id")
@@ -49,3 +144,40 @@ func GetUserID(c *gin.Context) (int, bool) {
 	id, ok := userID.(int)
 	return id, ok
 }
+
+// GetUserRole извлекает роль пользователя из контекста.
+// Токены, выпущенные до появления ролей, считаются токенами обычного пользователя
+func GetUserRole(c *gin.Context) rbac.Role {
+	value, exists := c.Get("user_role")
+	if !exists {
+		return rbac.RoleUser
+	}
+
+	role, ok := value.(rbac.Role)
+	if !ok || !role.Valid() {
+		return rbac.RoleUser
+	}
+	return role
+}
+
+// GetTokenClaims извлекает claims проверенного токена из контекста
+func GetTokenClaims(c *gin.Context) (*utils.Claims, bool) {
+	value, exists := c.Get("token_claims")
+	if !exists {
+		return nil, false
+	}
+
+	claims, ok := value.(*utils.Claims)
+	return claims, ok
+}
+
+// GetAPIKeyClaims извлекает данные API-ключа, если запрос аутентифицирован ключом, а не JWT
+func GetAPIKeyClaims(c *gin.Context) (*utils.APIKeyClaims, bool) {
+	value, exists := c.Get("api_key_claims")
+	if !exists {
+		return nil, false
+	}
+
+	claims, ok := value.(*utils.APIKeyClaims)
+	return claims, ok
+}
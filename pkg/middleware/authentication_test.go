@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"marketplace-api/pkg/utils"
+)
+
+type revocationCheckerStub struct {
+	revoked bool
+}
+
+func (s revocationCheckerStub) IsRevoked(claims *utils.Claims) (bool, error) {
+	return s.revoked, nil
+}
+
+type sessionActivityStub struct {
+	recorded int
+}
+
+func (s *sessionActivityStub) RecordSessionActivity(claims *utils.Claims) {
+	s.recorded++
+}
+
+type apiKeyAuthenticatorStub struct{}
+
+func (apiKeyAuthenticatorStub) AuthenticateAPIKey(key string) (*utils.APIKeyClaims, error) {
+	switch key {
+	case "mk_valid":
+		return &utils.APIKeyClaims{KeyID: 7, UserID: 1, Login: "artificial00", Role: "user", Scopes: []string{"listings:read"}}, nil
+	case "mk_broken":
+		return nil, errors.New("database connection failed")
+	default:
+		return nil, errors.New("invalid api key")
+	}
+}
+
+func TestOptionalAuthMiddleware(t *testing.T) {
+	keys := utils.NewHMACKeyRing("secret")
+
+	validToken, err := utils.GenerateToken(keys, utils.Claims{UserID: 1, Login: "artificial00"}, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	expiredToken, err := utils.GenerateToken(keys, utils.Claims{UserID: 1, Login: "artificial00"}, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	testTable := []struct {
+		name                 string
+		authHeader           string
+		revoked              bool
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedActivity     int
+	}{
+		{
+			name:                 "Anonymous",
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"user_id":null}`,
+		},
+		{
+			name:                 "Valid token",
+			authHeader:           "Bearer " + validToken,
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"user_id":1}`,
+			expectedActivity:     1,
+		},
+		{
+			name:                 "Expired token",
+			authHeader:           "Bearer " + expiredToken,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"Invalid token"}`,
+		},
+		{
+			name:                 "Malformed token",
+			authHeader:           "Bearer not.a.token",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"Invalid token"}`,
+		},
+		{
+			name:                 "Not a bearer token",
+			authHeader:           "Basic dXNlcjpwYXNz",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"Bearer token required"}`,
+		},
+		{
+			name:                 "Revoked token",
+			authHeader:           "Bearer " + validToken,
+			revoked:              true,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"Token has been revoked"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			sessions := &sessionActivityStub{}
+			r.Use(OptionalAuthMiddleware(keys, revocationCheckerStub{revoked: testCase.revoked}, sessions, nil))
+			r.GET("/listings", func(c *gin.Context) {
+				if userID, exists := GetUserID(c); exists {
+					c.JSON(http.StatusOK, gin.H{"user_id": userID})
+					return
+				}
+				c.JSON(http.StatusOK, gin.H{"user_id": nil})
+			})
+
+			ctx.Request, _ = http.NewRequest("GET", "/listings", nil)
+			if testCase.authHeader != "" {
+				ctx.Request.Header.Set("Authorization", testCase.authHeader)
+			}
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+			assert.Equal(t, testCase.expectedActivity, sessions.recorded)
+		})
+	}
+}
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	keys := utils.NewHMACKeyRing("secret")
+
+	validToken, err := utils.GenerateToken(keys, utils.Claims{UserID: 1, Login: "artificial00"}, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	testTable := []struct {
+		name                 string
+		authHeader           string
+		apiKeys              APIKeyAuthenticator
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Valid API key",
+			authHeader:           "Bearer mk_valid",
+			apiKeys:              apiKeyAuthenticatorStub{},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"user_id":1,"api_key_id":7}`,
+		},
+		{
+			name:                 "JWT is still accepted",
+			authHeader:           "Bearer " + validToken,
+			apiKeys:              apiKeyAuthenticatorStub{},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"user_id":1,"api_key_id":null}`,
+		},
+		{
+			name:                 "Unknown API key",
+			authHeader:           "Bearer mk_unknown",
+			apiKeys:              apiKeyAuthenticatorStub{},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"unauthorized", "message":"Invalid API key"}`,
+		},
+		{
+			name:                 "Verification failure",
+			authHeader:           "Bearer mk_broken",
+			apiKeys:              apiKeyAuthenticatorStub{},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal_error", "message":"Failed to verify API key"}`,
+		},
+		{
+			name:                 "API keys not accepted",
+			authHeader:           "Bearer mk_valid",
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: `{"error":"forbidden", "message":"API keys are not accepted for this endpoint"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(AuthMiddleware(keys, revocationCheckerStub{}, nil, testCase.apiKeys))
+			r.GET("/listings/my", func(c *gin.Context) {
+				userID, _ := GetUserID(c)
+				if claims, ok := GetAPIKeyClaims(c); ok {
+					c.JSON(http.StatusOK, gin.H{"user_id": userID, "api_key_id": claims.KeyID})
+					return
+				}
+				c.JSON(http.StatusOK, gin.H{"user_id": userID, "api_key_id": nil})
+			})
+
+			ctx.Request, _ = http.NewRequest("GET", "/listings/my", nil)
+			ctx.Request.Header.Set("Authorization", testCase.authHeader)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.JSONEq(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"marketplace-api/pkg/rbac"
+	"marketplace-api/pkg/utils"
+)
+
+// RequirePermission пропускает запрос, только если у роли пользователя есть право permission.
+// Должен стоять после AuthMiddleware
+func RequirePermission(permission rbac.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := GetUserID(c); !exists {
+			utils.Unauthorized(c, "User not found in context")
+			c.Abort()
+			return
+		}
+
+		if !GetUserRole(c).Can(permission) {
+			utils.Forbidden(c, "Insufficient permissions")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireScope пропускает запрос с API-ключом, только если ключу выдан scope.
+// Запросы с JWT не ограничиваются: токен входа дает все права владельца.
+// Должен стоять после AuthMiddleware или OptionalAuthMiddleware
+func RequireScope(scope rbac.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := GetAPIKeyClaims(c)
+		if ok && !claims.HasScope(string(scope)) {
+			utils.Forbidden(c, "API key does not have the required scope: "+string(scope))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
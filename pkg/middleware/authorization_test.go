@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"marketplace-api/pkg/rbac"
+	"marketplace-api/pkg/utils"
+)
+
+func TestRequirePermission(t *testing.T) {
+	testTable := []struct {
+		name               string
+		userID             interface{}
+		role               interface{}
+		permission         rbac.Permission
+		expectedStatusCode int
+	}{
+		{
+			name:               "Admin manages users",
+			userID:             1,
+			role:               rbac.RoleAdmin,
+			permission:         rbac.PermUsersManage,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Moderator cannot manage users",
+			userID:             1,
+			role:               rbac.RoleModerator,
+			permission:         rbac.PermUsersManage,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Moderator accesses admin routes",
+			userID:             1,
+			role:               rbac.RoleModerator,
+			permission:         rbac.PermAdminAccess,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Token without role is a regular user",
+			userID:             1,
+			permission:         rbac.PermAdminAccess,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Unknown role is a regular user",
+			userID:             1,
+			role:               rbac.Role("root"),
+			permission:         rbac.PermAdminAccess,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Anonymous",
+			permission:         rbac.PermAdminAccess,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.userID != nil {
+					ctx.Set("user_id", testCase.userID)
+				}
+				if testCase.role != nil {
+					ctx.Set("user_role", testCase.role)
+				}
+			})
+			r.Use(RequirePermission(testCase.permission))
+			r.GET("/admin", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			ctx.Request, _ = http.NewRequest("GET", "/admin", nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	testTable := []struct {
+		name               string
+		apiKey             *utils.APIKeyClaims
+		scope              rbac.Scope
+		expectedStatusCode int
+	}{
+		{
+			name:               "Key with scope",
+			apiKey:             &utils.APIKeyClaims{UserID: 1, Scopes: []string{"listings:read", "listings:write"}},
+			scope:              rbac.ScopeListingsWrite,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Key without scope",
+			apiKey:             &utils.APIKeyClaims{UserID: 1, Scopes: []string{"listings:read"}},
+			scope:              rbac.ScopeListingsWrite,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "JWT is not limited by scopes",
+			scope:              rbac.ScopeListingsWrite,
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			ctx, r := gin.CreateTestContext(w)
+
+			r.Use(func(ctx *gin.Context) {
+				ctx.Set("user_id", 1)
+				if testCase.apiKey != nil {
+					ctx.Set("api_key_claims", testCase.apiKey)
+				}
+			})
+			r.Use(RequireScope(testCase.scope))
+			r.POST("/listings", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			ctx.Request, _ = http.NewRequest("POST", "/listings", nil)
+
+			r.ServeHTTP(w, ctx.Request)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/pkg/utils"
+)
+
+// BodyLimit ограничивает размер тела запроса. Запрос с большим Content-Length отклоняется сразу с 413,
+// а чтение тела без Content-Length обрывается ошибкой *http.MaxBytesError, которую обрабатывает хендлер
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			utils.PayloadTooLarge(c, "Request body too large")
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+
+		c.Next()
+	}
+}
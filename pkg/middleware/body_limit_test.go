@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimit(t *testing.T) {
+	testTable := []struct {
+		name               string
+		body               string
+		contentLength      int64
+		expectedStatusCode int
+	}{
+		{
+			name:               "Within limit",
+			body:               "0123456789",
+			contentLength:      10,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Content-Length over limit",
+			body:               strings.Repeat("a", 20),
+			contentLength:      20,
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:               "Unknown length over limit",
+			body:               strings.Repeat("a", 20),
+			contentLength:      -1,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(BodyLimit(16))
+			r.POST("/", func(c *gin.Context) {
+				if _, err := io.ReadAll(c.Request.Body); err != nil {
+					c.Status(http.StatusBadRequest)
+					return
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(testCase.body))
+			req.ContentLength = testCase.contentLength
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/pkg/utils"
+)
+
+// ImpersonationAuditor записывает запросы, выполненные администратором от имени пользователя
+type ImpersonationAuditor interface {
+	StartRequest(claims *utils.Claims, method, path, ipAddress string, blocked bool) (int64, error)
+	FinishRequest(requestID int64, status int) error
+}
+
+// ImpersonationGuard записывает в журнал каждый запрос с токеном входа от имени пользователя
+// и отклоняет изменяющие запросы, если при выдаче токена они не были разрешены.
+// Если запрос не удалось записать, он не выполняется. Обычные токены и API-ключи пропускаются без изменений.
+// Должен стоять после AuthMiddleware или OptionalAuthMiddleware
+func ImpersonationGuard(auditor ImpersonationAuditor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := GetTokenClaims(c)
+		if !ok || !claims.IsImpersonation() {
+			c.Next()
+			return
+		}
+
+		blocked := !claims.ImpersonationWrite && !isSafeMethod(c.Request.Method)
+
+		requestID, err := auditor.StartRequest(claims, c.Request.Method, c.Request.URL.RequestURI(), c.ClientIP(), blocked)
+		if err != nil {
+			utils.InternalError(c, "Failed to record impersonated request")
+			c.Abort()
+			return
+		}
+
+		if blocked {
+			utils.Forbidden(c, "Write operations are not allowed while impersonating")
+			c.Abort()
+		} else {
+			c.Next()
+		}
+
+		// статус уже отправлен клиенту, ошибка записи не меняет ответ
+		_ = auditor.FinishRequest(requestID, c.Writer.Status())
+	}
+}
+
+// DenyImpersonation отклоняет запрос с токеном входа от имени пользователя независимо от разрешения на запись.
+// Ставится на маршруты, которые меняют учетные данные, выдают доступ или раскрывают все данные аккаунта.
+// Должен стоять после AuthMiddleware
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, ok := GetTokenClaims(c); ok && claims.IsImpersonation() {
+			utils.Forbidden(c, "This operation is not available while impersonating")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// isSafeMethod сообщает, что метод только читает данные
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"marketplace-api/pkg/utils"
+)
+
+type impersonatedRequestRecord struct {
+	method  string
+	path    string
+	blocked bool
+	status  int
+}
+
+type impersonationAuditorStub struct {
+	fail     bool
+	requests []impersonatedRequestRecord
+}
+
+func (s *impersonationAuditorStub) StartRequest(claims *utils.Claims, method, path, ipAddress string, blocked bool) (int64, error) {
+	if s.fail {
+		return 0, errors.New("database connection failed")
+	}
+	s.requests = append(s.requests, impersonatedRequestRecord{method: method, path: path, blocked: blocked})
+	return int64(len(s.requests)), nil
+}
+
+func (s *impersonationAuditorStub) FinishRequest(requestID int64, status int) error {
+	s.requests[requestID-1].status = status
+	return nil
+}
+
+func TestImpersonationGuard(t *testing.T) {
+	testTable := []struct {
+		name               string
+		claims             *utils.Claims
+		method             string
+		auditorFails       bool
+		expectedStatusCode int
+		expectedRequests   []impersonatedRequestRecord
+	}{
+		{
+			name:               "Regular token",
+			claims:             &utils.Claims{UserID: 1},
+			method:             "DELETE",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:               "Anonymous",
+			method:             "GET",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:               "Read-only impersonation reads",
+			claims:             &utils.Claims{UserID: 1, ImpersonatorID: 2},
+			method:             "GET",
+			expectedStatusCode: http.StatusNoContent,
+			expectedRequests: []impersonatedRequestRecord{
+				{method: "GET", path: "/resource?page=2", status: http.StatusNoContent},
+			},
+		},
+		{
+			name:               "Read-only impersonation writes",
+			claims:             &utils.Claims{UserID: 1, ImpersonatorID: 2},
+			method:             "DELETE",
+			expectedStatusCode: http.StatusForbidden,
+			expectedRequests: []impersonatedRequestRecord{
+				{method: "DELETE", path: "/resource?page=2", blocked: true, status: http.StatusForbidden},
+			},
+		},
+		{
+			name:               "Impersonation with write access",
+			claims:             &utils.Claims{UserID: 1, ImpersonatorID: 2, ImpersonationWrite: true},
+			method:             "DELETE",
+			expectedStatusCode: http.StatusNoContent,
+			expectedRequests: []impersonatedRequestRecord{
+				{method: "DELETE", path: "/resource?page=2", status: http.StatusNoContent},
+			},
+		},
+		{
+			name:               "Audit failure",
+			claims:             &utils.Claims{UserID: 1, ImpersonatorID: 2},
+			method:             "GET",
+			auditorFails:       true,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			auditor := &impersonationAuditorStub{fail: testCase.auditorFails}
+
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+
+			r.Use(func(ctx *gin.Context) {
+				if testCase.claims != nil {
+					ctx.Set("token_claims", testCase.claims)
+				}
+			})
+			r.Use(ImpersonationGuard(auditor))
+			r.Handle(testCase.method, "/resource", func(c *gin.Context) {
+				c.Status(http.StatusNoContent)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(testCase.method, "/resource?page=2", nil)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequests, auditor.requests)
+		})
+	}
+}
+
+func TestDenyImpersonation(t *testing.T) {
+	testTable := []struct {
+		name               string
+		claims             *utils.Claims
+		expectedStatusCode int
+	}{
+		{
+			name:               "Regular token",
+			claims:             &utils.Claims{UserID: 1},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Impersonation with write access",
+			claims:             &utils.Claims{UserID: 1, ImpersonatorID: 2, ImpersonationWrite: true},
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+
+			r.Use(func(ctx *gin.Context) {
+				ctx.Set("token_claims", testCase.claims)
+			})
+			r.PUT("/auth/password", DenyImpersonation(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PUT", "/auth/password", nil)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+		})
+	}
+}
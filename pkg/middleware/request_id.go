@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"marketplace-api/pkg/utils"
+)
+
+// RequestIDHeader заголовок с идентификатором запроса
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern допустимый идентификатор запроса от клиента или прокси
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID присваивает запросу идентификатор и возвращает его в заголовке X-Request-ID.
+// Идентификатор, переданный клиентом или прокси, сохраняется, если он допустимого вида, иначе создается новый
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			var err error
+			requestID, err = utils.GenerateRandomID()
+			if err != nil {
+				utils.InternalError(c, "Failed to generate request ID")
+				c.Abort()
+				return
+			}
+		}
+
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
+
+// GetRequestID извлекает идентификатор запроса из контекста
+func GetRequestID(c *gin.Context) string {
+	return c.GetString("request_id")
+}
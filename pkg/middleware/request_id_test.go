@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	testTable := []struct {
+		name          string
+		header        string
+		keepsProvided bool
+	}{
+		{
+			name:          "Provided by proxy",
+			header:        "3f2a9c1e-7b4d-4e8a-9c01-5d6e7f8a9b0c",
+			keepsProvided: true,
+		},
+		{
+			name: "Missing",
+		},
+		{
+			name:   "Invalid characters",
+			header: "abc\" OR 1=1",
+		},
+		{
+			name:   "Too long",
+			header: strings.Repeat("a", 65),
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+
+			var contextID string
+			r.Use(RequestID())
+			r.GET("/", func(c *gin.Context) {
+				contextID = GetRequestID(c)
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/", nil)
+			if testCase.header != "" {
+				req.Header.Set(RequestIDHeader, testCase.header)
+			}
+
+			r.ServeHTTP(w, req)
+
+			responseID := w.Header().Get(RequestIDHeader)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, contextID, responseID)
+			if testCase.keepsProvided {
+				assert.Equal(t, testCase.header, responseID)
+			} else {
+				assert.Len(t, responseID, 32)
+			}
+		})
+	}
+}
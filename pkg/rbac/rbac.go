@@ -0,0 +1,94 @@
+// Package rbac описывает роли пользователей и выдаваемые им права.
+package rbac
+
+// Role роль пользователя
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// Permission право на действие
+type Permission string
+
+const (
+	// PermListingsUpdateAny редактирование чужих объявлений
+	PermListingsUpdateAny Permission = "listings:update:any"
+	// PermListingsDeleteAny удаление чужих объявлений
+	PermListingsDeleteAny Permission = "listings:delete:any"
+	// PermAdminAccess доступ к маршрутам /api/admin
+	PermAdminAccess Permission = "admin:access"
+	// PermUsersManage просмотр пользователей и управление их ролями
+	PermUsersManage Permission = "users:manage"
+	// PermUsersImpersonate вход от имени пользователя
+	PermUsersImpersonate Permission = "users:impersonate"
+	// PermSecurityEventsRead поиск по журналу безопасности
+	PermSecurityEventsRead Permission = "security_events:read"
+	// PermCategoriesManage создание, изменение и удаление категорий объявлений
+	PermCategoriesManage Permission = "categories:manage"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleUser: {},
+	RoleModerator: {
+		PermListingsUpdateAny,
+		PermListingsDeleteAny,
+		PermAdminAccess,
+	},
+	RoleAdmin: {
+		PermListingsUpdateAny,
+		PermListingsDeleteAny,
+		PermAdminAccess,
+		PermUsersManage,
+		PermUsersImpersonate,
+		PermSecurityEventsRead,
+		PermCategoriesManage,
+	},
+}
+
+// Valid проверяет, что роль известна
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can проверяет, есть ли у роли право
+func (r Role) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanModifyOwned проверяет право на действие с ресурсом: владельцу оно разрешено всегда,
+// остальным — только при наличии права anyPermission
+func (r Role) CanModifyOwned(isOwner bool, anyPermission Permission) bool {
+	return isOwner || r.Can(anyPermission)
+}
+
+// Scope право персонального API-ключа. Ключ действует от имени владельца,
+// но только на маршрутах, для которых выдан соответствующий scope
+type Scope string
+
+const (
+	// ScopeListingsRead просмотр объявлений
+	ScopeListingsRead Scope = "listings:read"
+	// ScopeListingsWrite создание, изменение и удаление объявлений
+	ScopeListingsWrite Scope = "listings:write"
+)
+
+var knownScopes = []Scope{ScopeListingsRead, ScopeListingsWrite}
+
+// Valid проверяет, что scope известен
+func (s Scope) Valid() bool {
+	for _, known := range knownScopes {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
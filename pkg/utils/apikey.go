@@ -0,0 +1,47 @@
+package utils
+
+import "strings"
+
+const (
+	// APIKeyPrefix начало персонального API-ключа, по нему ключ отличается от JWT
+	APIKeyPrefix = "mk_"
+	// apiKeySize длина случайной части ключа в байтах
+	apiKeySize = 32
+	// apiKeyDisplayLength длина начала ключа, которое хранится открыто и показывается в списке ключей
+	apiKeyDisplayLength = 12
+)
+
+// APIKeyClaims данные проверенного API-ключа: владелец, его роль и выданные ключу scopes
+type APIKeyClaims struct {
+	KeyID  int
+	UserID int
+	Login  string
+	Role   string
+	Scopes []string
+}
+
+// HasScope проверяет, выдан ли ключу scope
+func (c *APIKeyClaims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateAPIKey создает новый API-ключ и возвращает его вместе с открытой частью для отображения
+func GenerateAPIKey() (key, displayPrefix string, err error) {
+	secret, err := GenerateOpaqueToken(apiKeySize)
+	if err != nil {
+		return "", "", err
+	}
+
+	key = APIKeyPrefix + secret
+	return key, key[:apiKeyDisplayLength], nil
+}
+
+// IsAPIKey проверяет, похожа ли строка на API-ключ, а не на JWT
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
@@ -10,32 +10,64 @@ import (
 type Claims struct {
 	UserID int    `json:"user_id"`
 	Login  string `json:"login"`
+	Role   string `json:"role,omitempty"`
+	// SessionID сеанс, в котором выпущен токен. Токены отозванного сеанса не принимаются
+	SessionID int `json:"sid,omitempty"`
+	// TokenVersion версия токенов пользователя на момент выпуска. Массовый отзыв увеличивает версию,
+	// и токены с меньшей версией не принимаются
+	TokenVersion int `json:"ver,omitempty"`
+	// ImpersonatorID администратор, который действует от имени UserID. У обычных токенов ноль
+	ImpersonatorID int `json:"imp_by,omitempty"`
+	// ImpersonationWrite разрешает изменяющие запросы от имени пользователя
+	ImpersonationWrite bool `json:"imp_write,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken создает JWT токен для пользователя
-func GenerateToken(userID int, login, secretKey string) (string, error) {
-	claims := &Claims{
-		UserID: userID,
-		Login:  login,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // токен действует 24 часа
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-		},
+// IsImpersonation сообщает, что токен выпущен администратору для работы от имени пользователя
+func (c *Claims) IsImpersonation() bool {
+	return c.ImpersonatorID != 0
+}
+
+// GenerateToken создает JWT токен доступа, действующий до expiresAt.
+// Из claims берутся данные пользователя, служебные поля (jti, iat, nbf, exp) заполняются здесь.
+// Токен подписывается текущим ключом из keys, kid ключа пишется в заголовок
+func GenerateToken(keys *KeyRing, claims Claims, expiresAt time.Time) (string, error) {
+	jti, err := GenerateRandomID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+
+	key, err := keys.CurrentKey(now)
+	if err != nil {
+		return "", err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secretKey))
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        jti,
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+	}
+
+	token := jwt.NewWithClaims(key.method(), &claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.signingKey())
 }
 
 // ValidateToken проверяет JWT токен и возвращает claims
-func ValidateToken(tokenString, secretKey string) (*Claims, error) {
+func ValidateToken(tokenString string, keys *KeyRing) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keys.Lookup(kid, time.Now())
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key: %q", kid)
+		}
+		if token.Method.Alg() != key.Algorithm {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(secretKey), nil
+		return key.verificationKey(), nil
 	})
 
 	if err != nil {
@@ -0,0 +1,324 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Поддерживаемые алгоритмы подписи токенов
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// hmacKeyID идентификатор единственного ключа в режиме HS256
+const hmacKeyID = "default"
+
+// rsaKeyBits размер генерируемых RSA ключей
+const rsaKeyBits = 2048
+
+// SigningKey ключ подписи токенов.
+// Ключ подписывает токены в промежутке [ActivatesAt, RetiresAt)
+// и принимается при проверке до ExpiresAt
+type SigningKey struct {
+	ID          string
+	Algorithm   string
+	Secret      []byte        // только для HS256
+	PrivateKey  crypto.Signer // для RS256 и EdDSA
+	ActivatesAt time.Time
+	RetiresAt   *time.Time
+	ExpiresAt   *time.Time
+}
+
+// canSign проверяет, может ли ключ подписывать токены в момент now
+func (k *SigningKey) canSign(now time.Time) bool {
+	if now.Before(k.ActivatesAt) {
+		return false
+	}
+	return k.RetiresAt == nil || now.Before(*k.RetiresAt)
+}
+
+// canVerify проверяет, принимаются ли подписанные ключом токены в момент now
+func (k *SigningKey) canVerify(now time.Time) bool {
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+func (k *SigningKey) method() jwt.SigningMethod {
+	switch k.Algorithm {
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256
+	case AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return jwt.SigningMethodHS256
+	}
+}
+
+func (k *SigningKey) signingKey() interface{} {
+	if k.Algorithm == AlgorithmHS256 {
+		return k.Secret
+	}
+	return k.PrivateKey
+}
+
+func (k *SigningKey) verificationKey() interface{} {
+	if k.Algorithm == AlgorithmHS256 {
+		return k.Secret
+	}
+	return k.PrivateKey.Public()
+}
+
+// JWK публичный ключ в формате RFC 7517
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+// JWKSet набор публичных ключей
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeyRing набор ключей подписи, идентифицируемых по kid
+type KeyRing struct {
+	mu   sync.RWMutex
+	keys map[string]*SigningKey
+}
+
+// NewKeyRing создает набор из переданных ключей
+func NewKeyRing(keys ...*SigningKey) *KeyRing {
+	ring := &KeyRing{}
+	ring.Replace(keys)
+	return ring
+}
+
+// NewHMACKeyRing создает набор из одного симметричного HS256 ключа
+func NewHMACKeyRing(secret string) *KeyRing {
+	return NewKeyRing(&SigningKey{
+		ID:        hmacKeyID,
+		Algorithm: AlgorithmHS256,
+		Secret:    []byte(secret),
+	})
+}
+
+// Replace атомарно заменяет содержимое набора
+func (r *KeyRing) Replace(keys []*SigningKey) {
+	byID := make(map[string]*SigningKey, len(keys))
+	for _, key := range keys {
+		byID[key.ID] = key
+	}
+
+	r.mu.Lock()
+	r.keys = byID
+	r.mu.Unlock()
+}
+
+// CurrentKey возвращает ключ, которым нужно подписывать новые токены
+func (r *KeyRing) CurrentKey(now time.Time) (*SigningKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var current *SigningKey
+	for _, key := range r.keys {
+		if !key.canSign(now) {
+			continue
+		}
+		if current == nil || key.ActivatesAt.After(current.ActivatesAt) {
+			current = key
+		}
+	}
+
+	if current == nil {
+		return nil, fmt.Errorf("no active signing key")
+	}
+
+	return current, nil
+}
+
+// Lookup возвращает ключ для проверки токена с указанным kid.
+// Токены без kid проверяются ключом HS256, если он есть
+func (r *KeyRing) Lookup(kid string, now time.Time) (*SigningKey, bool) {
+	if kid == "" {
+		kid = hmacKeyID
+	}
+
+	r.mu.RLock()
+	key, ok := r.keys[kid]
+	r.mu.RUnlock()
+
+	if !ok || !key.canVerify(now) {
+		return nil, false
+	}
+
+	return key, true
+}
+
+// JWKS возвращает публичные части асимметричных ключей, которые еще принимаются при проверке
+func (r *KeyRing) JWKS(now time.Time) JWKSet {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range r.keys {
+		if key.Algorithm == AlgorithmHS256 || !key.canVerify(now) {
+			continue
+		}
+
+		jwk := JWK{KeyID: key.ID, Use: "sig", Algorithm: key.Algorithm}
+		switch pub := key.PrivateKey.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].KeyID < set.Keys[j].KeyID
+	})
+
+	return set
+}
+
+// GenerateSigningKey создает новый асимметричный ключ подписи
+func GenerateSigningKey(algorithm string, activatesAt time.Time) (*SigningKey, error) {
+	var privateKey crypto.Signer
+	var err error
+
+	switch algorithm {
+	case AlgorithmRS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case AlgorithmEdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	kid, err := GenerateRandomID()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{
+		ID:          kid,
+		Algorithm:   algorithm,
+		PrivateKey:  privateKey,
+		ActivatesAt: activatesAt,
+	}, nil
+}
+
+// EncodePrivateKeyPEM кодирует закрытый ключ в PEM (PKCS #8)
+func EncodePrivateKeyPEM(key crypto.Signer) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// DecodePrivateKeyPEM разбирает закрытый ключ из PEM (PKCS #8)
+func DecodePrivateKeyPEM(data string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, fmt.Errorf("invalid PEM data")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type")
+	}
+
+	return signer, nil
+}
+
+// KeyEncryptionKeySize длина ключа шифрования закрытых ключей (AES-256)
+const KeyEncryptionKeySize = 32
+
+// EncryptPrivateKey шифрует закрытый ключ в PEM алгоритмом AES-256-GCM ключом kek (base64).
+// Идентификатор ключа подписи входит в дополнительные данные, поэтому зашифрованный ключ
+// нельзя подставить в запись с другим kid
+func EncryptPrivateKey(data, kid, kek string) (string, error) {
+	aead, err := newKeyCipher(kek)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(data), []byte(kid))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptPrivateKey расшифровывает закрытый ключ, зашифрованный EncryptPrivateKey
+func DecryptPrivateKey(data, kid, kek string) (string, error) {
+	aead, err := newKeyCipher(kek)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(data)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("invalid encrypted private key")
+	}
+
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(kid))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt private key: %w", err)
+	}
+
+	return string(plain), nil
+}
+
+func newKeyCipher(kek string) (cipher.AEAD, error) {
+	key, err := base64.StdEncoding.DecodeString(kek)
+	if err != nil || len(key) != KeyEncryptionKeySize {
+		return nil, fmt.Errorf("key encryption key must be %d base64-encoded bytes", KeyEncryptionKeySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyRing_TokenRoundTrip(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmRS256, AlgorithmEdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			key, err := GenerateSigningKey(algorithm, time.Now().Add(-time.Minute))
+			require.NoError(t, err)
+
+			encoded, err := EncodePrivateKeyPEM(key.PrivateKey)
+			require.NoError(t, err)
+			key.PrivateKey, err = DecodePrivateKeyPEM(encoded)
+			require.NoError(t, err)
+
+			keys := NewKeyRing(key)
+
+			token, err := GenerateToken(keys, Claims{UserID: 1, Login: "artificial00"}, time.Now().Add(time.Minute))
+			require.NoError(t, err)
+
+			claims, err := ValidateToken(token, keys)
+			require.NoError(t, err)
+			assert.Equal(t, 1, claims.UserID)
+			assert.NotEmpty(t, claims.ID)
+		})
+	}
+}
+
+func TestKeyRing_Rotation(t *testing.T) {
+	now := time.Now()
+	retiresAt := now.Add(time.Minute)
+	expiresAt := now.Add(time.Hour)
+
+	oldKey, err := GenerateSigningKey(AlgorithmEdDSA, now.Add(-time.Hour))
+	require.NoError(t, err)
+	oldKey.RetiresAt = &retiresAt
+	oldKey.ExpiresAt = &expiresAt
+
+	newKey, err := GenerateSigningKey(AlgorithmEdDSA, retiresAt)
+	require.NoError(t, err)
+
+	keys := NewKeyRing(oldKey, newKey)
+
+	current, err := keys.CurrentKey(now)
+	require.NoError(t, err)
+	assert.Equal(t, oldKey.ID, current.ID, "new key must not sign before activation")
+
+	current, err = keys.CurrentKey(retiresAt)
+	require.NoError(t, err)
+	assert.Equal(t, newKey.ID, current.ID)
+
+	_, ok := keys.Lookup(newKey.ID, now)
+	assert.True(t, ok, "published key must be accepted before activation")
+
+	_, ok = keys.Lookup(oldKey.ID, expiresAt.Add(-time.Second))
+	assert.True(t, ok, "retired key must be accepted during grace period")
+
+	_, ok = keys.Lookup(oldKey.ID, expiresAt)
+	assert.False(t, ok)
+}
+
+func TestValidateToken_RejectsAlgorithmMismatch(t *testing.T) {
+	key, err := GenerateSigningKey(AlgorithmRS256, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	keys := NewKeyRing(key)
+
+	claims := &Claims{
+		UserID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = key.ID
+	signed, err := token.SignedString([]byte("guessed-secret"))
+	require.NoError(t, err)
+
+	_, err = ValidateToken(signed, keys)
+	assert.Error(t, err)
+}
+
+func TestEncryptPrivateKey_RoundTrip(t *testing.T) {
+	kek := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, KeyEncryptionKeySize))
+	otherKEK := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, KeyEncryptionKeySize))
+
+	key, err := GenerateSigningKey(AlgorithmEdDSA, time.Now())
+	require.NoError(t, err)
+	pemData, err := EncodePrivateKeyPEM(key.PrivateKey)
+	require.NoError(t, err)
+
+	encrypted, err := EncryptPrivateKey(pemData, key.ID, kek)
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, "PRIVATE KEY")
+
+	decrypted, err := DecryptPrivateKey(encrypted, key.ID, kek)
+	require.NoError(t, err)
+	assert.Equal(t, pemData, decrypted)
+
+	// ключ, перенесенный в запись с другим kid, не расшифровывается
+	_, err = DecryptPrivateKey(encrypted, "other", kek)
+	assert.Error(t, err)
+
+	_, err = DecryptPrivateKey(encrypted, key.ID, otherKEK)
+	assert.Error(t, err)
+
+	_, err = EncryptPrivateKey(pemData, key.ID, "short")
+	assert.Error(t, err)
+}
@@ -0,0 +1,225 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Алгоритмы хеширования паролей
+const (
+	PasswordAlgorithmBcrypt   = "bcrypt"
+	PasswordAlgorithmArgon2id = "argon2id"
+)
+
+// Длины соли и ключа Argon2id в байтах (рекомендации RFC 9106)
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var (
+	// ErrPasswordMismatch пароль не совпадает с хешем. Так же проверяется пустой хеш:
+	// у пользователей, созданных через внешнего провайдера, и у удаленных пароля нет
+	ErrPasswordMismatch = errors.New("password mismatch")
+	// ErrUnknownPasswordHash хеш записан в формате, который не поддерживает ни один из алгоритмов
+	ErrUnknownPasswordHash = errors.New("unknown password hash format")
+)
+
+// phcEncoding кодирование соли и хеша в строке PHC: base64 без выравнивания
+var phcEncoding = base64.RawStdEncoding
+
+// PasswordHasher алгоритм хеширования паролей
+type PasswordHasher interface {
+	// Hash возвращает хеш пароля в формате PHC вместе с параметрами и солью
+	Hash(password string) (string, error)
+	// Verify сравнивает пароль с хешем, при несовпадении возвращает ErrPasswordMismatch
+	Verify(password, encoded string) error
+	// Supports сообщает, создан ли хеш этим алгоритмом
+	Supports(encoded string) bool
+	// NeedsRehash сообщает, что хеш этого алгоритма создан с параметрами, отличными от текущих
+	NeedsRehash(encoded string) bool
+}
+
+// BcryptHasher хеширует пароли bcrypt. Хеш записывается в стандартном виде $2a$<cost>$...,
+// который совместим с PHC и уже хранится у существующих пользователей
+type BcryptHasher struct {
+	cost int
+}
+
+func NewBcryptHasher(cost int) *BcryptHasher {
+	return &BcryptHasher{cost: cost}
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+func (h *BcryptHasher) Verify(password, encoded string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return ErrPasswordMismatch
+	}
+	if err != nil {
+		return fmt.Errorf("invalid bcrypt hash: %w", err)
+	}
+	return nil
+}
+
+func (h *BcryptHasher) Supports(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != h.cost
+}
+
+// Argon2idParams параметры Argon2id
+type Argon2idParams struct {
+	// Memory объем памяти в КиБ
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// Argon2idHasher хеширует пароли Argon2id. Хеш записывается в формате PHC:
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<соль>$<хеш>
+type Argon2idHasher struct {
+	params Argon2idParams
+}
+
+func NewArgon2idHasher(params Argon2idParams) *Argon2idHasher {
+	return &Argon2idHasher{params: params}
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, argon2KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.params.Memory,
+		h.params.Iterations,
+		h.params.Parallelism,
+		phcEncoding.EncodeToString(salt),
+		phcEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *Argon2idHasher) Verify(password, encoded string) error {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return err
+	}
+
+	actual := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+func (h *Argon2idHasher) Supports(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return params != h.params || len(salt) != argon2SaltLength || len(key) != argon2KeyLength
+}
+
+// decodeArgon2id разбирает хеш Argon2id в формате PHC
+func decodeArgon2id(encoded string) (Argon2idParams, []byte, []byte, error) {
+	var params Argon2idParams
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != PasswordAlgorithmArgon2id {
+		return params, nil, nil, fmt.Errorf("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2id version")
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+	if params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+		return params, nil, nil, fmt.Errorf("invalid argon2id parameters")
+	}
+
+	salt, err := phcEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	key, err := phcEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, fmt.Errorf("invalid argon2id hash value")
+	}
+
+	return params, salt, key, nil
+}
+
+// PasswordHashers хеширует новые пароли текущим алгоритмом и проверяет хеши всех известных алгоритмов,
+// чтобы при смене алгоритма или его параметров существующие пароли продолжали работать
+type PasswordHashers struct {
+	current PasswordHasher
+	legacy  []PasswordHasher
+}
+
+func NewPasswordHashers(current PasswordHasher, legacy ...PasswordHasher) *PasswordHashers {
+	return &PasswordHashers{
+		current: current,
+		legacy:  legacy,
+	}
+}
+
+// Hash хеширует пароль текущим алгоритмом
+func (h *PasswordHashers) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+// Verify проверяет пароль алгоритмом, которым создан хеш.
+// needsRehash сообщает, что пароль верен, но хеш создан другим алгоритмом или с устаревшими параметрами
+func (h *PasswordHashers) Verify(password, encoded string) (needsRehash bool, err error) {
+	if encoded == "" {
+		return false, ErrPasswordMismatch
+	}
+
+	if h.current.Supports(encoded) {
+		if err := h.current.Verify(password, encoded); err != nil {
+			return false, err
+		}
+		return h.current.NeedsRehash(encoded), nil
+	}
+
+	for _, hasher := range h.legacy {
+		if hasher.Supports(encoded) {
+			if err := hasher.Verify(password, encoded); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+	}
+
+	return false, ErrUnknownPasswordHash
+}
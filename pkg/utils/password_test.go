@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testArgon2idParams облегченные параметры, чтобы тесты не тратили 64 МиБ памяти на каждый хеш
+var testArgon2idParams = Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1}
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher := NewArgon2idHasher(testArgon2idParams)
+
+	hash, err := hasher.Hash("password123")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+	assert.True(t, hasher.Supports(hash))
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	assert.NoError(t, hasher.Verify("password123", hash))
+	assert.ErrorIs(t, hasher.Verify("password124", hash), ErrPasswordMismatch)
+
+	other, err := hasher.Hash("password123")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other, "salt must be random")
+
+	stronger := NewArgon2idHasher(Argon2idParams{Memory: 128, Iterations: 1, Parallelism: 1})
+	assert.True(t, stronger.NeedsRehash(hash))
+	assert.NoError(t, stronger.Verify("password123", hash), "parameters are taken from the hash")
+}
+
+func TestArgon2idHasher_ReferenceVector(t *testing.T) {
+	// хеш эталонной реализации: echo -n password | argon2 somesalt -id -t 2 -m 16 -p 1
+	const hash = "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+
+	hasher := NewArgon2idHasher(testArgon2idParams)
+	assert.NoError(t, hasher.Verify("password", hash))
+	assert.ErrorIs(t, hasher.Verify("Password", hash), ErrPasswordMismatch)
+}
+
+func TestArgon2idHasher_InvalidHash(t *testing.T) {
+	hasher := NewArgon2idHasher(testArgon2idParams)
+
+	for _, hash := range []string{
+		"$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ",
+		"$argon2id$v=16$m=64,t=1,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=0,t=1,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+	} {
+		err := hasher.Verify("password", hash)
+		assert.Error(t, err, hash)
+		assert.NotErrorIs(t, err, ErrPasswordMismatch, hash)
+		assert.True(t, hasher.NeedsRehash(hash), hash)
+	}
+}
+
+func TestBcryptHasher(t *testing.T) {
+	hasher := NewBcryptHasher(4)
+
+	hash, err := hasher.Hash("password123")
+	require.NoError(t, err)
+	assert.True(t, hasher.Supports(hash))
+	assert.False(t, hasher.NeedsRehash(hash))
+	assert.True(t, NewBcryptHasher(5).NeedsRehash(hash))
+
+	assert.NoError(t, hasher.Verify("password123", hash))
+	assert.ErrorIs(t, hasher.Verify("password124", hash), ErrPasswordMismatch)
+}
+
+func TestPasswordHashers_Verify(t *testing.T) {
+	bcryptHasher := NewBcryptHasher(4)
+	argon2idHasher := NewArgon2idHasher(testArgon2idParams)
+	hashers := NewPasswordHashers(argon2idHasher, bcryptHasher)
+
+	bcryptHash, err := bcryptHasher.Hash("password123")
+	require.NoError(t, err)
+	outdatedHash, err := NewArgon2idHasher(Argon2idParams{Memory: 32, Iterations: 1, Parallelism: 1}).Hash("password123")
+	require.NoError(t, err)
+	currentHash, err := hashers.Hash("password123")
+	require.NoError(t, err)
+	assert.True(t, argon2idHasher.Supports(currentHash))
+
+	tests := []struct {
+		name            string
+		password        string
+		hash            string
+		wantErr         error
+		wantNeedsRehash bool
+	}{
+		{name: "current algorithm", password: "password123", hash: currentHash},
+		{name: "legacy algorithm", password: "password123", hash: bcryptHash, wantNeedsRehash: true},
+		{name: "outdated parameters", password: "password123", hash: outdatedHash, wantNeedsRehash: true},
+		{name: "wrong password for legacy hash", password: "password124", hash: bcryptHash, wantErr: ErrPasswordMismatch},
+		{name: "wrong password", password: "password124", hash: currentHash, wantErr: ErrPasswordMismatch},
+		{name: "account without password", password: "", hash: "", wantErr: ErrPasswordMismatch},
+		{name: "unknown format", password: "password123", hash: "$scrypt$ln=15,r=8,p=1$c29tZXNhbHQ$aGFzaA", wantErr: ErrUnknownPasswordHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			needsRehash, err := hashers.Verify(tt.password, tt.hash)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNeedsRehash, needsRehash)
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package utils
 
 import (
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -9,6 +12,8 @@ import (
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message,omitempty"`
+	// Details подробности ошибки, например список нарушенных правил
+	Details interface{} `json:"details,omitempty"`
 }
 
 type SuccessResponse struct {
@@ -47,6 +52,15 @@ func BadRequest(c *gin.Context, message string) {
 	SendError(c, http.StatusBadRequest, "bad_request", message)
 }
 
+// BadRequestWithDetails отправляет ошибку 400 с подробностями, например списком нарушенных правил
+func BadRequestWithDetails(c *gin.Context, message string, details interface{}) {
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Error:   "bad_request",
+		Message: message,
+		Details: details,
+	})
+}
+
 // Unauthorized отправляет ошибку 401
 func Unauthorized(c *gin.Context, message string) {
 	SendError(c, http.StatusUnauthorized, "unauthorized", message)
@@ -69,3 +83,34 @@ func NotFound(c *gin.Context, message string) {
 func Forbidden(c *gin.Context, message string) {
 	SendError(c, http.StatusForbidden, "forbidden", message)
 }
+
+// BadGateway отправляет ошибку 502, когда не отвечает внешний сервис
+func BadGateway(c *gin.Context, message string) {
+	SendError(c, http.StatusBadGateway, "bad_gateway", message)
+}
+
+// TooManyRequests отправляет ошибку 429 с заголовком Retry-After в секундах
+func TooManyRequests(c *gin.Context, retryAfter time.Duration, message string) {
+	setRetryAfter(c, retryAfter)
+	SendError(c, http.StatusTooManyRequests, "too_many_requests", message)
+}
+
+// Locked отправляет ошибку 423 с заголовком Retry-After в секундах
+func Locked(c *gin.Context, retryAfter time.Duration, message string) {
+	setRetryAfter(c, retryAfter)
+	SendError(c, http.StatusLocked, "locked", message)
+}
+
+// PayloadTooLarge отправляет ошибку 413
+func PayloadTooLarge(c *gin.Context, message string) {
+	SendError(c, http.StatusRequestEntityTooLarge, "payload_too_large", message)
+}
+
+// UnsupportedMediaType отправляет ошибку 415
+func UnsupportedMediaType(c *gin.Context, message string) {
+	SendError(c, http.StatusUnsupportedMediaType, "unsupported_media_type", message)
+}
+
+func setRetryAfter(c *gin.Context, retryAfter time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+}
@@ -1,41 +1,29 @@
 package utils
 
 import (
+	"net/mail"
 	"net/url"
-	"regexp"
 	"strings"
-	"unicode"
 )
 
-// ValidateLogin проверяет корректность логина
-func ValidateLogin(login string) bool {
-	if len(login) < 3 || len(login) > 50 {
+// ValidateEmail проверяет, что строка — один адрес вида user@domain без имени и угловых скобок
+func ValidateEmail(email string) bool {
+	if len(email) > 254 {
 		return false
 	}
 
-	matched, _ := regexp.MatchString("^[a-zA-Z0-9_]+$", login)
-	return matched
-}
-
-// ValidatePassword проверяет корректность пароля
-func ValidatePassword(password string) bool {
-	if len(password) < 6 {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
 		return false
 	}
 
-	hasLetter := false
-	hasDigit := false
-
-	for _, char := range password {
-		if unicode.IsLetter(char) {
-			hasLetter = true
-		}
-		if unicode.IsDigit(char) {
-			hasDigit = true
-		}
-	}
+	at := strings.LastIndex(email, "@")
+	return at > 0 && strings.Contains(email[at+1:], ".")
+}
 
-	return hasLetter && hasDigit
+// NormalizeEmail приводит email к виду, в котором он хранится: без пробелов по краям и в нижнем регистре
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
 // ValidateURL валидирует URL